@@ -10,8 +10,9 @@ done
 
 echo "Database is ready!"
 
-echo "Running database migrations..."
-./main migrate
+# The server checks the schema itself and, with auto-migrate on, applies
+# pending migrations under an advisory lock so replicas don't race.
+export DB_AUTO_MIGRATE="${DB_AUTO_MIGRATE:-true}"
 
 echo "Starting application..."
 exec ./main
\ No newline at end of file
@@ -0,0 +1,172 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type Action string
+
+const (
+	// ActionCreateTeam targets the name of the team being created.
+	ActionCreateTeam Action = "team:create"
+	// ActionSetTeamMembers targets the name of an existing team whose members
+	// change.
+	ActionSetTeamMembers Action = "team:set_members"
+	// ActionSetUserActive targets the user being activated or deactivated.
+	ActionSetUserActive Action = "user:set_active"
+	// ActionSetRole targets the user whose role changes.
+	ActionSetRole Action = "user:set_role"
+	// ActionSetPassword targets the user whose password is set or reset.
+	ActionSetPassword Action = "user:set_password"
+	// ActionSetOnLeave targets the user who goes on or returns from leave.
+	ActionSetOnLeave Action = "user:set_on_leave"
+	// ActionSetNotifications targets the user whose preferences change.
+	ActionSetNotifications Action = "user:set_notifications"
+	// ActionCreatePR targets the user named as the author of the new pull
+	// request.
+	ActionCreatePR Action = "pr:create"
+	// ActionMergePR targets the pull request being merged.
+	ActionMergePR Action = "pr:merge"
+	// ActionReassignReviewer targets the pull request being changed.
+	ActionReassignReviewer Action = "pr:reassign"
+	// ActionManageAPITokens covers creating, listing and revoking API tokens.
+	ActionManageAPITokens Action = "api_tokens:manage"
+	// ActionRevokeSessions targets the user whose sessions are revoked.
+	ActionRevokeSessions Action = "user:revoke_sessions"
+	// ActionSyncTeams covers planning and applying a team roster sync.
+	ActionSyncTeams Action = "teams:sync"
+	// ActionProvisionSCIM covers every SCIM request from the identity provider.
+	ActionProvisionSCIM Action = "scim:provision"
+)
+
+// Authorizer decides whether an authenticated user may perform an action on a
+// resource. Handlers call it before executing the matching command.
+type Authorizer struct {
+	userRepo ports.UserRepository
+	prRepo   ports.PRRepository
+}
+
+func NewAuthorizer(userRepo ports.UserRepository, prRepo ports.PRRepository) *Authorizer {
+	return &Authorizer{
+		userRepo: userRepo,
+		prRepo:   prRepo,
+	}
+}
+
+// Authorize returns entities.ErrForbidden when the actor may not perform the
+// action. A missing target resource is reported with its not-found error.
+func (a *Authorizer) Authorize(ctx context.Context, actorID string, action Action, resourceID string) error {
+	actor, err := a.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("getting actor: %w", err)
+	}
+	if actor == nil || !actor.IsActive {
+		return entities.ErrForbidden
+	}
+	if actor.IsAdmin() {
+		return nil
+	}
+
+	switch action {
+	case ActionCreateTeam, ActionSetRole, ActionSetPassword, ActionManageAPITokens, ActionRevokeSessions, ActionSyncTeams, ActionProvisionSCIM:
+		return entities.ErrForbidden
+
+	case ActionSetTeamMembers:
+		if actor.LeadsTeam(resourceID) {
+			return nil
+		}
+
+	case ActionSetUserActive:
+		target, err := a.getUser(ctx, resourceID)
+		if err != nil {
+			return err
+		}
+		if actor.LeadsTeam(target.TeamName) {
+			return nil
+		}
+
+	case ActionSetOnLeave:
+		if actor.ID == resourceID {
+			return nil
+		}
+		target, err := a.getUser(ctx, resourceID)
+		if err != nil {
+			return err
+		}
+		if actor.LeadsTeam(target.TeamName) {
+			return nil
+		}
+
+	case ActionSetNotifications:
+		if actor.ID == resourceID {
+			return nil
+		}
+
+	case ActionCreatePR:
+		if actor.ID == resourceID {
+			return nil
+		}
+
+	case ActionMergePR:
+		pr, err := a.getPR(ctx, resourceID)
+		if err != nil {
+			return err
+		}
+		if pr.AuthorID == actor.ID {
+			return nil
+		}
+
+	case ActionReassignReviewer:
+		pr, err := a.getPR(ctx, resourceID)
+		if err != nil {
+			return err
+		}
+		if pr.AuthorID == actor.ID || isReviewer(pr, actor.ID) {
+			return nil
+		}
+		author, err := a.getUser(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
+		if actor.LeadsTeam(author.TeamName) {
+			return nil
+		}
+	}
+
+	return entities.ErrForbidden
+}
+
+func (a *Authorizer) getUser(ctx context.Context, id string) (*entities.User, error) {
+	user, err := a.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return nil, entities.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (a *Authorizer) getPR(ctx context.Context, id string) (*entities.PullRequest, error) {
+	pr, err := a.prRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting pr: %w", err)
+	}
+	if pr == nil {
+		return nil, entities.ErrPRNotFound
+	}
+	return pr, nil
+}
+
+func isReviewer(pr *entities.PullRequest, userID string) bool {
+	for _, reviewer := range pr.AssignedReviewers {
+		if reviewer == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package authz_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+func TestAuthorize(t *testing.T) {
+	ctx := context.Background()
+	userRepo := repositories.NewInMemoryUserRepository()
+	prRepo := repositories.NewInMemoryPRRepository(userRepo, repositories.NewInMemoryAssignmentDecisionRepository())
+
+	admin := entities.NewUser("admin", "root", "ops", true)
+	admin.SetRole(entities.RoleAdmin)
+	lead := entities.NewUser("lead", "lena", "backend", true)
+	lead.SetRole(entities.RoleTeamLead)
+	otherLead := entities.NewUser("other-lead", "oleg", "frontend", true)
+	otherLead.SetRole(entities.RoleTeamLead)
+	author := entities.NewUser("author", "alice", "backend", true)
+	reviewer := entities.NewUser("reviewer", "bob", "backend", true)
+	bystander := entities.NewUser("bystander", "carol", "backend", true)
+	inactiveAdmin := entities.NewUser("inactive-admin", "dave", "ops", false)
+	inactiveAdmin.SetRole(entities.RoleAdmin)
+
+	for _, u := range []*entities.User{admin, lead, otherLead, author, reviewer, bystander, inactiveAdmin} {
+		if err := userRepo.Save(ctx, u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+	if err := prRepo.Save(ctx, entities.NewPullRequest("pr-1", "Add search", "author", []string{"reviewer"})); err != nil {
+		t.Fatalf("failed to save pr: %v", err)
+	}
+
+	authorizer := authz.NewAuthorizer(userRepo, prRepo)
+
+	tests := []struct {
+		name        string
+		actorID     string
+		action      authz.Action
+		resourceID  string
+		expectedErr error
+	}{
+		{"admin creates team", "admin", authz.ActionCreateTeam, "new-team", nil},
+		{"team lead cannot create team", "lead", authz.ActionCreateTeam, "new-team", entities.ErrForbidden},
+		{"lead sets own team members", "lead", authz.ActionSetTeamMembers, "backend", nil},
+		{"lead cannot set other team members", "other-lead", authz.ActionSetTeamMembers, "backend", entities.ErrForbidden},
+		{"member cannot set team members", "author", authz.ActionSetTeamMembers, "backend", entities.ErrForbidden},
+		{"inactive admin is denied", "inactive-admin", authz.ActionCreateTeam, "new-team", entities.ErrForbidden},
+		{"unknown actor is denied", "ghost", authz.ActionMergePR, "pr-1", entities.ErrForbidden},
+		{"lead deactivates own team member", "lead", authz.ActionSetUserActive, "bystander", nil},
+		{"lead cannot deactivate other team member", "other-lead", authz.ActionSetUserActive, "bystander", entities.ErrForbidden},
+		{"member cannot deactivate colleague", "author", authz.ActionSetUserActive, "bystander", entities.ErrForbidden},
+		{"deactivating unknown user", "lead", authz.ActionSetUserActive, "ghost", entities.ErrUserNotFound},
+		{"only admin sets roles", "lead", authz.ActionSetRole, "author", entities.ErrForbidden},
+		{"only admin sets passwords", "author", authz.ActionSetPassword, "author", entities.ErrForbidden},
+		{"only admin provisions via scim", "lead", authz.ActionProvisionSCIM, "", entities.ErrForbidden},
+		{"user goes on leave", "author", authz.ActionSetOnLeave, "author", nil},
+		{"lead puts own team member on leave", "lead", authz.ActionSetOnLeave, "bystander", nil},
+		{"member cannot put colleague on leave", "author", authz.ActionSetOnLeave, "bystander", entities.ErrForbidden},
+		{"user sets own notifications", "author", authz.ActionSetNotifications, "author", nil},
+		{"user cannot set others notifications", "author", authz.ActionSetNotifications, "reviewer", entities.ErrForbidden},
+		{"author creates own pr", "author", authz.ActionCreatePR, "author", nil},
+		{"admin creates pr for author", "admin", authz.ActionCreatePR, "author", nil},
+		{"lead cannot create pr for member", "lead", authz.ActionCreatePR, "author", entities.ErrForbidden},
+		{"author merges", "author", authz.ActionMergePR, "pr-1", nil},
+		{"admin merges", "admin", authz.ActionMergePR, "pr-1", nil},
+		{"reviewer cannot merge", "reviewer", authz.ActionMergePR, "pr-1", entities.ErrForbidden},
+		{"lead cannot merge", "lead", authz.ActionMergePR, "pr-1", entities.ErrForbidden},
+		{"merging unknown pr", "author", authz.ActionMergePR, "pr-404", entities.ErrPRNotFound},
+		{"reviewer reassigns", "reviewer", authz.ActionReassignReviewer, "pr-1", nil},
+		{"author's team lead reassigns", "lead", authz.ActionReassignReviewer, "pr-1", nil},
+		{"other team lead cannot reassign", "other-lead", authz.ActionReassignReviewer, "pr-1", entities.ErrForbidden},
+		{"bystander cannot reassign", "bystander", authz.ActionReassignReviewer, "pr-1", entities.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(ctx, tt.actorID, tt.action, tt.resourceID)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
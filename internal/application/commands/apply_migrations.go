@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
@@ -19,14 +20,47 @@ func NewApplyMigrationsCommand(migrationRepo ports.MigrationRepository, logger *
 	}
 }
 
-func (c *ApplyMigrationsCommand) Execute(ctx context.Context) error {
-	c.logger.Info("Executing command: applying migrations")
+// Execute migrates the schema to target, or to the latest bundled version
+// when target is nil. A target below the current version reverts migrations.
+// With dryRun set nothing is applied; the planned steps are returned either
+// way.
+func (c *ApplyMigrationsCommand) Execute(ctx context.Context, target *uint, dryRun bool) (_ []ports.MigrationStep, err error) {
+	ctx, span := startSpan(ctx, "ApplyMigrationsCommand")
+	defer finishSpan(span, &err)
 
-	if err := c.migrationRepo.ApplyMigrations(ctx); err != nil {
-		c.logger.Error("Migration failed", "error", err)
-		return err
+	version := uint(0)
+	if target != nil {
+		version = *target
+	} else {
+		version, err = c.migrationRepo.GetLatestVersion(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting latest migration: %w", err)
+		}
 	}
 
-	c.logger.Info("Command executed: migrations applied successfully")
-	return nil
+	return runMigrationPlan(ctx, c.migrationRepo, c.logger, version, dryRun)
+}
+
+// runMigrationPlan is shared by migrate and rollback so both honour dry runs
+// the same way.
+func runMigrationPlan(
+	ctx context.Context,
+	migrationRepo ports.MigrationRepository,
+	logger *slog.Logger,
+	version uint,
+	dryRun bool,
+) ([]ports.MigrationStep, error) {
+	steps, err := migrationRepo.Plan(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("planning migrations: %w", err)
+	}
+	if dryRun || len(steps) == 0 {
+		return steps, nil
+	}
+
+	logger.Info("Migrating schema", "target_version", version, "steps", len(steps))
+	if err := migrationRepo.MigrateTo(ctx, version); err != nil {
+		return nil, fmt.Errorf("migrating to version %d: %w", version, err)
+	}
+	return steps, nil
 }
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/logging"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+type AuthenticateCommand struct {
+	userRepo       ports.UserRepository
+	credentialRepo ports.CredentialRepository
+	hasher         ports.PasswordHasher
+	policy         entities.LockoutPolicy
+	clock          services.Clock
+	logger         *slog.Logger
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewAuthenticateCommand(
+	userRepo ports.UserRepository,
+	credentialRepo ports.CredentialRepository,
+	hasher ports.PasswordHasher,
+	policy entities.LockoutPolicy,
+	clock services.Clock,
+	logger *slog.Logger,
+) *AuthenticateCommand {
+	return &AuthenticateCommand{
+		userRepo:       userRepo,
+		credentialRepo: credentialRepo,
+		hasher:         hasher,
+		policy:         policy,
+		clock:          clock,
+		logger:         logger,
+	}
+}
+
+// Execute verifies a user's password. Unknown users, inactive users and users
+// without a password all fail with ErrInvalidCredentials so callers cannot
+// tell them apart.
+func (c *AuthenticateCommand) Execute(ctx context.Context, userID, password string) (_ *entities.User, err error) {
+	ctx, span := startSpan(ctx, "AuthenticateCommand", attribute.String("user.id", userID))
+	defer finishSpan(span, &err)
+
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil || !user.IsActive {
+		c.compareDummy(password)
+		return nil, entities.ErrInvalidCredentials
+	}
+
+	credentials, err := c.credentialRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting credentials: %w", err)
+	}
+	if credentials == nil {
+		c.compareDummy(password)
+		return nil, entities.ErrInvalidCredentials
+	}
+
+	now := c.clock.Now().UTC()
+	if credentials.IsLocked(now) {
+		return nil, entities.ErrAccountLocked
+	}
+
+	if err := c.hasher.Compare(credentials.PasswordHash, password); err != nil {
+		if !errors.Is(err, entities.ErrInvalidCredentials) {
+			return nil, fmt.Errorf("comparing password: %w", err)
+		}
+
+		credentials, err = c.credentialRepo.RecordFailedLogin(ctx, userID, c.policy, now)
+		if err != nil {
+			return nil, fmt.Errorf("recording failed login: %w", err)
+		}
+		if credentials != nil && credentials.IsLocked(now) {
+			logging.FromContext(ctx, c.logger).Warn("account locked after repeated failed logins", "user_id", userID)
+		}
+		return nil, entities.ErrInvalidCredentials
+	}
+
+	if credentials.FailedAttempts > 0 || credentials.LockedUntil != nil {
+		if err := c.credentialRepo.ResetFailedLogins(ctx, userID); err != nil {
+			return nil, fmt.Errorf("resetting failed logins: %w", err)
+		}
+	}
+
+	return user, nil
+}
+
+// compareDummy spends as long as a real password check, so that failing
+// early does not reveal whether the user exists.
+func (c *AuthenticateCommand) compareDummy(password string) {
+	c.dummyHashOnce.Do(func() {
+		c.dummyHash, _ = c.hasher.Hash("dummy-password")
+	})
+	if c.dummyHash != "" {
+		_ = c.hasher.Compare(c.dummyHash, password)
+	}
+}
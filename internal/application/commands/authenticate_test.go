@@ -0,0 +1,93 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// countingHasher counts password comparisons.
+type countingHasher struct {
+	ports.PasswordHasher
+	compares atomic.Int32
+}
+
+func newCountingHasher() *countingHasher {
+	return &countingHasher{PasswordHasher: security.NewBcryptHasher(bcrypt.MinCost)}
+}
+
+func (h *countingHasher) Compare(hash, password string) error {
+	h.compares.Add(1)
+	return h.PasswordHasher.Compare(hash, password)
+}
+
+func newAuthenticateCommand(t *testing.T, hasher *countingHasher, policy entities.LockoutPolicy) *commands.AuthenticateCommand {
+	t.Helper()
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clock := services.NewRealClock()
+	store := repositories.NewInMemoryStore()
+
+	users := []*entities.User{
+		entities.NewUser("u1", "alice", "backend", true),
+		entities.NewUser("u2", "bob", "backend", false),
+	}
+	if err := store.Teams.Save(ctx, entities.NewTeam("backend", users)); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	credentialRepo := repositories.NewInMemoryCredentialRepository()
+	setPassword := commands.NewSetPasswordCommand(store.Users, credentialRepo, hasher, clock)
+	for _, id := range []string{"u1", "u2"} {
+		if err := setPassword.Execute(ctx, id, "correct-horse-battery"); err != nil {
+			t.Fatalf("failed to set password: %v", err)
+		}
+	}
+	return commands.NewAuthenticateCommand(store.Users, credentialRepo, hasher, policy, clock, logger)
+}
+
+func TestAuthenticateComparesPasswordForUnknownUsers(t *testing.T) {
+	hasher := newCountingHasher()
+	cmd := newAuthenticateCommand(t, hasher, entities.LockoutPolicy{})
+
+	for _, id := range []string{"ghost", "u2"} {
+		before := hasher.compares.Load()
+		if _, err := cmd.Execute(context.Background(), id, "correct-horse-battery"); !errors.Is(err, entities.ErrInvalidCredentials) {
+			t.Errorf("%s: expected ErrInvalidCredentials, got %v", id, err)
+		}
+		if hasher.compares.Load() == before {
+			t.Errorf("%s: expected a password comparison so the response time matches a real user", id)
+		}
+	}
+}
+
+func TestAuthenticateCountsParallelFailures(t *testing.T) {
+	policy := entities.LockoutPolicy{MaxFailedAttempts: 3, LockoutDuration: time.Minute}
+	cmd := newAuthenticateCommand(t, newCountingHasher(), policy)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd.Execute(context.Background(), "u1", "wrong-password")
+		}()
+	}
+	wg.Wait()
+
+	if _, err := cmd.Execute(context.Background(), "u1", "correct-horse-battery"); !errors.Is(err, entities.ErrAccountLocked) {
+		t.Errorf("expected parallel failures to lock the account, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+type CreateAPITokenCommand struct {
+	tokenRepo ports.APITokenRepository
+	userRepo  ports.UserRepository
+	clock     services.Clock
+}
+
+func NewCreateAPITokenCommand(
+	tokenRepo ports.APITokenRepository,
+	userRepo ports.UserRepository,
+	clock services.Clock,
+) *CreateAPITokenCommand {
+	return &CreateAPITokenCommand{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		clock:     clock,
+	}
+}
+
+// Execute issues a token acting as userID. It returns the stored token and the
+// raw secret, which is never persisted and can only be shown once. A zero ttl
+// creates a token that does not expire.
+func (c *CreateAPITokenCommand) Execute(ctx context.Context, userID, name string, scopes []entities.Scope, ttl time.Duration) (_ *entities.APIToken, _ string, err error) {
+	ctx, span := startSpan(ctx, "CreateAPITokenCommand", attribute.String("user.id", userID))
+	defer finishSpan(span, &err)
+
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, "", entities.ErrInvalidScope
+		}
+	}
+
+	exists, err := c.userRepo.ExistsByID(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("checking user exists: %w", err)
+	}
+	if !exists {
+		return nil, "", entities.ErrUserNotFound
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	raw := entities.APITokenPrefix + secret
+
+	now := c.clock.Now().UTC()
+	var expiresAt *time.Time
+	if ttl > 0 {
+		expiry := now.Add(ttl)
+		expiresAt = &expiry
+	}
+
+	token := entities.NewAPIToken(id, name, userID, entities.HashAPIToken(raw), scopes, now, expiresAt)
+	if err := c.tokenRepo.Save(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("saving api token: %w", err)
+	}
+
+	return token, raw, nil
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating random value: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
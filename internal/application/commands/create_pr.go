@@ -3,6 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
@@ -14,6 +17,9 @@ type CreatePRCommand struct {
 	userRepo          ports.UserRepository
 	prRepo            ports.PRRepository
 	assignmentService *services.ReviewerAssignmentService
+	notifier          ports.Notifier
+	metrics           ports.AssignmentMetrics
+	logger            *slog.Logger
 }
 
 func NewCreatePRCommand(
@@ -21,16 +27,25 @@ func NewCreatePRCommand(
 	userRepo ports.UserRepository,
 	prRepo ports.PRRepository,
 	assignmentService *services.ReviewerAssignmentService,
+	notifier ports.Notifier,
+	metrics ports.AssignmentMetrics,
+	logger *slog.Logger,
 ) *CreatePRCommand {
 	return &CreatePRCommand{
 		teamRepo:          teamRepo,
 		userRepo:          userRepo,
 		prRepo:            prRepo,
 		assignmentService: assignmentService,
+		notifier:          notifier,
+		metrics:           metrics,
+		logger:            logger,
 	}
 }
 
-func (c *CreatePRCommand) Execute(ctx context.Context, prID, prName, authorID string) (*entities.PullRequest, error) {
+func (c *CreatePRCommand) Execute(ctx context.Context, prID, prName, authorID string, labels ...string) (_ *entities.PullRequest, err error) {
+	ctx, span := startSpan(ctx, "CreatePRCommand", attribute.String("pr.id", prID), attribute.String("pr.author_id", authorID))
+	defer finishSpan(span, &err)
+
 	exists, err := c.prRepo.ExistsByID(ctx, prID)
 	if err != nil {
 		return nil, fmt.Errorf("checking pr exists: %w", err)
@@ -55,17 +70,29 @@ func (c *CreatePRCommand) Execute(ctx context.Context, prID, prName, authorID st
 		return nil, entities.ErrTeamNotFound
 	}
 
-	reviewers, err := c.assignmentService.SelectReviewers(team, authorID)
+	openReviews, err := c.prRepo.CountOpenReviews(ctx, team.Name)
+	if err != nil {
+		return nil, fmt.Errorf("counting open reviews: %w", err)
+	}
+
+	decision, err := c.assignmentService.SelectReviewers(team, authorID, openReviews)
 	if err != nil {
+		recordNoCandidate(c.metrics, "create", team.Name, err)
 		return nil, fmt.Errorf("selecting reviewers: %w", err)
 	}
 
-	pr := entities.NewPullRequest(prID, prName, authorID, reviewers)
+	pr := entities.NewPullRequest(prID, prName, authorID, decision.Reviewers)
+	pr.SetLabels(labels)
 
-	err = c.prRepo.Save(ctx, pr)
+	decision.PullRequestID = pr.ID
+	decision.CreatedAt = pr.CreatedAt
+	err = c.prRepo.SaveWithDecision(ctx, pr, decision)
 	if err != nil {
 		return nil, fmt.Errorf("saving pr: %w", err)
 	}
+	recordReviewersAssigned(c.metrics, c.assignmentService.Strategy(), team.Name, len(pr.AssignedReviewers))
+
+	notifyReviewersAssigned(ctx, c.notifier, c.logger, pr, team, pr.AssignedReviewers)
 
 	return pr, nil
 }
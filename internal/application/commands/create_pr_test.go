@@ -0,0 +1,72 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+type failingNotifier struct {
+	calls int
+}
+
+func (n *failingNotifier) NotifyReviewersAssigned(ctx context.Context, event ports.ReviewersAssignedEvent) error {
+	n.calls++
+	return errors.New("webhook unavailable")
+}
+
+func TestCreatePRIgnoresNotificationFailure(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	userRepo := repositories.NewInMemoryUserRepository()
+	teamRepo := repositories.NewInMemoryTeamRepository(userRepo)
+	decisionRepo := repositories.NewInMemoryAssignmentDecisionRepository()
+	prRepo := repositories.NewInMemoryPRRepository(userRepo, decisionRepo)
+
+	members := []*entities.User{
+		entities.NewUser("user1", "alice", "backend", true),
+		entities.NewUser("user2", "bob", "backend", true),
+	}
+	if err := teamRepo.Save(ctx, entities.NewTeam("backend", members)); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	for _, m := range members {
+		if err := userRepo.Save(ctx, m); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+
+	notifier := &failingNotifier{}
+	cmd := commands.NewCreatePRCommand(
+		teamRepo, userRepo, prRepo,
+		services.NewReviewerAssignmentService(nil, 0),
+		notifier, nil, logger,
+	)
+
+	pr, err := cmd.Execute(ctx, "pr-1", "Add search", "user1")
+	if err != nil {
+		t.Fatalf("expected notification failure to be ignored, got %v", err)
+	}
+	if notifier.calls != 1 {
+		t.Errorf("expected 1 notification, got %d", notifier.calls)
+	}
+
+	saved, _ := prRepo.GetByID(ctx, pr.ID)
+	if saved == nil {
+		t.Error("expected pull request to be persisted")
+	}
+
+	decisions, _ := decisionRepo.ListByPullRequest(ctx, pr.ID)
+	if len(decisions) != 1 || decisions[0].Kind != entities.AssignmentCreate || len(decisions[0].Reviewers) != 1 {
+		t.Errorf("expected the assignment decision to be recorded, got %+v", decisions)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
@@ -20,7 +22,10 @@ func NewCreateTeamCommand(teamRepo ports.TeamRepository, userRepo ports.UserRepo
 	}
 }
 
-func (c *CreateTeamCommand) Execute(ctx context.Context, teamName string, members []*entities.User) (*entities.Team, error) {
+func (c *CreateTeamCommand) Execute(ctx context.Context, teamName, chatWebhookURL string, members []*entities.User) (_ *entities.Team, err error) {
+	ctx, span := startSpan(ctx, "CreateTeamCommand", attribute.String("team.name", teamName), attribute.Int("team.members", len(members)))
+	defer finishSpan(span, &err)
+
 	exists, err := c.teamRepo.ExistsByName(ctx, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("checking team exists: %w", err)
@@ -29,7 +34,12 @@ func (c *CreateTeamCommand) Execute(ctx context.Context, teamName string, member
 		return nil, entities.ErrTeamExists
 	}
 
+	if err = c.keepStoredFields(ctx, members); err != nil {
+		return nil, err
+	}
+
 	team := entities.NewTeam(teamName, members)
+	team.ChatWebhookURL = chatWebhookURL
 
 	err = c.teamRepo.Save(ctx, team)
 	if err != nil {
@@ -45,3 +55,26 @@ func (c *CreateTeamCommand) Execute(ctx context.Context, teamName string, member
 
 	return team, nil
 }
+
+// keepStoredFields copies onto members that already exist what a team
+// request does not carry, so that adding them to a team changes only their
+// team, username and active state.
+func (c *CreateTeamCommand) keepStoredFields(ctx context.Context, members []*entities.User) error {
+	for _, member := range members {
+		stored, err := c.userRepo.GetByID(ctx, member.ID)
+		if err != nil {
+			return fmt.Errorf("getting user %s: %w", member.ID, err)
+		}
+		if stored == nil {
+			continue
+		}
+		member.Role = stored.Role
+		member.OnLeave = stored.OnLeave
+		if member.ChatHandle == "" {
+			member.ChatHandle = stored.ChatHandle
+		}
+		member.Email = stored.Email
+		member.Notifications = stored.Notifications
+	}
+	return nil
+}
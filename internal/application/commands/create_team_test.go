@@ -0,0 +1,48 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+func TestCreateTeamKeepsExistingUsers(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewInMemoryStore()
+
+	alice := entities.NewUser("u1", "alice", "backend", true)
+	alice.SetNotificationPreferences("alice@example.com", entities.NotificationPreferences{EmailOnAssignment: true})
+	alice.ChatHandle = "@alice"
+	admin := entities.NewUser("u3", "carol", "backend", true)
+	admin.SetRole(entities.RoleAdmin)
+	if err := store.Teams.Save(ctx, entities.NewTeam("backend", []*entities.User{alice, admin})); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	cmd := commands.NewCreateTeamCommand(store.Teams, store.Users)
+	members := []*entities.User{
+		entities.NewUser("u1", "alice.s", "platform", false),
+		entities.NewUser("u2", "bob", "platform", true),
+		entities.NewUser("u3", "carol", "platform", true),
+	}
+	if _, err := cmd.Execute(ctx, "platform", "", members); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := store.Users.GetByID(ctx, "u1")
+	if got.TeamName != "platform" || got.Username != "alice.s" || got.IsActive {
+		t.Errorf("expected team, username and active state to change, got %+v", got)
+	}
+	if got.Email != "alice@example.com" || !got.Notifications.EmailOnAssignment || got.ChatHandle != "@alice" {
+		t.Errorf("expected email, notification preferences and chat handle to be kept, got %+v", got)
+	}
+	if carol, _ := store.Users.GetByID(ctx, "u3"); carol.Role != entities.RoleAdmin || carol.TeamName != "platform" {
+		t.Errorf("expected the admin to keep their role, got %+v", carol)
+	}
+	if bob, _ := store.Users.GetByID(ctx, "u2"); bob == nil || bob.TeamName != "platform" {
+		t.Errorf("expected u2 to be created, got %+v", bob)
+	}
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type DeleteTeamCommand struct {
+	teamRepo    ports.TeamRepository
+	syncRepo    ports.TeamSyncRepository
+	defaultTeam string
+	logger      *slog.Logger
+}
+
+func NewDeleteTeamCommand(
+	teamRepo ports.TeamRepository,
+	syncRepo ports.TeamSyncRepository,
+	defaultTeam string,
+	logger *slog.Logger,
+) *DeleteTeamCommand {
+	return &DeleteTeamCommand{
+		teamRepo:    teamRepo,
+		syncRepo:    syncRepo,
+		defaultTeam: defaultTeam,
+		logger:      logger,
+	}
+}
+
+// Execute deletes a team after moving its members to the default team. The
+// default team itself can only be deleted once it is empty.
+func (c *DeleteTeamCommand) Execute(ctx context.Context, teamName string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteTeamCommand", attribute.String("team.name", teamName))
+	defer finishSpan(span, &err)
+
+	team, err := c.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("getting team: %w", err)
+	}
+	if team == nil {
+		return entities.ErrTeamNotFound
+	}
+	if teamName == c.defaultTeam && len(team.Members) > 0 {
+		return entities.ErrTeamNotEmpty
+	}
+
+	plan := &entities.TeamSyncPlan{DeleteTeams: []string{teamName}}
+	for _, member := range team.Members {
+		member.TeamName = c.defaultTeam
+		plan.Move = append(plan.Move, entities.UserMove{User: member, FromTeam: teamName})
+	}
+	if len(plan.Move) > 0 {
+		if err := planTeam(ctx, c.teamRepo, plan, c.defaultTeam); err != nil {
+			return err
+		}
+	}
+
+	if err := c.syncRepo.Apply(ctx, plan); err != nil {
+		return fmt.Errorf("deleting team: %w", err)
+	}
+
+	c.logger.Info("Team deleted", "team_name", teamName, "moved", len(plan.Move))
+	return nil
+}
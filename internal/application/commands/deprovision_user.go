@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type DeprovisionUserCommand struct {
+	userRepo ports.UserRepository
+	syncRepo ports.TeamSyncRepository
+	revoker  ports.SessionRevoker
+	logger   *slog.Logger
+}
+
+func NewDeprovisionUserCommand(
+	userRepo ports.UserRepository,
+	syncRepo ports.TeamSyncRepository,
+	revoker ports.SessionRevoker,
+	logger *slog.Logger,
+) *DeprovisionUserCommand {
+	return &DeprovisionUserCommand{
+		userRepo: userRepo,
+		syncRepo: syncRepo,
+		revoker:  revoker,
+		logger:   logger,
+	}
+}
+
+// Execute deletes a user removed by the identity provider. A user with pull
+// request history is deactivated instead so the history is kept; either way
+// their sessions are revoked.
+func (c *DeprovisionUserCommand) Execute(ctx context.Context, userID string) (err error) {
+	ctx, span := startSpan(ctx, "DeprovisionUserCommand", attribute.String("user.id", userID))
+	defer finishSpan(span, &err)
+
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return entities.ErrUserNotFound
+	}
+
+	// Revoke first so that a failure leaves the user in place for a retry.
+	// The stored cut-off is kept after the user row is deleted, which also
+	// rejects old tokens if the same ID is provisioned again.
+	if err := c.revoker.RevokeUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("revoking sessions: %w", err)
+	}
+	if err := c.syncRepo.Apply(ctx, &entities.TeamSyncPlan{Remove: []*entities.User{user}}); err != nil {
+		return fmt.Errorf("removing user: %w", err)
+	}
+
+	c.logger.Info("User deprovisioned", "user_id", userID)
+	return nil
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+var (
+	ErrSchemaDirty  = errors.New("schema is dirty")
+	ErrSchemaBehind = errors.New("schema is behind the binary")
+	ErrSchemaAhead  = errors.New("schema is newer than the binary")
+)
+
+type EnsureSchemaCommand struct {
+	migrationRepo ports.MigrationRepository
+	lockTimeout   time.Duration
+	logger        *slog.Logger
+}
+
+func NewEnsureSchemaCommand(migrationRepo ports.MigrationRepository, lockTimeout time.Duration, logger *slog.Logger) *EnsureSchemaCommand {
+	return &EnsureSchemaCommand{
+		migrationRepo: migrationRepo,
+		lockTimeout:   lockTimeout,
+		logger:        logger,
+	}
+}
+
+// Execute checks that the schema matches the newest bundled migration before
+// the server starts. An older schema is upgraded when autoMigrate is set and
+// rejected otherwise; a newer or dirty one is always rejected. The check runs
+// under the schema lock so that replicas starting together migrate only once.
+// Only waiting for the lock is bounded by the lock timeout: cancelling a
+// migration halfway would leave the schema dirty.
+func (c *EnsureSchemaCommand) Execute(ctx context.Context, autoMigrate bool) (err error) {
+	ctx, span := startSpan(ctx, "EnsureSchemaCommand")
+	defer finishSpan(span, &err)
+
+	lockCtx, cancel := context.WithTimeout(ctx, c.lockTimeout)
+	unlock, err := c.migrationRepo.Lock(lockCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("locking schema: %w", err)
+	}
+	defer unlock()
+
+	current, dirty, err := c.migrationRepo.GetCurrentVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("getting current version: %w", err)
+	}
+	latest, err := c.migrationRepo.GetLatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("getting latest migration: %w", err)
+	}
+
+	switch {
+	case dirty:
+		return fmt.Errorf("%w: migration %d failed, fix it and run force", ErrSchemaDirty, current)
+	case current > latest:
+		return fmt.Errorf("%w: database is at version %d, binary knows up to %d", ErrSchemaAhead, current, latest)
+	case current == latest:
+		return nil
+	case !autoMigrate:
+		return fmt.Errorf("%w: database is at version %d, binary expects %d; run migrate", ErrSchemaBehind, current, latest)
+	}
+
+	c.logger.Info("Migrating schema on startup", "from_version", current, "to_version", latest)
+	if err := c.migrationRepo.MigrateTo(ctx, latest); err != nil {
+		return fmt.Errorf("migrating to version %d: %w", latest, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+)
+
+func TestEnsureSchema(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name        string
+		current     uint
+		dirty       bool
+		autoMigrate bool
+		wantErr     error
+		wantMigrate bool
+	}{
+		{name: "up to date", current: 7},
+		{name: "behind", current: 5, wantErr: commands.ErrSchemaBehind},
+		{name: "behind with auto-migrate", current: 5, autoMigrate: true, wantMigrate: true},
+		{name: "empty database with auto-migrate", current: 0, autoMigrate: true, wantMigrate: true},
+		{name: "ahead", current: 9, autoMigrate: true, wantErr: commands.ErrSchemaAhead},
+		{name: "dirty", current: 5, dirty: true, autoMigrate: true, wantErr: commands.ErrSchemaDirty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeMigrationRepo(tt.current)
+			repo.dirty = tt.dirty
+
+			err := commands.NewEnsureSchemaCommand(repo, time.Minute, logger).Execute(context.Background(), tt.autoMigrate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !repo.locked || !repo.unlocked {
+				t.Errorf("expected the check to hold and release the schema lock")
+			}
+			if !repo.lockDeadline || repo.migrateDeadline {
+				t.Errorf("expected only the lock wait to be time-limited")
+			}
+
+			migrated := len(repo.migratedTo) == 1 && repo.migratedTo[0] == 7
+			if migrated != tt.wantMigrate || (!tt.wantMigrate && len(repo.migratedTo) != 0) {
+				t.Errorf("expected migrate=%v, got migrations to %v", tt.wantMigrate, repo.migratedTo)
+			}
+		})
+	}
+}
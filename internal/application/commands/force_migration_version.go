@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+type ForceMigrationVersionCommand struct {
+	migrationRepo ports.MigrationRepository
+	logger        *slog.Logger
+}
+
+func NewForceMigrationVersionCommand(migrationRepo ports.MigrationRepository, logger *slog.Logger) *ForceMigrationVersionCommand {
+	return &ForceMigrationVersionCommand{
+		migrationRepo: migrationRepo,
+		logger:        logger,
+	}
+}
+
+// Execute marks version as cleanly applied after a failed migration has been
+// repaired by hand.
+func (c *ForceMigrationVersionCommand) Execute(ctx context.Context, version int) (err error) {
+	ctx, span := startSpan(ctx, "ForceMigrationVersionCommand")
+	defer finishSpan(span, &err)
+
+	if err := c.migrationRepo.Force(ctx, version); err != nil {
+		return fmt.Errorf("forcing version %d: %w", version, err)
+	}
+	c.logger.Info("Migration version forced", "version", version)
+	return nil
+}
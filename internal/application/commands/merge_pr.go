@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
@@ -16,7 +18,10 @@ func NewMergePRCommand(prRepo ports.PRRepository) *MergePRCommand {
 	return &MergePRCommand{prRepo: prRepo}
 }
 
-func (c *MergePRCommand) Execute(ctx context.Context, prID string) (*entities.PullRequest, error) {
+func (c *MergePRCommand) Execute(ctx context.Context, prID string) (_ *entities.PullRequest, err error) {
+	ctx, span := startSpan(ctx, "MergePRCommand", attribute.String("pr.id", prID))
+	defer finishSpan(span, &err)
+
 	pr, err := c.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, fmt.Errorf("getting pr: %w", err)
@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// recordNoCandidate counts selections that failed for lack of an eligible
+// reviewer; other errors are not recorded. metrics may be nil.
+func recordNoCandidate(metrics ports.AssignmentMetrics, operation, team string, err error) {
+	if metrics != nil && errors.Is(err, entities.ErrNoCandidateFound) {
+		metrics.NoCandidateFound(operation, team)
+	}
+}
+
+func recordReviewersAssigned(metrics ports.AssignmentMetrics, strategy, team string, count int) {
+	if metrics != nil && count > 0 {
+		metrics.ReviewersAssigned(strategy, team, count)
+	}
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/logging"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// notifyReviewersAssigned is best-effort: the assignment is already persisted,
+// so a delivery failure is logged and never returned to the caller.
+func notifyReviewersAssigned(
+	ctx context.Context,
+	notifier ports.Notifier,
+	logger *slog.Logger,
+	pr *entities.PullRequest,
+	team *entities.Team,
+	reviewerIDs []string,
+) {
+	if notifier == nil {
+		return
+	}
+
+	reviewers := make([]*entities.User, 0, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		for _, member := range team.Members {
+			if member.ID == id {
+				reviewers = append(reviewers, member)
+				break
+			}
+		}
+	}
+
+	event := ports.ReviewersAssignedEvent{
+		PR:        pr,
+		Team:      team,
+		Reviewers: reviewers,
+	}
+
+	if err := notifier.NotifyReviewersAssigned(ctx, event); err != nil {
+		logging.FromContext(ctx, logger).Warn("failed to deliver reviewer notification",
+			"pull_request_id", pr.ID,
+			"error", err,
+		)
+	}
+}
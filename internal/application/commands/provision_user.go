@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type ProvisionUserCommand struct {
+	userRepo    ports.UserRepository
+	teamRepo    ports.TeamRepository
+	syncRepo    ports.TeamSyncRepository
+	defaultTeam string
+	logger      *slog.Logger
+}
+
+func NewProvisionUserCommand(
+	userRepo ports.UserRepository,
+	teamRepo ports.TeamRepository,
+	syncRepo ports.TeamSyncRepository,
+	defaultTeam string,
+	logger *slog.Logger,
+) *ProvisionUserCommand {
+	return &ProvisionUserCommand{
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		syncRepo:    syncRepo,
+		defaultTeam: defaultTeam,
+		logger:      logger,
+	}
+}
+
+// Execute creates a user pushed by the identity provider. The user joins the
+// default team, which is created if needed, until a group assigns a team.
+func (c *ProvisionUserCommand) Execute(ctx context.Context, user *entities.User) (_ *entities.User, err error) {
+	ctx, span := startSpan(ctx, "ProvisionUserCommand", attribute.String("user.id", user.ID))
+	defer finishSpan(span, &err)
+
+	exists, err := c.userRepo.ExistsByID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("checking user exists: %w", err)
+	}
+	if exists {
+		return nil, entities.ErrUserExists
+	}
+	if err := checkUsernameFree(ctx, c.userRepo, user.ID, user.Username); err != nil {
+		return nil, err
+	}
+
+	user.TeamName = c.defaultTeam
+	plan := &entities.TeamSyncPlan{Add: []*entities.User{user}}
+	if err := planTeam(ctx, c.teamRepo, plan, c.defaultTeam); err != nil {
+		return nil, err
+	}
+	if err := c.syncRepo.Apply(ctx, plan); err != nil {
+		return nil, fmt.Errorf("saving user: %w", err)
+	}
+
+	c.logger.Info("User provisioned", "user_id", user.ID, "team_name", user.TeamName)
+	return user, nil
+}
+
+// checkUsernameFree returns ErrUserExists when a user other than userID
+// already has the username. Usernames are compared case-insensitively, as
+// identity providers do.
+func checkUsernameFree(ctx context.Context, userRepo ports.UserRepository, userID, username string) error {
+	users, err := userRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing users: %w", err)
+	}
+	for _, u := range users {
+		if u.ID != userID && strings.EqualFold(u.Username, username) {
+			return entities.ErrUserExists
+		}
+	}
+	return nil
+}
+
+// planTeam adds name to the teams the plan creates when it does not exist.
+func planTeam(ctx context.Context, teamRepo ports.TeamRepository, plan *entities.TeamSyncPlan, name string) error {
+	exists, err := teamRepo.ExistsByName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("checking team exists: %w", err)
+	}
+	if !exists {
+		plan.CreateTeams = append(plan.CreateTeams, name)
+	}
+	return nil
+}
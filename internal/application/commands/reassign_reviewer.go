@@ -3,6 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
@@ -14,6 +17,10 @@ type ReassignReviewerCommand struct {
 	userRepo          ports.UserRepository
 	prRepo            ports.PRRepository
 	assignmentService *services.ReviewerAssignmentService
+	notifier          ports.Notifier
+	metrics           ports.AssignmentMetrics
+	clock             services.Clock
+	logger            *slog.Logger
 }
 
 func NewReassignReviewerCommand(
@@ -21,12 +28,20 @@ func NewReassignReviewerCommand(
 	userRepo ports.UserRepository,
 	prRepo ports.PRRepository,
 	assignmentService *services.ReviewerAssignmentService,
+	notifier ports.Notifier,
+	metrics ports.AssignmentMetrics,
+	clock services.Clock,
+	logger *slog.Logger,
 ) *ReassignReviewerCommand {
 	return &ReassignReviewerCommand{
 		teamRepo:          teamRepo,
 		userRepo:          userRepo,
 		prRepo:            prRepo,
 		assignmentService: assignmentService,
+		notifier:          notifier,
+		metrics:           metrics,
+		clock:             clock,
+		logger:            logger,
 	}
 }
 
@@ -35,7 +50,10 @@ type ReassignReviewerResult struct {
 	ReplacedBy string
 }
 
-func (c *ReassignReviewerCommand) Execute(ctx context.Context, prID, oldReviewerID string) (*ReassignReviewerResult, error) {
+func (c *ReassignReviewerCommand) Execute(ctx context.Context, prID, oldReviewerID string) (_ *ReassignReviewerResult, err error) {
+	ctx, span := startSpan(ctx, "ReassignReviewerCommand", attribute.String("pr.id", prID), attribute.String("pr.old_reviewer_id", oldReviewerID))
+	defer finishSpan(span, &err)
+
 	pr, err := c.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, fmt.Errorf("getting pr: %w", err)
@@ -75,20 +93,33 @@ func (c *ReassignReviewerCommand) Execute(ctx context.Context, prID, oldReviewer
 		return nil, entities.ErrTeamNotFound
 	}
 
-	newReviewerID, err := c.assignmentService.FindReplacement(team, pr.AuthorID, pr.AssignedReviewers)
+	openReviews, err := c.prRepo.CountOpenReviews(ctx, team.Name)
+	if err != nil {
+		return nil, fmt.Errorf("counting open reviews: %w", err)
+	}
+
+	decision, err := c.assignmentService.FindReplacement(team, pr.AuthorID, pr.AssignedReviewers, openReviews)
 	if err != nil {
+		recordNoCandidate(c.metrics, "reassign", team.Name, err)
 		return nil, fmt.Errorf("finding replacement: %w", err)
 	}
+	newReviewerID := decision.Reviewers[0]
 
 	err = pr.ReassignReviewer(oldReviewerID, newReviewerID)
 	if err != nil {
 		return nil, fmt.Errorf("reassigning reviewer: %w", err)
 	}
 
-	err = c.prRepo.Save(ctx, pr)
+	decision.PullRequestID = pr.ID
+	decision.ReplacedReviewerID = oldReviewerID
+	decision.CreatedAt = c.clock.Now().UTC()
+	err = c.prRepo.SaveWithDecision(ctx, pr, decision)
 	if err != nil {
 		return nil, fmt.Errorf("saving pr: %w", err)
 	}
+	recordReviewersAssigned(c.metrics, c.assignmentService.Strategy(), team.Name, 1)
+
+	notifyReviewersAssigned(ctx, c.notifier, c.logger, pr, team, []string{newReviewerID})
 
 	return &ReassignReviewerResult{
 		PR:         pr,
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+type RevokeAPITokenCommand struct {
+	tokenRepo ports.APITokenRepository
+	clock     services.Clock
+}
+
+func NewRevokeAPITokenCommand(tokenRepo ports.APITokenRepository, clock services.Clock) *RevokeAPITokenCommand {
+	return &RevokeAPITokenCommand{
+		tokenRepo: tokenRepo,
+		clock:     clock,
+	}
+}
+
+func (c *RevokeAPITokenCommand) Execute(ctx context.Context, tokenID string) (_ *entities.APIToken, err error) {
+	ctx, span := startSpan(ctx, "RevokeAPITokenCommand", attribute.String("api_token.id", tokenID))
+	defer finishSpan(span, &err)
+
+	token, err := c.tokenRepo.GetByID(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("getting api token: %w", err)
+	}
+	if token == nil {
+		return nil, entities.ErrAPITokenNotFound
+	}
+
+	token.Revoke(c.clock.Now().UTC())
+
+	if err := c.tokenRepo.Save(ctx, token); err != nil {
+		return nil, fmt.Errorf("saving api token: %w", err)
+	}
+
+	return token, nil
+}
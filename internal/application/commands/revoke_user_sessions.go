@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type RevokeUserSessionsCommand struct {
+	userRepo ports.UserRepository
+	revoker  ports.SessionRevoker
+}
+
+func NewRevokeUserSessionsCommand(userRepo ports.UserRepository, revoker ports.SessionRevoker) *RevokeUserSessionsCommand {
+	return &RevokeUserSessionsCommand{
+		userRepo: userRepo,
+		revoker:  revoker,
+	}
+}
+
+func (c *RevokeUserSessionsCommand) Execute(ctx context.Context, userID string) (err error) {
+	ctx, span := startSpan(ctx, "RevokeUserSessionsCommand", attribute.String("user.id", userID))
+	defer finishSpan(span, &err)
+
+	exists, err := c.userRepo.ExistsByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("checking user exists: %w", err)
+	}
+	if !exists {
+		return entities.ErrUserNotFound
+	}
+
+	if err := c.revoker.RevokeUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("revoking sessions: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
@@ -19,14 +21,39 @@ func NewRollbackMigrationsCommand(migrationRepo ports.MigrationRepository, logge
 	}
 }
 
-func (c *RollbackMigrationsCommand) Execute(ctx context.Context) error {
-	c.logger.Info("Executing command: rolling back migrations")
+// Execute reverts the last steps applied migrations.
+func (c *RollbackMigrationsCommand) Execute(ctx context.Context, steps int, dryRun bool) (_ []ports.MigrationStep, err error) {
+	ctx, span := startSpan(ctx, "RollbackMigrationsCommand")
+	defer finishSpan(span, &err)
 
-	if err := c.migrationRepo.RollbackMigrations(ctx); err != nil {
-		c.logger.Error("Rollback failed", "error", err)
-		return err
+	if steps <= 0 {
+		return nil, errors.New("steps must be positive")
 	}
 
-	c.logger.Info("Command executed: migrations rolled back successfully")
-	return nil
+	current, dirty, err := c.migrationRepo.GetCurrentVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting current version: %w", err)
+	}
+	if dirty {
+		return nil, fmt.Errorf("migration %d is dirty, fix it and run force first", current)
+	}
+
+	migrations, err := c.migrationRepo.ListMigrations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing migrations: %w", err)
+	}
+
+	var applied []uint
+	for _, m := range migrations {
+		if m.Version <= current {
+			applied = append(applied, m.Version)
+		}
+	}
+
+	target := uint(0)
+	if steps < len(applied) {
+		target = applied[len(applied)-1-steps]
+	}
+
+	return runMigrationPlan(ctx, c.migrationRepo, c.logger, target, dryRun)
 }
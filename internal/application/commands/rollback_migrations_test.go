@@ -0,0 +1,135 @@
+package commands_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+type fakeMigrationRepo struct {
+	current    uint
+	dirty      bool
+	migrations []ports.Migration
+	migratedTo []uint
+	plannedTo  []uint
+	locked     bool
+	unlocked   bool
+	// lockDeadline and migrateDeadline record whether Lock and MigrateTo
+	// were called with a context that has a deadline.
+	lockDeadline    bool
+	migrateDeadline bool
+}
+
+func (r *fakeMigrationRepo) MigrateTo(ctx context.Context, version uint) error {
+	r.migratedTo = append(r.migratedTo, version)
+	_, r.migrateDeadline = ctx.Deadline()
+	return nil
+}
+
+func (r *fakeMigrationRepo) Force(ctx context.Context, version int) error { return nil }
+
+func (r *fakeMigrationRepo) Lock(ctx context.Context) (func(), error) {
+	r.locked = true
+	_, r.lockDeadline = ctx.Deadline()
+	return func() { r.unlocked = true }, nil
+}
+
+func (r *fakeMigrationRepo) GetCurrentVersion(ctx context.Context) (uint, bool, error) {
+	return r.current, r.dirty, nil
+}
+
+func (r *fakeMigrationRepo) GetLatestVersion(ctx context.Context) (uint, error) {
+	return r.migrations[len(r.migrations)-1].Version, nil
+}
+
+func (r *fakeMigrationRepo) ListMigrations(ctx context.Context) ([]ports.Migration, error) {
+	return r.migrations, nil
+}
+
+func (r *fakeMigrationRepo) Plan(ctx context.Context, version uint) ([]ports.MigrationStep, error) {
+	r.plannedTo = append(r.plannedTo, version)
+	var steps []ports.MigrationStep
+	for i := len(r.migrations) - 1; i >= 0; i-- {
+		m := r.migrations[i]
+		if m.Version > version && m.Version <= r.current {
+			steps = append(steps, ports.MigrationStep{Migration: m, Direction: ports.MigrationDown})
+		}
+	}
+	return steps, nil
+}
+
+func newFakeMigrationRepo(current uint) *fakeMigrationRepo {
+	return &fakeMigrationRepo{
+		current: current,
+		migrations: []ports.Migration{
+			{Version: 1, Name: "init"},
+			{Version: 2, Name: "add_admin"},
+			{Version: 5, Name: "add_roles"},
+			{Version: 7, Name: "add_tokens"},
+		},
+	}
+}
+
+func TestRollbackMigrationsSteps(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name   string
+		steps  int
+		target uint
+	}{
+		{"one step", 1, 2},
+		{"two steps", 2, 1},
+		{"all applied", 3, 0},
+		{"more than applied", 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeMigrationRepo(5)
+			steps, err := commands.NewRollbackMigrationsCommand(repo, logger).Execute(context.Background(), tt.steps, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(repo.migratedTo) != 1 || repo.migratedTo[0] != tt.target {
+				t.Errorf("expected migration to %d, got %v", tt.target, repo.migratedTo)
+			}
+			if len(steps) == 0 || steps[0].Version != 5 || steps[0].Direction != ports.MigrationDown {
+				t.Errorf("expected first step to revert 5, got %+v", steps)
+			}
+		})
+	}
+}
+
+func TestRollbackMigrationsDryRunDoesNotMigrate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := newFakeMigrationRepo(7)
+
+	steps, err := commands.NewRollbackMigrationsCommand(repo, logger).Execute(context.Background(), 1, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.migratedTo) != 0 {
+		t.Errorf("dry run must not migrate, got %v", repo.migratedTo)
+	}
+	if len(steps) != 1 || steps[0].Version != 7 {
+		t.Errorf("expected a single step reverting 7, got %+v", steps)
+	}
+}
+
+func TestRollbackMigrationsRejectsDirtySchema(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := newFakeMigrationRepo(5)
+	repo.dirty = true
+
+	if _, err := commands.NewRollbackMigrationsCommand(repo, logger).Execute(context.Background(), 1, false); err == nil {
+		t.Fatal("expected error for dirty schema")
+	}
+	if len(repo.plannedTo) != 0 {
+		t.Errorf("expected no plan for dirty schema, got %v", repo.plannedTo)
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+type SendReviewDigestCommand struct {
+	prRepo   ports.PRRepository
+	userRepo ports.UserRepository
+	runRepo  ports.DigestRunRepository
+	sender   ports.DigestSender
+	clock    services.Clock
+	sla      time.Duration
+	logger   *slog.Logger
+}
+
+func NewSendReviewDigestCommand(
+	prRepo ports.PRRepository,
+	userRepo ports.UserRepository,
+	runRepo ports.DigestRunRepository,
+	sender ports.DigestSender,
+	clock services.Clock,
+	sla time.Duration,
+	logger *slog.Logger,
+) *SendReviewDigestCommand {
+	return &SendReviewDigestCommand{
+		prRepo:   prRepo,
+		userRepo: userRepo,
+		runRepo:  runRepo,
+		sender:   sender,
+		clock:    clock,
+		sla:      sla,
+		logger:   logger,
+	}
+}
+
+// Execute sends one digest per opted-in reviewer with open assignments and
+// returns the number of digests delivered. A failure for one reviewer does
+// not prevent the others from receiving theirs. The digest goes out once a
+// day: when another instance has already claimed today's run, nothing is sent.
+func (c *SendReviewDigestCommand) Execute(ctx context.Context) (_ int, err error) {
+	ctx, span := startSpan(ctx, "SendReviewDigestCommand")
+	defer finishSpan(span, &err)
+
+	now := c.clock.Now()
+	claimed, err := c.runRepo.Claim(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("claiming digest run: %w", err)
+	}
+	if !claimed {
+		c.logger.Info("review digest for today was already sent by another instance")
+		return 0, nil
+	}
+
+	prs, err := c.prRepo.ListOpen(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing open prs: %w", err)
+	}
+
+	byReviewer := make(map[string][]ports.ReviewAssignment)
+	for _, pr := range prs {
+		age := now.Sub(pr.CreatedAt)
+		for _, reviewerID := range pr.AssignedReviewers {
+			byReviewer[reviewerID] = append(byReviewer[reviewerID], ports.ReviewAssignment{
+				PR:          pr,
+				Age:         age,
+				SLABreached: c.sla > 0 && age > c.sla,
+			})
+		}
+	}
+
+	reviewerIDs := make([]string, 0, len(byReviewer))
+	for id := range byReviewer {
+		reviewerIDs = append(reviewerIDs, id)
+	}
+	sort.Strings(reviewerIDs)
+
+	sent := 0
+	var errs []error
+	for _, reviewerID := range reviewerIDs {
+		reviewer, err := c.userRepo.GetByID(ctx, reviewerID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("getting reviewer %s: %w", reviewerID, err))
+			continue
+		}
+		if reviewer == nil || !reviewer.WantsDigestEmails() {
+			continue
+		}
+
+		assignments := byReviewer[reviewerID]
+		sort.Slice(assignments, func(i, j int) bool {
+			return assignments[i].Age > assignments[j].Age
+		})
+
+		digest := ports.ReviewDigest{
+			Reviewer:    reviewer,
+			Assignments: assignments,
+			SLA:         c.sla,
+			GeneratedAt: now,
+		}
+
+		if err := c.sender.SendDigest(ctx, digest); err != nil {
+			c.logger.Warn("failed to send review digest", "user_id", reviewerID, "error", err)
+			errs = append(errs, fmt.Errorf("sending digest to %s: %w", reviewerID, err))
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
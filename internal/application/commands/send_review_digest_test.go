@@ -0,0 +1,96 @@
+package commands_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+type recordingDigestSender struct {
+	digests []ports.ReviewDigest
+}
+
+func (s *recordingDigestSender) SendDigest(ctx context.Context, digest ports.ReviewDigest) error {
+	s.digests = append(s.digests, digest)
+	return nil
+}
+
+func TestSendReviewDigest(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	userRepo := repositories.NewInMemoryUserRepository()
+	prRepo := repositories.NewInMemoryPRRepository(userRepo, repositories.NewInMemoryAssignmentDecisionRepository())
+
+	optedIn := entities.NewUser("user2", "bob", "backend", true)
+	optedIn.SetNotificationPreferences("bob@example.com", entities.NotificationPreferences{EmailDigest: true})
+	optedOut := entities.NewUser("user3", "charlie", "backend", true)
+	for _, u := range []*entities.User{optedIn, optedOut} {
+		if err := userRepo.Save(ctx, u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+
+	stale := entities.NewPullRequest("pr-1", "Old change", "user1", []string{"user2", "user3"})
+	stale.CreatedAt = now.Add(-72 * time.Hour)
+	fresh := entities.NewPullRequest("pr-2", "Fresh change", "user1", []string{"user2"})
+	fresh.CreatedAt = now.Add(-2 * time.Hour)
+	merged := entities.NewPullRequest("pr-3", "Done", "user1", []string{"user2"})
+	merged.Merge()
+	for _, pr := range []*entities.PullRequest{stale, fresh, merged} {
+		if err := prRepo.Save(ctx, pr); err != nil {
+			t.Fatalf("failed to save pr: %v", err)
+		}
+	}
+
+	sender := &recordingDigestSender{}
+	runRepo := repositories.NewInMemoryDigestRunRepository()
+	cmd := commands.NewSendReviewDigestCommand(prRepo, userRepo, runRepo, sender, fixedClock{now: now}, 48*time.Hour, logger)
+
+	sent, err := cmd.Execute(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != 1 || len(sender.digests) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(sender.digests))
+	}
+
+	digest := sender.digests[0]
+	if digest.Reviewer.ID != "user2" {
+		t.Errorf("expected digest for user2, got %s", digest.Reviewer.ID)
+	}
+	if len(digest.Assignments) != 2 {
+		t.Fatalf("expected 2 open assignments, got %d", len(digest.Assignments))
+	}
+	if digest.Assignments[0].PR.ID != "pr-1" || !digest.Assignments[0].SLABreached {
+		t.Errorf("expected oldest assignment pr-1 to breach SLA, got %+v", digest.Assignments[0])
+	}
+	if digest.Assignments[1].SLABreached {
+		t.Error("expected fresh assignment to be within SLA")
+	}
+
+	// Another instance firing on the same day finds the run claimed.
+	replica := commands.NewSendReviewDigestCommand(prRepo, userRepo, runRepo, sender, fixedClock{now: now.Add(time.Minute)}, 48*time.Hour, logger)
+	if sent, err := replica.Execute(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected the replica to send nothing, got %d, %v", sent, err)
+	}
+
+	tomorrow := commands.NewSendReviewDigestCommand(prRepo, userRepo, runRepo, sender, fixedClock{now: now.AddDate(0, 0, 1)}, 48*time.Hour, logger)
+	if sent, err := tomorrow.Execute(ctx); err != nil || sent != 1 {
+		t.Fatalf("expected the next day's digest to be sent, got %d, %v", sent, err)
+	}
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SetNotificationPreferencesCommand struct {
+	userRepo ports.UserRepository
+}
+
+func NewSetNotificationPreferencesCommand(userRepo ports.UserRepository) *SetNotificationPreferencesCommand {
+	return &SetNotificationPreferencesCommand{userRepo: userRepo}
+}
+
+func (c *SetNotificationPreferencesCommand) Execute(
+	ctx context.Context,
+	userID, email string,
+	prefs entities.NotificationPreferences,
+) (_ *entities.User, err error) {
+	ctx, span := startSpan(ctx, "SetNotificationPreferencesCommand", attribute.String("user.id", userID))
+	defer finishSpan(span, &err)
+
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return nil, entities.ErrUserNotFound
+	}
+
+	user.SetNotificationPreferences(email, prefs)
+
+	err = c.userRepo.Save(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("saving user: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+type SetPasswordCommand struct {
+	userRepo       ports.UserRepository
+	credentialRepo ports.CredentialRepository
+	hasher         ports.PasswordHasher
+	clock          services.Clock
+}
+
+func NewSetPasswordCommand(
+	userRepo ports.UserRepository,
+	credentialRepo ports.CredentialRepository,
+	hasher ports.PasswordHasher,
+	clock services.Clock,
+) *SetPasswordCommand {
+	return &SetPasswordCommand{
+		userRepo:       userRepo,
+		credentialRepo: credentialRepo,
+		hasher:         hasher,
+		clock:          clock,
+	}
+}
+
+// Execute sets or resets a user's password. Resetting also lifts any active
+// lockout.
+func (c *SetPasswordCommand) Execute(ctx context.Context, userID, password string) (err error) {
+	ctx, span := startSpan(ctx, "SetPasswordCommand", attribute.String("user.id", userID))
+	defer finishSpan(span, &err)
+
+	if len(password) < entities.MinPasswordLength {
+		return entities.ErrWeakPassword
+	}
+
+	exists, err := c.userRepo.ExistsByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("checking user exists: %w", err)
+	}
+	if !exists {
+		return entities.ErrUserNotFound
+	}
+
+	hash, err := c.hasher.Hash(password)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", err)
+	}
+
+	credentials, err := c.credentialRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting credentials: %w", err)
+	}
+	if credentials == nil {
+		credentials = entities.NewCredentials(userID, hash)
+	} else {
+		credentials.SetPasswordHash(hash, c.clock.Now().UTC())
+	}
+
+	if err := c.credentialRepo.Save(ctx, credentials); err != nil {
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SetTeamMembersCommand struct {
+	teamRepo    ports.TeamRepository
+	userRepo    ports.UserRepository
+	syncRepo    ports.TeamSyncRepository
+	defaultTeam string
+	logger      *slog.Logger
+}
+
+func NewSetTeamMembersCommand(
+	teamRepo ports.TeamRepository,
+	userRepo ports.UserRepository,
+	syncRepo ports.TeamSyncRepository,
+	defaultTeam string,
+	logger *slog.Logger,
+) *SetTeamMembersCommand {
+	return &SetTeamMembersCommand{
+		teamRepo:    teamRepo,
+		userRepo:    userRepo,
+		syncRepo:    syncRepo,
+		defaultTeam: defaultTeam,
+		logger:      logger,
+	}
+}
+
+// Execute makes the listed users the members of a team, creating the team when
+// create is set. A user belongs to one team, so listed users leave their old
+// team; members who are no longer listed move to the default team.
+func (c *SetTeamMembersCommand) Execute(ctx context.Context, teamName string, memberIDs []string, create bool) (_ *entities.Team, err error) {
+	ctx, span := startSpan(ctx, "SetTeamMembersCommand", attribute.String("team.name", teamName), attribute.Int("team.members", len(memberIDs)))
+	defer finishSpan(span, &err)
+
+	exists, err := c.teamRepo.ExistsByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("checking team exists: %w", err)
+	}
+	plan := &entities.TeamSyncPlan{}
+	switch {
+	case create && exists:
+		return nil, entities.ErrTeamExists
+	case create:
+		plan.CreateTeams = append(plan.CreateTeams, teamName)
+	case !exists:
+		return nil, entities.ErrTeamNotFound
+	}
+
+	listed := make(map[string]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		if listed[id] {
+			continue
+		}
+		listed[id] = true
+
+		user, err := c.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("getting user %s: %w", id, err)
+		}
+		if user == nil {
+			return nil, fmt.Errorf("%w: %s", entities.ErrUserNotFound, id)
+		}
+		if user.TeamName != teamName {
+			plan.Move = append(plan.Move, entities.UserMove{User: user, FromTeam: user.TeamName})
+			user.TeamName = teamName
+		}
+	}
+
+	if exists && teamName != c.defaultTeam {
+		if err := c.planDropped(ctx, plan, teamName, listed); err != nil {
+			return nil, err
+		}
+	}
+
+	if !plan.IsEmpty() {
+		if err := c.syncRepo.Apply(ctx, plan); err != nil {
+			return nil, fmt.Errorf("applying team members: %w", err)
+		}
+		c.logger.Info("Team members set", "team_name", teamName, "moved", len(plan.Move))
+	}
+
+	team, err := c.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("getting team: %w", err)
+	}
+	return team, nil
+}
+
+func (c *SetTeamMembersCommand) planDropped(ctx context.Context, plan *entities.TeamSyncPlan, teamName string, listed map[string]bool) error {
+	members, err := c.userRepo.GetByTeamName(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("getting members of %s: %w", teamName, err)
+	}
+	dropped := false
+	for _, member := range members {
+		if listed[member.ID] {
+			continue
+		}
+		member.TeamName = c.defaultTeam
+		plan.Move = append(plan.Move, entities.UserMove{User: member, FromTeam: teamName})
+		dropped = true
+	}
+	if !dropped {
+		return nil
+	}
+	return planTeam(ctx, c.teamRepo, plan, c.defaultTeam)
+}
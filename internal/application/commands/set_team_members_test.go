@@ -0,0 +1,85 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+func newTeamMembersStore(t *testing.T) *repositories.InMemoryStore {
+	t.Helper()
+	ctx := context.Background()
+	store := repositories.NewInMemoryStore()
+
+	backend := []*entities.User{
+		entities.NewUser("u1", "alice", "backend", true),
+		entities.NewUser("u2", "bob", "backend", true),
+	}
+	ops := []*entities.User{entities.NewUser("u3", "carol", "ops", true)}
+	for _, team := range []*entities.Team{entities.NewTeam("backend", backend), entities.NewTeam("ops", ops)} {
+		if err := store.Teams.Save(ctx, team); err != nil {
+			t.Fatalf("failed to save team: %v", err)
+		}
+	}
+	return store
+}
+
+func TestSetTeamMembersMovesUsers(t *testing.T) {
+	ctx := context.Background()
+	store := newTeamMembersStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cmd := commands.NewSetTeamMembersCommand(store.Teams, store.Users, repositories.NewInMemoryTeamSyncRepository(store), "unassigned", logger)
+
+	team, err := cmd.Execute(ctx, "backend", []string{"u1", "u3"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := userIDs(team.Members); !slices.Equal(ids, []string{"u1", "u3"}) {
+		t.Errorf("expected members [u1 u3], got %v", ids)
+	}
+	if u2, _ := store.Users.GetByID(ctx, "u2"); u2 == nil || u2.TeamName != "unassigned" {
+		t.Errorf("expected u2 to move to the default team, got %+v", u2)
+	}
+
+	if _, err := cmd.Execute(ctx, "backend", nil, true); !errors.Is(err, entities.ErrTeamExists) {
+		t.Errorf("expected ErrTeamExists, got %v", err)
+	}
+	if _, err := cmd.Execute(ctx, "missing", nil, false); !errors.Is(err, entities.ErrTeamNotFound) {
+		t.Errorf("expected ErrTeamNotFound, got %v", err)
+	}
+	if _, err := cmd.Execute(ctx, "ops", []string{"ghost"}, false); !errors.Is(err, entities.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestDeleteTeamKeepsMembers(t *testing.T) {
+	ctx := context.Background()
+	store := newTeamMembersStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cmd := commands.NewDeleteTeamCommand(store.Teams, repositories.NewInMemoryTeamSyncRepository(store), "unassigned", logger)
+
+	if err := cmd.Execute(ctx, "backend"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists, _ := store.Teams.ExistsByName(ctx, "backend"); exists {
+		t.Error("expected backend to be deleted")
+	}
+	members, err := store.Users.GetByTeamName(ctx, "unassigned")
+	if err != nil {
+		t.Fatalf("failed to list members: %v", err)
+	}
+	if ids := userIDs(members); !slices.Equal(ids, []string{"u1", "u2"}) {
+		t.Errorf("expected u1 and u2 in the default team, got %v", ids)
+	}
+
+	if err := cmd.Execute(ctx, "unassigned"); !errors.Is(err, entities.ErrTeamNotEmpty) {
+		t.Errorf("expected ErrTeamNotEmpty, got %v", err)
+	}
+}
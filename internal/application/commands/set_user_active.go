@@ -4,19 +4,30 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
 type SetUserActiveCommand struct {
 	userRepo ports.UserRepository
+	revoker  ports.SessionRevoker
 }
 
-func NewSetUserActiveCommand(userRepo ports.UserRepository) *SetUserActiveCommand {
-	return &SetUserActiveCommand{userRepo: userRepo}
+func NewSetUserActiveCommand(userRepo ports.UserRepository, revoker ports.SessionRevoker) *SetUserActiveCommand {
+	return &SetUserActiveCommand{
+		userRepo: userRepo,
+		revoker:  revoker,
+	}
 }
 
-func (c *SetUserActiveCommand) Execute(ctx context.Context, userID string, isActive bool) (*entities.User, error) {
+// Execute activates or deactivates a user. When revokeSessions is set, a
+// deactivated user's access and refresh tokens are revoked as well.
+func (c *SetUserActiveCommand) Execute(ctx context.Context, userID string, isActive, revokeSessions bool) (_ *entities.User, err error) {
+	ctx, span := startSpan(ctx, "SetUserActiveCommand", attribute.String("user.id", userID), attribute.Bool("user.is_active", isActive))
+	defer finishSpan(span, &err)
+
 	user, err := c.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("getting user: %w", err)
@@ -32,5 +43,11 @@ func (c *SetUserActiveCommand) Execute(ctx context.Context, userID string, isAct
 		return nil, fmt.Errorf("saving user: %w", err)
 	}
 
+	if !isActive && revokeSessions {
+		if err := c.revoker.RevokeUserSessions(ctx, userID); err != nil {
+			return nil, fmt.Errorf("revoking sessions: %w", err)
+		}
+	}
+
 	return user, nil
 }
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SetUserOnLeaveCommand struct {
+	userRepo ports.UserRepository
+}
+
+func NewSetUserOnLeaveCommand(userRepo ports.UserRepository) *SetUserOnLeaveCommand {
+	return &SetUserOnLeaveCommand{userRepo: userRepo}
+}
+
+// Execute marks a user as away or back. Reviews already assigned to them are
+// left as they are.
+func (c *SetUserOnLeaveCommand) Execute(ctx context.Context, userID string, onLeave bool) (_ *entities.User, err error) {
+	ctx, span := startSpan(ctx, "SetUserOnLeaveCommand", attribute.String("user.id", userID), attribute.Bool("user.on_leave", onLeave))
+	defer finishSpan(span, &err)
+
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return nil, entities.ErrUserNotFound
+	}
+
+	user.SetOnLeave(onLeave)
+
+	err = c.userRepo.Save(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("saving user: %w", err)
+	}
+
+	return user, nil
+}
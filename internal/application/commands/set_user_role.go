@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SetUserRoleCommand struct {
+	userRepo ports.UserRepository
+}
+
+func NewSetUserRoleCommand(userRepo ports.UserRepository) *SetUserRoleCommand {
+	return &SetUserRoleCommand{userRepo: userRepo}
+}
+
+func (c *SetUserRoleCommand) Execute(ctx context.Context, userID string, role entities.Role) (_ *entities.User, err error) {
+	ctx, span := startSpan(ctx, "SetUserRoleCommand", attribute.String("user.id", userID), attribute.String("user.role", role.String()))
+	defer finishSpan(span, &err)
+
+	if !role.IsValid() {
+		return nil, entities.ErrInvalidRole
+	}
+
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return nil, entities.ErrUserNotFound
+	}
+
+	user.SetRole(role)
+
+	err = c.userRepo.Save(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("saving user: %w", err)
+	}
+
+	return user, nil
+}
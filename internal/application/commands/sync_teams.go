@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SyncTeamsCommand struct {
+	teamRepo ports.TeamRepository
+	userRepo ports.UserRepository
+	prRepo   ports.PRRepository
+	syncRepo ports.TeamSyncRepository
+	logger   *slog.Logger
+}
+
+func NewSyncTeamsCommand(
+	teamRepo ports.TeamRepository,
+	userRepo ports.UserRepository,
+	prRepo ports.PRRepository,
+	syncRepo ports.TeamSyncRepository,
+	logger *slog.Logger,
+) *SyncTeamsCommand {
+	return &SyncTeamsCommand{
+		teamRepo: teamRepo,
+		userRepo: userRepo,
+		prRepo:   prRepo,
+		syncRepo: syncRepo,
+		logger:   logger,
+	}
+}
+
+// Execute compares the roster with the stored teams and returns the plan that
+// reconciles them, applying it only when apply is set. Only teams named in the
+// roster are reconciled; list a team without members to empty it.
+func (c *SyncTeamsCommand) Execute(ctx context.Context, roster []*entities.Team, apply bool) (_ *entities.TeamSyncPlan, err error) {
+	ctx, span := startSpan(ctx, "SyncTeamsCommand", attribute.Int("roster.teams", len(roster)), attribute.Bool("sync.apply", apply))
+	defer finishSpan(span, &err)
+
+	if err := validateRoster(roster); err != nil {
+		return nil, err
+	}
+
+	plan := &entities.TeamSyncPlan{}
+	listed := make(map[string]bool)
+	for _, team := range roster {
+		exists, err := c.teamRepo.ExistsByName(ctx, team.Name)
+		if err != nil {
+			return nil, fmt.Errorf("checking team exists: %w", err)
+		}
+		if !exists {
+			plan.CreateTeams = append(plan.CreateTeams, team.Name)
+		}
+
+		for _, member := range team.Members {
+			listed[member.ID] = true
+			if err := c.planMember(ctx, plan, team.Name, member); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, team := range roster {
+		members, err := c.userRepo.GetByTeamName(ctx, team.Name)
+		if err != nil {
+			return nil, fmt.Errorf("getting members of %s: %w", team.Name, err)
+		}
+		for _, member := range members {
+			if listed[member.ID] {
+				continue
+			}
+			hasHistory, err := c.hasPRHistory(ctx, member.ID)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case !hasHistory:
+				plan.Remove = append(plan.Remove, member)
+			case member.IsActive:
+				member.SetActive(false)
+				plan.Deactivate = append(plan.Deactivate, member)
+			}
+		}
+	}
+
+	if apply && !plan.IsEmpty() {
+		if err := c.syncRepo.Apply(ctx, plan); err != nil {
+			return nil, fmt.Errorf("applying team sync: %w", err)
+		}
+		c.logger.Info("Teams synced",
+			"created_teams", len(plan.CreateTeams),
+			"added", len(plan.Add),
+			"moved", len(plan.Move),
+			"updated", len(plan.Update),
+			"deactivated", len(plan.Deactivate),
+			"removed", len(plan.Remove),
+		)
+	}
+
+	return plan, nil
+}
+
+// planMember files a listed member under the change that brings the stored
+// user to the listed state. Role, contact details and notification settings
+// are not part of the roster and are kept.
+func (c *SyncTeamsCommand) planMember(ctx context.Context, plan *entities.TeamSyncPlan, teamName string, member *entities.User) error {
+	current, err := c.userRepo.GetByID(ctx, member.ID)
+	if err != nil {
+		return fmt.Errorf("getting user %s: %w", member.ID, err)
+	}
+	if current == nil {
+		plan.Add = append(plan.Add, entities.NewUser(member.ID, member.Username, teamName, member.IsActive))
+		return nil
+	}
+
+	fromTeam := current.TeamName
+	changed := current.Username != member.Username || current.IsActive != member.IsActive
+	current.Username = member.Username
+	current.TeamName = teamName
+	current.SetActive(member.IsActive)
+
+	switch {
+	case fromTeam != teamName:
+		plan.Move = append(plan.Move, entities.UserMove{User: current, FromTeam: fromTeam})
+	case !changed:
+	case !member.IsActive:
+		plan.Deactivate = append(plan.Deactivate, current)
+	default:
+		plan.Update = append(plan.Update, current)
+	}
+	return nil
+}
+
+func (c *SyncTeamsCommand) hasPRHistory(ctx context.Context, userID string) (bool, error) {
+	for _, query := range []ports.PRQuery{{AuthorID: userID}, {ReviewerID: userID}} {
+		count, err := c.prRepo.Count(ctx, query)
+		if err != nil {
+			return false, fmt.Errorf("counting prs of %s: %w", userID, err)
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func validateRoster(roster []*entities.Team) error {
+	teams := make(map[string]bool, len(roster))
+	users := make(map[string]string)
+	for _, team := range roster {
+		if team.Name == "" {
+			return fmt.Errorf("%w: team_name cannot be empty", entities.ErrInvalidRoster)
+		}
+		if teams[team.Name] {
+			return fmt.Errorf("%w: team %s is listed twice", entities.ErrInvalidRoster, team.Name)
+		}
+		teams[team.Name] = true
+
+		for _, member := range team.Members {
+			if member.ID == "" || member.Username == "" {
+				return fmt.Errorf("%w: members of %s need a user_id and a username", entities.ErrInvalidRoster, team.Name)
+			}
+			if other, ok := users[member.ID]; ok {
+				return fmt.Errorf("%w: user %s is listed in %s and %s", entities.ErrInvalidRoster, member.ID, other, team.Name)
+			}
+			users[member.ID] = team.Name
+		}
+	}
+	return nil
+}
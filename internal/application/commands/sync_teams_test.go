@@ -0,0 +1,148 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+func newSyncTeamsCommand(t *testing.T) (*commands.SyncTeamsCommand, *repositories.InMemoryStore) {
+	t.Helper()
+	ctx := context.Background()
+	store := repositories.NewInMemoryStore()
+
+	backend := []*entities.User{
+		entities.NewUser("u1", "alice", "backend", true),
+		entities.NewUser("u2", "bob", "backend", true),
+		entities.NewUser("u3", "carol", "backend", true),
+		entities.NewUser("u4", "dan", "backend", false),
+	}
+	ops := []*entities.User{entities.NewUser("u5", "erin", "ops", true)}
+	for _, team := range []*entities.Team{entities.NewTeam("backend", backend), entities.NewTeam("ops", ops)} {
+		if err := store.Teams.Save(ctx, team); err != nil {
+			t.Fatalf("failed to save team: %v", err)
+		}
+	}
+	// bob has review history, carol has none.
+	if err := store.PRs.Save(ctx, entities.NewPullRequest("pr-1", "PR", "u1", []string{"u2"})); err != nil {
+		t.Fatalf("failed to save pr: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cmd := commands.NewSyncTeamsCommand(store.Teams, store.Users, store.PRs, repositories.NewInMemoryTeamSyncRepository(store), logger)
+	return cmd, store
+}
+
+func TestSyncTeamsPlansWithoutApplying(t *testing.T) {
+	ctx := context.Background()
+	cmd, store := newSyncTeamsCommand(t)
+
+	roster := []*entities.Team{
+		entities.NewTeam("backend", []*entities.User{
+			entities.NewUser("u1", "alice", "backend", false),
+			entities.NewUser("u4", "dan", "backend", true),
+		}),
+		entities.NewTeam("frontend", []*entities.User{
+			entities.NewUser("u5", "erin", "frontend", true),
+			entities.NewUser("u6", "frank", "frontend", true),
+		}),
+	}
+
+	plan, err := cmd.Execute(ctx, roster, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.CreateTeams) != 1 || plan.CreateTeams[0] != "frontend" {
+		t.Errorf("expected frontend to be created, got %v", plan.CreateTeams)
+	}
+	if len(plan.Add) != 1 || plan.Add[0].ID != "u6" {
+		t.Errorf("expected u6 to be added, got %+v", plan.Add)
+	}
+	if len(plan.Move) != 1 || plan.Move[0].User.ID != "u5" || plan.Move[0].FromTeam != "ops" {
+		t.Errorf("expected u5 to move from ops, got %+v", plan.Move)
+	}
+	if len(plan.Update) != 1 || plan.Update[0].ID != "u4" || !plan.Update[0].IsActive {
+		t.Errorf("expected u4 to be reactivated, got %+v", plan.Update)
+	}
+	// alice is deactivated by the roster, bob is dropped but has history.
+	if ids := userIDs(plan.Deactivate); len(ids) != 2 || ids[0] != "u1" || ids[1] != "u2" {
+		t.Errorf("expected u1 and u2 to be deactivated, got %v", ids)
+	}
+	if ids := userIDs(plan.Remove); len(ids) != 1 || ids[0] != "u3" {
+		t.Errorf("expected u3 to be removed, got %v", ids)
+	}
+
+	if exists, _ := store.Teams.ExistsByName(ctx, "frontend"); exists {
+		t.Error("expected a dry run to leave the teams alone")
+	}
+	if u3, _ := store.Users.GetByID(ctx, "u3"); u3 == nil {
+		t.Error("expected a dry run to keep u3")
+	}
+}
+
+func TestSyncTeamsApplies(t *testing.T) {
+	ctx := context.Background()
+	cmd, store := newSyncTeamsCommand(t)
+
+	roster := []*entities.Team{
+		entities.NewTeam("backend", []*entities.User{
+			entities.NewUser("u1", "alice", "backend", true),
+			entities.NewUser("u5", "erin", "backend", true),
+		}),
+	}
+	if _, err := cmd.Execute(ctx, roster, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	members, err := store.Users.GetByTeamName(ctx, "backend")
+	if err != nil {
+		t.Fatalf("failed to list members: %v", err)
+	}
+	if ids := userIDs(members); len(ids) != 3 || ids[0] != "u1" || ids[1] != "u2" || ids[2] != "u5" {
+		t.Errorf("expected members u1 u2 u5, got %v", ids)
+	}
+	if u2, _ := store.Users.GetByID(ctx, "u2"); u2 == nil || u2.IsActive {
+		t.Errorf("expected u2 to be kept inactive, got %+v", u2)
+	}
+	for _, id := range []string{"u3", "u4"} {
+		if user, _ := store.Users.GetByID(ctx, id); user != nil {
+			t.Errorf("expected %s to be removed, got %+v", id, user)
+		}
+	}
+
+	plan, err := cmd.Execute(ctx, roster, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("expected no changes after applying, got %+v", plan)
+	}
+}
+
+func TestSyncTeamsRejectsInvalidRoster(t *testing.T) {
+	cmd, _ := newSyncTeamsCommand(t)
+
+	roster := []*entities.Team{
+		entities.NewTeam("backend", []*entities.User{entities.NewUser("u1", "alice", "backend", true)}),
+		entities.NewTeam("frontend", []*entities.User{entities.NewUser("u1", "alice", "frontend", true)}),
+	}
+	_, err := cmd.Execute(context.Background(), roster, true)
+	if !errors.Is(err, entities.ErrInvalidRoster) {
+		t.Errorf("expected ErrInvalidRoster, got %v", err)
+	}
+}
+
+func userIDs(users []*entities.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
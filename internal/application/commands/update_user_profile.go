@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type UpdateUserProfileCommand struct {
+	userRepo ports.UserRepository
+}
+
+func NewUpdateUserProfileCommand(userRepo ports.UserRepository) *UpdateUserProfileCommand {
+	return &UpdateUserProfileCommand{userRepo: userRepo}
+}
+
+// Execute changes the username and email the identity provider holds for a
+// user. Team, role and notification settings are kept.
+func (c *UpdateUserProfileCommand) Execute(ctx context.Context, userID, username, email string) (_ *entities.User, err error) {
+	ctx, span := startSpan(ctx, "UpdateUserProfileCommand", attribute.String("user.id", userID))
+	defer finishSpan(span, &err)
+
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return nil, entities.ErrUserNotFound
+	}
+	if user.Username != username {
+		if err := checkUsernameFree(ctx, c.userRepo, userID, username); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Username = username
+	user.Email = email
+	if err := c.userRepo.Save(ctx, user); err != nil {
+		return nil, fmt.Errorf("saving user: %w", err)
+	}
+	return user, nil
+}
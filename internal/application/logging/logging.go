@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerCtxKey struct{}
+
+// WithLogger returns a context carrying logger, typically one already
+// annotated with the request ID and caller.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or fallback when there is
+// none.
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := ctx.Value(loggerCtxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return fallback
+}
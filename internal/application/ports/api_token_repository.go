@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type APITokenRepository interface {
+	Save(ctx context.Context, token *entities.APIToken) error
+	GetByID(ctx context.Context, id string) (*entities.APIToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*entities.APIToken, error)
+	List(ctx context.Context) ([]*entities.APIToken, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type AssignmentDecisionRepository interface {
+	Save(ctx context.Context, decision *entities.AssignmentDecision) error
+	// ListByPullRequest returns the decisions for a pull request, oldest first.
+	ListByPullRequest(ctx context.Context, prID string) ([]*entities.AssignmentDecision, error)
+}
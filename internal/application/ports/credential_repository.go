@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type CredentialRepository interface {
+	Save(ctx context.Context, credentials *entities.Credentials) error
+	GetByUserID(ctx context.Context, userID string) (*entities.Credentials, error)
+	// RecordFailedLogin counts a failed attempt in a single write, so that
+	// concurrent attempts are all counted, and returns the updated
+	// credentials, or nil if the user has none.
+	RecordFailedLogin(ctx context.Context, userID string, policy entities.LockoutPolicy, now time.Time) (*entities.Credentials, error)
+	ResetFailedLogins(ctx context.Context, userID string) error
+}
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+}
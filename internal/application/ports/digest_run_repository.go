@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type DigestRunRepository interface {
+	// Claim records that the review digest for the UTC date of day is being
+	// sent and reports whether this call made the claim. Every later claim
+	// for the same date, from any instance, reports false.
+	Claim(ctx context.Context, day time.Time) (bool, error)
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores a pending record for its key and user. If a record that
+	// has not expired at now already exists, it is returned unchanged instead.
+	Reserve(ctx context.Context, record *entities.IdempotencyRecord, now time.Time) (*entities.IdempotencyRecord, error)
+	// Complete stores the response and expiry of a reserved record. It does
+	// nothing once another owner has taken the reservation over.
+	Complete(ctx context.Context, record *entities.IdempotencyRecord) error
+	// Release drops a pending reservation so that the request can be
+	// retried. It does nothing once another owner has taken it over.
+	Release(ctx context.Context, record *entities.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
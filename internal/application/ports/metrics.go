@@ -0,0 +1,7 @@
+package ports
+
+// AssignmentMetrics records reviewer assignment outcomes for monitoring.
+type AssignmentMetrics interface {
+	ReviewersAssigned(strategy, team string, count int)
+	NoCandidateFound(operation, team string)
+}
@@ -2,8 +2,41 @@ package ports
 
 import "context"
 
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+type MigrationDirection string
+
+const (
+	MigrationUp   MigrationDirection = "up"
+	MigrationDown MigrationDirection = "down"
+)
+
+// MigrationStep is one migration file that would run to reach a version.
+type MigrationStep struct {
+	Migration
+	Direction MigrationDirection
+	SQL       string
+}
+
 type MigrationRepository interface {
-	ApplyMigrations(ctx context.Context) error
-	RollbackMigrations(ctx context.Context) error
+	// MigrateTo applies or reverts migrations until the schema is at version;
+	// version 0 reverts everything.
+	MigrateTo(ctx context.Context, version uint) error
+	// Force records version as applied and clears the dirty flag without
+	// running any SQL; -1 marks the schema as empty.
+	Force(ctx context.Context, version int) error
 	GetCurrentVersion(ctx context.Context) (uint, bool, error)
+	// GetLatestVersion returns the newest migration bundled with the
+	// binary, i.e. the schema version the code expects.
+	GetLatestVersion(ctx context.Context) (uint, error)
+	// ListMigrations returns the bundled migrations in version order.
+	ListMigrations(ctx context.Context) ([]Migration, error)
+	// Plan returns the steps MigrateTo(version) would run, in order.
+	Plan(ctx context.Context, version uint) ([]MigrationStep, error)
+	// Lock blocks until no other instance holds the schema lock and
+	// returns the function that releases it.
+	Lock(ctx context.Context) (func(), error)
 }
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type ReviewersAssignedEvent struct {
+	PR        *entities.PullRequest
+	Team      *entities.Team
+	Reviewers []*entities.User
+}
+
+type Notifier interface {
+	NotifyReviewersAssigned(ctx context.Context, event ReviewersAssignedEvent) error
+}
+
+type ReviewAssignment struct {
+	PR          *entities.PullRequest
+	Age         time.Duration
+	SLABreached bool
+}
+
+type ReviewDigest struct {
+	Reviewer    *entities.User
+	Assignments []ReviewAssignment
+	SLA         time.Duration
+	GeneratedAt time.Time
+}
+
+type DigestSender interface {
+	SendDigest(ctx context.Context, digest ReviewDigest) error
+}
@@ -2,13 +2,65 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
 type PRRepository interface {
 	Save(ctx context.Context, pr *entities.PullRequest) error
+	// SaveWithDecision saves the pull request together with the assignment
+	// decision that picked its reviewers, so neither is stored without the
+	// other.
+	SaveWithDecision(ctx context.Context, pr *entities.PullRequest, decision *entities.AssignmentDecision) error
 	GetByID(ctx context.Context, id string) (*entities.PullRequest, error)
 	ExistsByID(ctx context.Context, id string) (bool, error)
-	GetByReviewer(ctx context.Context, userID string) ([]*entities.PullRequest, error)
+	// Find returns the pull requests matching query, with their reviewers.
+	Find(ctx context.Context, query PRQuery) ([]*entities.PullRequest, error)
+	// Count returns how many pull requests match query's filters, ignoring
+	// its cursor and limit.
+	Count(ctx context.Context, query PRQuery) (int, error)
+	ListOpen(ctx context.Context) ([]*entities.PullRequest, error)
+	// CountOpenByTeam returns the number of open pull requests per author
+	// team.
+	CountOpenByTeam(ctx context.Context) (map[string]int, error)
+	// CountOpenReviews returns how many open pull requests each member of
+	// the team reviews; members without open reviews are left out.
+	CountOpenReviews(ctx context.Context, teamName string) (map[string]int, error)
+}
+
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// PRCursor is the sort key of the last pull request on the previous page.
+type PRCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// PRQuery selects pull requests ordered by creation time, ties broken by ID.
+// Zero-valued fields do not filter.
+type PRQuery struct {
+	ReviewerID string
+	AuthorID   string
+	// AuthorIDs matches pull requests by any of the listed authors.
+	AuthorIDs []string
+	Status    entities.PRStatus
+	Label     string
+	// Search matches names containing a word that starts with each word of
+	// the search, ignoring case.
+	Search string
+	// CreatedFrom is inclusive, CreatedTo exclusive.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Order defaults to ascending.
+	Order SortOrder
+	// After skips everything up to and including the cursor position.
+	After *PRCursor
+	// Limit caps the number of results; zero means no limit.
+	Limit int
 }
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type RefreshTokenRepository interface {
+	Save(ctx context.Context, token *entities.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+	// Consume revokes the token unless it already is, and reports whether
+	// this call revoked it. Only one of several concurrent calls succeeds.
+	Consume(ctx context.Context, tokenHash string, at time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
@@ -10,4 +10,6 @@ type TeamRepository interface {
 	Save(ctx context.Context, team *entities.Team) error
 	GetByName(ctx context.Context, name string) (*entities.Team, error)
 	ExistsByName(ctx context.Context, name string) (bool, error)
+	// List returns every team with its members, ordered by name.
+	List(ctx context.Context) ([]*entities.Team, error)
 }
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// TeamSyncRepository applies a team sync plan atomically: either every change
+// is stored or none is. A user planned for removal who has gained pull request
+// history since planning is deactivated instead of deleted, and deleting a team
+// that still has members fails.
+type TeamSyncRepository interface {
+	Apply(ctx context.Context, plan *entities.TeamSyncPlan) error
+}
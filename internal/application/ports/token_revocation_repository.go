@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type TokenRevocationRepository interface {
+	RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error
+	RevokeUser(ctx context.Context, userID string, revokedAt time.Time) error
+	// Load returns every revocation that can still affect a token at now.
+	Load(ctx context.Context, now time.Time) (*entities.TokenDenylist, error)
+}
+
+// SessionRevoker invalidates issued access and refresh tokens.
+type SessionRevoker interface {
+	RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+}
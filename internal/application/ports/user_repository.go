@@ -11,4 +11,6 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*entities.User, error)
 	ExistsByID(ctx context.Context, id string) (bool, error)
 	GetByTeamName(ctx context.Context, teamName string) ([]*entities.User, error)
+	// List returns every user ordered by ID.
+	List(ctx context.Context) ([]*entities.User, error)
 }
@@ -0,0 +1,25 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+type CountOpenPRsByTeamQuery struct {
+	prRepo ports.PRRepository
+}
+
+func NewCountOpenPRsByTeamQuery(prRepo ports.PRRepository) *CountOpenPRsByTeamQuery {
+	return &CountOpenPRsByTeamQuery{prRepo: prRepo}
+}
+
+// Execute returns the number of open pull requests per author team.
+func (q *CountOpenPRsByTeamQuery) Execute(ctx context.Context) (map[string]int, error) {
+	counts, err := q.prRepo.CountOpenByTeam(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("counting open prs: %w", err)
+	}
+	return counts, nil
+}
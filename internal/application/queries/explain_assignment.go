@@ -0,0 +1,42 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// AssignmentExplanation is a pull request with the decisions behind its
+// reviewers, oldest first. Pull requests created before decisions were
+// recorded have none.
+type AssignmentExplanation struct {
+	PR        *entities.PullRequest
+	Decisions []*entities.AssignmentDecision
+}
+
+type ExplainAssignmentQuery struct {
+	prRepo       ports.PRRepository
+	decisionRepo ports.AssignmentDecisionRepository
+}
+
+func NewExplainAssignmentQuery(prRepo ports.PRRepository, decisionRepo ports.AssignmentDecisionRepository) *ExplainAssignmentQuery {
+	return &ExplainAssignmentQuery{prRepo: prRepo, decisionRepo: decisionRepo}
+}
+
+func (q *ExplainAssignmentQuery) Execute(ctx context.Context, prID string) (*AssignmentExplanation, error) {
+	pr, err := q.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("getting pr: %w", err)
+	}
+	if pr == nil {
+		return nil, entities.ErrPRNotFound
+	}
+
+	decisions, err := q.decisionRepo.ListByPullRequest(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("listing assignment decisions: %w", err)
+	}
+	return &AssignmentExplanation{PR: pr, Decisions: decisions}, nil
+}
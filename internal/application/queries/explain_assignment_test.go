@@ -0,0 +1,65 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+func TestExplainAssignment(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewInMemoryStore()
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	pr := entities.NewPullRequest("pr-1", "Add search", "u1", []string{"u3"})
+	if err := store.PRs.Save(ctx, pr); err != nil {
+		t.Fatalf("failed to save pr: %v", err)
+	}
+	decisions := []*entities.AssignmentDecision{
+		{
+			PullRequestID:      "pr-1",
+			Kind:               entities.AssignmentReassign,
+			ReplacedReviewerID: "u2",
+			Strategy:           "random",
+			Seed:               2,
+			Reviewers:          []string{"u3"},
+			CreatedAt:          created.Add(time.Hour),
+		},
+		{
+			PullRequestID: "pr-1",
+			Kind:          entities.AssignmentCreate,
+			Strategy:      "random",
+			Seed:          1,
+			Reviewers:     []string{"u2"},
+			CreatedAt:     created,
+		},
+	}
+	for _, decision := range decisions {
+		if err := store.Decisions.Save(ctx, decision); err != nil {
+			t.Fatalf("failed to save decision: %v", err)
+		}
+	}
+	query := queries.NewExplainAssignmentQuery(store.PRs, store.Decisions)
+
+	explanation, err := query.Execute(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if explanation.PR.ID != "pr-1" || len(explanation.Decisions) != 2 {
+		t.Fatalf("expected pr-1 with 2 decisions, got %+v", explanation)
+	}
+	kinds := []entities.AssignmentKind{explanation.Decisions[0].Kind, explanation.Decisions[1].Kind}
+	if !slices.Equal(kinds, []entities.AssignmentKind{entities.AssignmentCreate, entities.AssignmentReassign}) {
+		t.Errorf("expected decisions oldest first, got %v", kinds)
+	}
+
+	if _, err := query.Execute(ctx, "missing"); !errors.Is(err, entities.ErrPRNotFound) {
+		t.Errorf("expected ErrPRNotFound, got %v", err)
+	}
+}
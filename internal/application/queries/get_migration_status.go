@@ -2,6 +2,7 @@ package queries
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
@@ -10,6 +11,9 @@ import (
 type MigrationStatus struct {
 	Version uint
 	Dirty   bool
+	// Latest is the newest migration bundled with the binary.
+	Latest  uint
+	Pending []ports.Migration
 }
 
 type GetMigrationStatusQuery struct {
@@ -25,23 +29,22 @@ func NewGetMigrationStatusQuery(migrationRepo ports.MigrationRepository, logger
 }
 
 func (q *GetMigrationStatusQuery) Execute(ctx context.Context) (*MigrationStatus, error) {
-	q.logger.Info("Executing query: getting migration status")
-
 	version, dirty, err := q.migrationRepo.GetCurrentVersion(ctx)
 	if err != nil {
-		q.logger.Error("Failed to get migration status", "error", err)
-		return nil, err
+		return nil, fmt.Errorf("getting current version: %w", err)
 	}
 
-	status := &MigrationStatus{
-		Version: version,
-		Dirty:   dirty,
+	migrations, err := q.migrationRepo.ListMigrations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing migrations: %w", err)
 	}
 
-	q.logger.Info("Query executed successfully",
-		"version", version,
-		"dirty", dirty,
-	)
-
+	status := &MigrationStatus{Version: version, Dirty: dirty}
+	for _, m := range migrations {
+		if m.Version > version {
+			status.Pending = append(status.Pending, m)
+		}
+		status.Latest = m.Version
+	}
 	return status, nil
 }
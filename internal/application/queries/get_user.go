@@ -0,0 +1,28 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type GetUserQuery struct {
+	userRepo ports.UserRepository
+}
+
+func NewGetUserQuery(userRepo ports.UserRepository) *GetUserQuery {
+	return &GetUserQuery{userRepo: userRepo}
+}
+
+func (q *GetUserQuery) Execute(ctx context.Context, userID string) (*entities.User, error) {
+	user, err := q.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return nil, entities.ErrUserNotFound
+	}
+	return user, nil
+}
@@ -3,11 +3,32 @@ package queries
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
+// UserReviewsFilter narrows the pull requests a user reviews. Zero-valued
+// fields do not filter; Order defaults to newest first. Without a Limit or a
+// Cursor every match is returned on one page, as before pagination existed.
+type UserReviewsFilter struct {
+	UserID      string
+	Status      entities.PRStatus
+	AuthorID    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Order       ports.SortOrder
+	Cursor      string
+	Limit       int
+}
+
+type UserReviewsPage struct {
+	PullRequests []*entities.PullRequest
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
 type GetUserReviewsQuery struct {
 	prRepo ports.PRRepository
 }
@@ -16,10 +37,38 @@ func NewGetUserReviewsQuery(prRepo ports.PRRepository) *GetUserReviewsQuery {
 	return &GetUserReviewsQuery{prRepo: prRepo}
 }
 
-func (q *GetUserReviewsQuery) Execute(ctx context.Context, userID string) ([]*entities.PullRequest, error) {
-	prs, err := q.prRepo.GetByReviewer(ctx, userID)
+func (q *GetUserReviewsQuery) Execute(ctx context.Context, filter UserReviewsFilter) (*UserReviewsPage, error) {
+	after, err := decodePRCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	order := filter.Order
+	if order == "" {
+		order = ports.SortDescending
+	}
+	query := ports.PRQuery{
+		ReviewerID:  filter.UserID,
+		AuthorID:    filter.AuthorID,
+		Status:      filter.Status,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+		Order:       order,
+		After:       after,
+	}
+	paginated := filter.Limit > 0 || filter.Cursor != ""
+	limit := pageSize(filter.Limit)
+	if paginated {
+		query.Limit = limit + 1
+	}
+
+	prs, err := q.prRepo.Find(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("getting prs by reviewer: %w", err)
+		return nil, fmt.Errorf("finding prs by reviewer: %w", err)
+	}
+	if !paginated {
+		return &UserReviewsPage{PullRequests: prs}, nil
 	}
-	return prs, nil
+
+	page, next := pagePRs(prs, limit)
+	return &UserReviewsPage{PullRequests: page, NextCursor: next}, nil
 }
@@ -0,0 +1,83 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+func TestGetUserReviewsPagesThroughResults(t *testing.T) {
+	ctx := context.Background()
+	prRepo := repositories.NewInMemoryPRRepository(repositories.NewInMemoryUserRepository(), repositories.NewInMemoryAssignmentDecisionRepository())
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	for i := 1; i <= 5; i++ {
+		pr := entities.NewPullRequest(fmt.Sprintf("pr-%d", i), "PR", "u1", []string{"u2"})
+		pr.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		if err := prRepo.Save(ctx, pr); err != nil {
+			t.Fatalf("failed to save pr: %v", err)
+		}
+	}
+
+	query := queries.NewGetUserReviewsQuery(prRepo)
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("expected three pages, got more: %v", got)
+		}
+		page, err := query.Execute(ctx, queries.UserReviewsFilter{UserID: "u2", Cursor: cursor, Limit: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, pr := range page.PullRequests {
+			got = append(got, pr.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if want := []string{"pr-5", "pr-4", "pr-3", "pr-2", "pr-1"}; !slices.Equal(got, want) {
+		t.Errorf("expected newest first %v, got %v", want, got)
+	}
+}
+
+func TestGetUserReviewsReturnsEverythingWithoutPagination(t *testing.T) {
+	ctx := context.Background()
+	prRepo := repositories.NewInMemoryPRRepository(repositories.NewInMemoryUserRepository(), repositories.NewInMemoryAssignmentDecisionRepository())
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	for i := 1; i <= 120; i++ {
+		pr := entities.NewPullRequest(fmt.Sprintf("pr-%03d", i), "PR", "u1", []string{"u2"})
+		pr.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := prRepo.Save(ctx, pr); err != nil {
+			t.Fatalf("failed to save pr: %v", err)
+		}
+	}
+
+	page, err := queries.NewGetUserReviewsQuery(prRepo).Execute(ctx, queries.UserReviewsFilter{UserID: "u2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.PullRequests) != 120 || page.NextCursor != "" {
+		t.Fatalf("expected all 120 pull requests on one page, got %d (next cursor %q)", len(page.PullRequests), page.NextCursor)
+	}
+	if first := page.PullRequests[0].ID; first != "pr-120" {
+		t.Errorf("expected newest first, got %s", first)
+	}
+}
+
+func TestGetUserReviewsRejectsInvalidCursor(t *testing.T) {
+	query := queries.NewGetUserReviewsQuery(repositories.NewInMemoryPRRepository(repositories.NewInMemoryUserRepository(), repositories.NewInMemoryAssignmentDecisionRepository()))
+	_, err := query.Execute(context.Background(), queries.UserReviewsFilter{UserID: "u2", Cursor: "not a cursor"})
+	if !errors.Is(err, entities.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
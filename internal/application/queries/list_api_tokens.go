@@ -0,0 +1,25 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type ListAPITokensQuery struct {
+	tokenRepo ports.APITokenRepository
+}
+
+func NewListAPITokensQuery(tokenRepo ports.APITokenRepository) *ListAPITokensQuery {
+	return &ListAPITokensQuery{tokenRepo: tokenRepo}
+}
+
+func (q *ListAPITokensQuery) Execute(ctx context.Context) ([]*entities.APIToken, error) {
+	tokens, err := q.tokenRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing api tokens: %w", err)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,133 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+// PRListFilter narrows the pull request list. Zero-valued fields do not
+// filter; Order defaults to newest first. TeamName keeps pull requests
+// authored by current members of the team. MinAge and MaxAge bound the age of
+// a pull request relative to now and combine with CreatedFrom and CreatedTo.
+type PRListFilter struct {
+	TeamName    string
+	AuthorID    string
+	ReviewerID  string
+	Status      entities.PRStatus
+	Label       string
+	Search      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MinAge      time.Duration
+	MaxAge      time.Duration
+	Order       ports.SortOrder
+	Cursor      string
+	Limit       int
+}
+
+type PRListPage struct {
+	PullRequests []*entities.PullRequest
+	// NextCursor is empty on the last page.
+	NextCursor string
+	// Total counts every pull request matching the filter, across all pages.
+	Total int
+}
+
+type ListPullRequestsQuery struct {
+	prRepo   ports.PRRepository
+	teamRepo ports.TeamRepository
+	clock    services.Clock
+}
+
+func NewListPullRequestsQuery(
+	prRepo ports.PRRepository,
+	teamRepo ports.TeamRepository,
+	clock services.Clock,
+) *ListPullRequestsQuery {
+	return &ListPullRequestsQuery{
+		prRepo:   prRepo,
+		teamRepo: teamRepo,
+		clock:    clock,
+	}
+}
+
+func (q *ListPullRequestsQuery) Execute(ctx context.Context, filter PRListFilter) (*PRListPage, error) {
+	after, err := decodePRCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	order := filter.Order
+	if order == "" {
+		order = ports.SortDescending
+	}
+	limit := pageSize(filter.Limit)
+
+	query := ports.PRQuery{
+		ReviewerID:  filter.ReviewerID,
+		AuthorID:    filter.AuthorID,
+		Status:      filter.Status,
+		Label:       filter.Label,
+		Search:      filter.Search,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+		Order:       order,
+	}
+
+	if filter.TeamName != "" {
+		team, err := q.teamRepo.GetByName(ctx, filter.TeamName)
+		if err != nil {
+			return nil, fmt.Errorf("getting team: %w", err)
+		}
+		if team == nil {
+			return nil, entities.ErrTeamNotFound
+		}
+		if len(team.Members) == 0 {
+			return &PRListPage{}, nil
+		}
+		for _, member := range team.Members {
+			query.AuthorIDs = append(query.AuthorIDs, member.ID)
+		}
+	}
+
+	now := q.clock.Now().UTC()
+	if filter.MinAge > 0 {
+		query.CreatedTo = earliest(query.CreatedTo, now.Add(-filter.MinAge))
+	}
+	if filter.MaxAge > 0 {
+		query.CreatedFrom = latest(query.CreatedFrom, now.Add(-filter.MaxAge))
+	}
+
+	total, err := q.prRepo.Count(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("counting prs: %w", err)
+	}
+
+	query.After = after
+	query.Limit = limit + 1
+	prs, err := q.prRepo.Find(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("finding prs: %w", err)
+	}
+
+	page, next := pagePRs(prs, limit)
+	return &PRListPage{PullRequests: page, NextCursor: next, Total: total}, nil
+}
+
+func earliest(bound *time.Time, t time.Time) *time.Time {
+	if bound != nil && bound.Before(t) {
+		return bound
+	}
+	return &t
+}
+
+func latest(bound *time.Time, t time.Time) *time.Time {
+	if bound != nil && bound.After(t) {
+		return bound
+	}
+	return &t
+}
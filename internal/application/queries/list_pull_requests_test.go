@@ -0,0 +1,106 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+func newListPullRequestsQuery(t *testing.T, now time.Time) *queries.ListPullRequestsQuery {
+	t.Helper()
+	ctx := context.Background()
+	store := repositories.NewInMemoryStore()
+
+	backend := []*entities.User{
+		entities.NewUser("u1", "alice", "backend", true),
+		entities.NewUser("u2", "bob", "backend", true),
+	}
+	frontend := []*entities.User{entities.NewUser("u3", "carol", "frontend", true)}
+	for _, team := range []*entities.Team{entities.NewTeam("backend", backend), entities.NewTeam("frontend", frontend)} {
+		if err := store.Teams.Save(ctx, team); err != nil {
+			t.Fatalf("failed to save team: %v", err)
+		}
+	}
+
+	// pr-1 is ten days old, pr-2 three days and pr-3 one hour.
+	ages := []time.Duration{10 * 24 * time.Hour, 3 * 24 * time.Hour, time.Hour}
+	authors := []string{"u1", "u3", "u2"}
+	for i, age := range ages {
+		pr := entities.NewPullRequest(fmt.Sprintf("pr-%d", i+1), "PR", authors[i], nil)
+		pr.CreatedAt = now.Add(-age)
+		if err := store.PRs.Save(ctx, pr); err != nil {
+			t.Fatalf("failed to save pr: %v", err)
+		}
+	}
+
+	return queries.NewListPullRequestsQuery(store.PRs, store.Teams, fixedClock{now: now})
+}
+
+func TestListPullRequestsFiltersByTeamAndAge(t *testing.T) {
+	now := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+	query := newListPullRequestsQuery(t, now)
+
+	tests := []struct {
+		name   string
+		filter queries.PRListFilter
+		want   []string
+	}{
+		{"all", queries.PRListFilter{}, []string{"pr-3", "pr-2", "pr-1"}},
+		{"team", queries.PRListFilter{TeamName: "backend"}, []string{"pr-3", "pr-1"}},
+		{"min age", queries.PRListFilter{MinAge: 2 * 24 * time.Hour}, []string{"pr-2", "pr-1"}},
+		{"max age", queries.PRListFilter{MaxAge: 7 * 24 * time.Hour}, []string{"pr-3", "pr-2"}},
+		{"team and age", queries.PRListFilter{TeamName: "backend", MaxAge: 7 * 24 * time.Hour}, []string{"pr-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := query.Execute(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, pr := range page.PullRequests {
+				got = append(got, pr.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if page.Total != len(tt.want) {
+				t.Errorf("expected total %d, got %d", len(tt.want), page.Total)
+			}
+		})
+	}
+}
+
+func TestListPullRequestsTotalSpansPages(t *testing.T) {
+	query := newListPullRequestsQuery(t, time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC))
+
+	page, err := query.Execute(context.Background(), queries.PRListFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.PullRequests) != 2 || page.NextCursor == "" || page.Total != 3 {
+		t.Errorf("expected two of three prs and a cursor, got %d (total %d, cursor %q)",
+			len(page.PullRequests), page.Total, page.NextCursor)
+	}
+}
+
+func TestListPullRequestsRejectsUnknownTeam(t *testing.T) {
+	query := newListPullRequestsQuery(t, time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC))
+
+	_, err := query.Execute(context.Background(), queries.PRListFilter{TeamName: "missing"})
+	if !errors.Is(err, entities.ErrTeamNotFound) {
+		t.Errorf("expected ErrTeamNotFound, got %v", err)
+	}
+}
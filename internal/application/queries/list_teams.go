@@ -0,0 +1,25 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type ListTeamsQuery struct {
+	teamRepo ports.TeamRepository
+}
+
+func NewListTeamsQuery(teamRepo ports.TeamRepository) *ListTeamsQuery {
+	return &ListTeamsQuery{teamRepo: teamRepo}
+}
+
+func (q *ListTeamsQuery) Execute(ctx context.Context) ([]*entities.Team, error) {
+	teams, err := q.teamRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing teams: %w", err)
+	}
+	return teams, nil
+}
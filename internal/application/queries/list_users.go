@@ -0,0 +1,25 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type ListUsersQuery struct {
+	userRepo ports.UserRepository
+}
+
+func NewListUsersQuery(userRepo ports.UserRepository) *ListUsersQuery {
+	return &ListUsersQuery{userRepo: userRepo}
+}
+
+func (q *ListUsersQuery) Execute(ctx context.Context) ([]*entities.User, error) {
+	users, err := q.userRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	return users, nil
+}
@@ -0,0 +1,62 @@
+package queries
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageSize applies the default to an unset limit and caps large ones.
+func pageSize(limit int) int {
+	switch {
+	case limit <= 0:
+		return defaultPageSize
+	case limit > maxPageSize:
+		return maxPageSize
+	default:
+		return limit
+	}
+}
+
+// encodePRCursor returns an opaque cursor pointing just past pr.
+func encodePRCursor(pr *entities.PullRequest) string {
+	raw := pr.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + pr.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePRCursor(cursor string) (*ports.PRCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, entities.ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, entities.ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, entities.ErrInvalidCursor
+	}
+	return &ports.PRCursor{CreatedAt: t, ID: id}, nil
+}
+
+// pagePRs trims a result fetched with one extra row down to limit and returns
+// the cursor of the next page, or "" when nothing follows.
+func pagePRs(prs []*entities.PullRequest, limit int) ([]*entities.PullRequest, string) {
+	if len(prs) <= limit {
+		return prs, ""
+	}
+	prs = prs[:limit]
+	return prs, encodePRCursor(prs[len(prs)-1])
+}
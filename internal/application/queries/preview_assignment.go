@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+// AssignmentPreview is what creating a pull request would assign right now.
+// The decision has no reviewers when there are no candidates, where creating
+// the pull request would fail.
+type AssignmentPreview struct {
+	AuthorID string
+	TeamName string
+	Decision *entities.AssignmentDecision
+}
+
+type PreviewAssignmentQuery struct {
+	teamRepo          ports.TeamRepository
+	userRepo          ports.UserRepository
+	prRepo            ports.PRRepository
+	assignmentService *services.ReviewerAssignmentService
+}
+
+func NewPreviewAssignmentQuery(
+	teamRepo ports.TeamRepository,
+	userRepo ports.UserRepository,
+	prRepo ports.PRRepository,
+	assignmentService *services.ReviewerAssignmentService,
+) *PreviewAssignmentQuery {
+	return &PreviewAssignmentQuery{
+		teamRepo:          teamRepo,
+		userRepo:          userRepo,
+		prRepo:            prRepo,
+		assignmentService: assignmentService,
+	}
+}
+
+// Execute runs reviewer selection for a pull request by authorID the way
+// CreatePRCommand does, without saving anything. Selection draws a fresh seed,
+// so the chosen reviewers are one possible draw; only the candidates and
+// exclusions match what creating the pull request would see.
+func (q *PreviewAssignmentQuery) Execute(ctx context.Context, authorID string) (*AssignmentPreview, error) {
+	author, err := q.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("getting author: %w", err)
+	}
+	if author == nil {
+		return nil, entities.ErrUserNotFound
+	}
+
+	team, err := q.teamRepo.GetByName(ctx, author.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("getting team: %w", err)
+	}
+	if team == nil {
+		return nil, entities.ErrTeamNotFound
+	}
+
+	openReviews, err := q.prRepo.CountOpenReviews(ctx, team.Name)
+	if err != nil {
+		return nil, fmt.Errorf("counting open reviews: %w", err)
+	}
+
+	return &AssignmentPreview{
+		AuthorID: authorID,
+		TeamName: team.Name,
+		Decision: q.assignmentService.Assign(team, authorID, openReviews),
+	}, nil
+}
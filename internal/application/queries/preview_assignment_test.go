@@ -0,0 +1,70 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+type fixedSeed int64
+
+func (s fixedSeed) Seed() int64 { return int64(s) }
+
+func TestPreviewAssignment(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewInMemoryStore()
+	members := []*entities.User{
+		entities.NewUser("u1", "alice", "backend", true),
+		entities.NewUser("u2", "bob", "backend", false),
+		entities.NewUser("u3", "carol", "backend", true),
+	}
+	if err := store.Teams.Save(ctx, entities.NewTeam("backend", members)); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	if err := store.Teams.Save(ctx, entities.NewTeam("solo", []*entities.User{entities.NewUser("u4", "dave", "solo", true)})); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	query := queries.NewPreviewAssignmentQuery(store.Teams, store.Users, store.PRs, services.NewReviewerAssignmentService(fixedSeed(1), 0))
+
+	preview, err := query.Execute(ctx, "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decision := preview.Decision
+	if preview.TeamName != "backend" || decision.Strategy != services.RandomStrategy || decision.Seed != 1 {
+		t.Errorf("unexpected preview %+v", decision)
+	}
+	if !slices.Equal(decision.Reviewers, []string{"u3"}) || len(decision.Candidates) != 1 || decision.Candidates[0].UserID != "u3" {
+		t.Errorf("expected u3 to be the only candidate and reviewer, got %v from %v", decision.Reviewers, decision.Candidates)
+	}
+	wantExcluded := []entities.ReviewerExclusion{
+		{UserID: "u1", Reason: entities.ExclusionAuthor},
+		{UserID: "u2", Reason: entities.ExclusionInactive},
+	}
+	if !slices.Equal(decision.Excluded, wantExcluded) {
+		t.Errorf("expected exclusions %v, got %v", wantExcluded, decision.Excluded)
+	}
+	if prs, _ := store.PRs.Find(ctx, ports.PRQuery{}); len(prs) != 0 {
+		t.Errorf("expected nothing to be saved, got %d pull requests", len(prs))
+	}
+
+	// Without candidates the preview explains why instead of failing.
+	preview, err = query.Execute(ctx, "u4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview.Decision.Reviewers) != 0 || len(preview.Decision.Excluded) != 1 {
+		t.Errorf("expected no reviewers and the author excluded, got %+v", preview.Decision)
+	}
+
+	if _, err := query.Execute(ctx, "ghost"); !errors.Is(err, entities.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+type VerifyAPITokenQuery struct {
+	tokenRepo ports.APITokenRepository
+	userRepo  ports.UserRepository
+	clock     services.Clock
+}
+
+func NewVerifyAPITokenQuery(
+	tokenRepo ports.APITokenRepository,
+	userRepo ports.UserRepository,
+	clock services.Clock,
+) *VerifyAPITokenQuery {
+	return &VerifyAPITokenQuery{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		clock:     clock,
+	}
+}
+
+// Execute resolves a raw API token. Unknown, revoked and expired tokens, as
+// well as tokens of deactivated service accounts, yield ErrAPITokenInvalid.
+func (q *VerifyAPITokenQuery) Execute(ctx context.Context, raw string) (*entities.APIToken, error) {
+	token, err := q.tokenRepo.GetByHash(ctx, entities.HashAPIToken(raw))
+	if err != nil {
+		return nil, fmt.Errorf("getting api token: %w", err)
+	}
+	if token == nil || token.IsRevoked() || token.IsExpired(q.clock.Now().UTC()) {
+		return nil, entities.ErrAPITokenInvalid
+	}
+
+	user, err := q.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil || !user.IsActive {
+		return nil, entities.ErrAPITokenInvalid
+	}
+
+	return token, nil
+}
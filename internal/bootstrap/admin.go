@@ -0,0 +1,85 @@
+package bootstrap
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+)
+
+const adminTeamName = "admin-team"
+
+// runAdminCommand handles `bootstrap-admin <user_id> <username>`, which
+// creates (or re-activates) a user with the admin role and sets its password. The
+// password is taken from ADMIN_PASSWORD or read from the first line of stdin.
+func runAdminCommand(store *storage, logger *slog.Logger, cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if len(cfg.Command.Args) != 2 {
+		logger.Error("usage: bootstrap-admin <user_id> <username>")
+		os.Exit(1)
+	}
+	userID, username := cfg.Command.Args[0], cfg.Command.Args[1]
+
+	password, err := readAdminPassword()
+	if err != nil {
+		logger.Error("failed to read admin password", "error", err)
+		os.Exit(1)
+	}
+
+	teamRepo := store.teams
+	userRepo := store.users
+	credentialRepo := store.credentials
+	hasher := security.NewBcryptHasher(cfg.Auth.BcryptCost)
+
+	admin := entities.NewUser(userID, username, adminTeamName, true)
+	if existing, err := userRepo.GetByID(ctx, userID); err != nil {
+		logger.Error("failed to look up admin user", "error", err)
+		os.Exit(1)
+	} else if existing != nil {
+		admin = existing
+		admin.SetActive(true)
+	}
+	admin.SetRole(entities.RoleAdmin)
+
+	if err := teamRepo.Save(ctx, entities.NewTeam(admin.TeamName, []*entities.User{admin})); err != nil {
+		logger.Error("failed to save admin team", "error", err)
+		os.Exit(1)
+	}
+	// Team saves never touch the role of an existing member, so persist it
+	// through the user repository.
+	if err := userRepo.Save(ctx, admin); err != nil {
+		logger.Error("failed to save admin user", "error", err)
+		os.Exit(1)
+	}
+
+	setPasswordCmd := commands.NewSetPasswordCommand(userRepo, credentialRepo, hasher, services.NewRealClock())
+	if err := setPasswordCmd.Execute(ctx, userID, password); err != nil {
+		logger.Error("failed to set admin password", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Administrator provisioned", "user_id", userID)
+}
+
+func readAdminPassword() (string, error) {
+	if password := os.Getenv("ADMIN_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no password provided on stdin or in ADMIN_PASSWORD")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,68 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	apphttp "github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/http"
+)
+
+func buildTokenManager(cfg config.AuthConfig) (*apphttp.TokenManager, error) {
+	secret := cfg.JWTSecret
+	if cfg.JWTSecretFile != "" {
+		content, err := os.ReadFile(cfg.JWTSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwt secret file: %w", err)
+		}
+		secret = strings.TrimSpace(string(content))
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("JWT_SECRET or JWT_SECRET_FILE must be set")
+	}
+
+	previous, err := parseKeyList(cfg.JWTPreviousKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return apphttp.NewTokenManager(apphttp.TokenConfig{
+		ActiveKey:        apphttp.SigningKey{ID: cfg.JWTKeyID, Secret: []byte(secret)},
+		VerificationKeys: previous,
+		Issuer:           cfg.JWTIssuer,
+		Audience:         cfg.JWTAudience,
+		AccessTokenTTL:   cfg.AccessTokenTTL,
+		RefreshTokenTTL:  cfg.RefreshTokenTTL,
+	})
+}
+
+// buildOIDCVerifier returns nil when no identity provider is configured.
+func buildOIDCVerifier(cfg config.AuthConfig) (*apphttp.OIDCVerifier, error) {
+	if cfg.OIDCJWKSURL == "" {
+		return nil, nil
+	}
+	return apphttp.NewOIDCVerifier(apphttp.OIDCConfig{
+		JWKSURL:     cfg.OIDCJWKSURL,
+		Issuer:      cfg.OIDCIssuer,
+		Audience:    cfg.OIDCAudience,
+		UserIDClaim: cfg.OIDCUserIDClaim,
+		CacheTTL:    cfg.OIDCJWKSCacheTTL,
+	})
+}
+
+func parseKeyList(value string) ([]apphttp.SigningKey, error) {
+	var keys []apphttp.SigningKey
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(pair, "=")
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("invalid jwt key %q, expected kid=secret", pair)
+		}
+		keys = append(keys, apphttp.SigningKey{ID: kid, Secret: []byte(secret)})
+	}
+	return keys, nil
+}
@@ -5,16 +5,22 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"log/slog"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/health"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/http"
-	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/metrics"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/tracing"
 
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
 
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
 )
 
@@ -26,39 +32,184 @@ func Run() {
 
 	cfg := config.Load(logger)
 
-	db, err := config.NewPostgresConnection(&cfg.DB)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
-		logger.Error("DB connection failed", "error", err)
+		logger.Error("failed to configure tracing", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	store, err := openStorage(cfg, logger)
+	if err != nil {
+		logger.Error("DB connection failed", "driver", cfg.DB.Driver, "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("failed to close storage", "error", err)
+		}
+	}()
 
 	if cfg.Command.IsMigrationCommand() {
-		runMigrationCommand(db, logger, cfg)
+		runMigrationCommand(store, logger, cfg)
+		return
+	}
+
+	if cfg.Command.IsAdminCommand() {
+		runAdminCommand(store, logger, cfg)
+		return
+	}
+
+	if cfg.Command.IsSyncTeamsCommand() {
+		runSyncTeamsCommand(store, logger, cfg)
 		return
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// --- Schema ---
+	migrationRepo := store.migrations
+	if migrationRepo != nil {
+		if err := ensureSchema(ctx, migrationRepo, cfg.DB, logger); err != nil {
+			logger.Error("schema check failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// --- Repositories ---
-	teamRepo := repositories.NewPostgresTeamRepository(db)
-	userRepo := repositories.NewPostgresUserRepository(db)
-	prRepo := repositories.NewPostgresPRRepository(db)
+	teamRepo := store.teams
+	userRepo := store.users
+	prRepo := store.prs
+	refreshTokenRepo := store.refreshTokens
+	credentialRepo := store.credentials
+	apiTokenRepo := store.apiTokens
+	revocationRepo := store.revocations
+	teamSyncRepo := store.teamSync
+	decisionRepo := store.decisions
+	digestRunRepo := store.digestRuns
+
+	// --- Auth ---
+	tokens, err := buildTokenManager(cfg.Auth)
+	if err != nil {
+		logger.Error("failed to configure authentication", "error", err)
+		os.Exit(1)
+	}
+	oidc, err := buildOIDCVerifier(cfg.Auth)
+	if err != nil {
+		logger.Error("failed to configure oidc", "error", err)
+		os.Exit(1)
+	}
 
 	// --- Domain Services ---
+	clock := services.NewRealClock()
 	randomizer := services.NewDefaultRandomizer()
-	assignmentService := services.NewReviewerAssignmentService(randomizer)
+	assignmentService := services.NewReviewerAssignmentService(randomizer, cfg.Assignment.MaxOpenReviews)
+
+	// --- Token revocation ---
+	denylist := security.NewDenylist(revocationRepo, refreshTokenRepo, clock, logger)
+	if err := denylist.Reload(ctx); err != nil {
+		logger.Error("failed to load token denylist", "error", err)
+		os.Exit(1)
+	}
+	go denylist.Run(ctx, cfg.Auth.DenylistReloadInterval)
+
+	// --- Rate limiting ---
+	rateLimiter, rateLimits, err := buildRateLimiter(ctx, cfg.RateLimit, store, logger)
+	if err != nil {
+		logger.Error("failed to configure rate limiting", "error", err)
+		os.Exit(1)
+	}
+
+	go pruneIdempotencyKeys(ctx, store.idempotency, logger)
+
+	// --- Health ---
+	readiness := health.NewChecker(cfg.Server.ReadinessTimeout)
+	if store.db != nil {
+		readiness.Add("database", health.DatabaseCheck(store.db))
+		readiness.Add("migrations", health.MigrationCheck(migrationRepo))
+	}
+
+	// --- Metrics ---
+	appMetrics := metrics.New()
+	if store.db != nil {
+		if err := appMetrics.RegisterDatabase(store.db, store.driver, migrationRepo, logger); err != nil {
+			logger.Error("failed to register database metrics", "error", err)
+			os.Exit(1)
+		}
+	}
+	if err := appMetrics.RegisterOpenPRs(queries.NewCountOpenPRsByTeamQuery(prRepo), logger); err != nil {
+		logger.Error("failed to register pull request metrics", "error", err)
+		os.Exit(1)
+	}
+
+	// --- Notifications ---
+	notifier, digestSender, err := buildNotifier(cfg.Notifications, logger)
+	if err != nil {
+		logger.Error("failed to configure notifications", "error", err)
+		os.Exit(1)
+	}
 
 	// --- Application Layer ---
 	createTeamCmd := commands.NewCreateTeamCommand(teamRepo, userRepo)
-	createPRCmd := commands.NewCreatePRCommand(teamRepo, userRepo, prRepo, assignmentService)
+	createPRCmd := commands.NewCreatePRCommand(teamRepo, userRepo, prRepo, assignmentService, notifier, appMetrics, logger)
 	mergePRCmd := commands.NewMergePRCommand(prRepo)
-	reassignReviewerCmd := commands.NewReassignReviewerCommand(teamRepo, userRepo, prRepo, assignmentService)
-	setUserActiveCmd := commands.NewSetUserActiveCommand(userRepo)
+	reassignReviewerCmd := commands.NewReassignReviewerCommand(teamRepo, userRepo, prRepo, assignmentService, notifier, appMetrics, clock, logger)
+	setUserActiveCmd := commands.NewSetUserActiveCommand(userRepo, denylist)
+	setUserRoleCmd := commands.NewSetUserRoleCommand(userRepo)
+	setUserOnLeaveCmd := commands.NewSetUserOnLeaveCommand(userRepo)
+	setNotificationPreferencesCmd := commands.NewSetNotificationPreferencesCommand(userRepo)
+	syncTeamsCmd := commands.NewSyncTeamsCommand(teamRepo, userRepo, prRepo, teamSyncRepo, logger)
+	provisionUserCmd := commands.NewProvisionUserCommand(userRepo, teamRepo, teamSyncRepo, cfg.SCIM.DefaultTeam, logger)
+	updateProfileCmd := commands.NewUpdateUserProfileCommand(userRepo)
+	deprovisionUserCmd := commands.NewDeprovisionUserCommand(userRepo, teamSyncRepo, denylist, logger)
+	setTeamMembersCmd := commands.NewSetTeamMembersCommand(teamRepo, userRepo, teamSyncRepo, cfg.SCIM.DefaultTeam, logger)
+	deleteTeamCmd := commands.NewDeleteTeamCommand(teamRepo, teamSyncRepo, cfg.SCIM.DefaultTeam, logger)
+	hasher := security.NewBcryptHasher(cfg.Auth.BcryptCost)
+	lockout := entities.LockoutPolicy{
+		MaxFailedAttempts: cfg.Auth.MaxFailedLogins,
+		LockoutDuration:   cfg.Auth.LockoutDuration,
+	}
+	authenticateCmd := commands.NewAuthenticateCommand(userRepo, credentialRepo, hasher, lockout, clock, logger)
+	setPasswordCmd := commands.NewSetPasswordCommand(userRepo, credentialRepo, hasher, clock)
+	revokeSessionsCmd := commands.NewRevokeUserSessionsCommand(userRepo, denylist)
+
+	createAPITokenCmd := commands.NewCreateAPITokenCommand(apiTokenRepo, userRepo, clock)
+	revokeAPITokenCmd := commands.NewRevokeAPITokenCommand(apiTokenRepo, clock)
 
 	getTeamQuery := queries.NewGetTeamQuery(teamRepo)
 	getUserReviewsQuery := queries.NewGetUserReviewsQuery(prRepo)
+	listPRsQuery := queries.NewListPullRequestsQuery(prRepo, teamRepo, clock)
+	previewPRQuery := queries.NewPreviewAssignmentQuery(teamRepo, userRepo, prRepo, assignmentService)
+	explainPRQuery := queries.NewExplainAssignmentQuery(prRepo, decisionRepo)
+	getUserQuery := queries.NewGetUserQuery(userRepo)
+	listUsersQuery := queries.NewListUsersQuery(userRepo)
+	listTeamsQuery := queries.NewListTeamsQuery(teamRepo)
+	listAPITokensQuery := queries.NewListAPITokensQuery(apiTokenRepo)
+	verifyAPITokenQuery := queries.NewVerifyAPITokenQuery(apiTokenRepo, userRepo, clock)
+
+	authorizer := authz.NewAuthorizer(userRepo, prRepo)
+
+	if cfg.Notifications.Digest.Enabled {
+		if digestSender == nil {
+			logger.Error("review digest requires SMTP to be configured")
+			os.Exit(1)
+		}
+		digestCmd := commands.NewSendReviewDigestCommand(
+			prRepo, userRepo, digestRunRepo, digestSender,
+			clock, cfg.Notifications.Digest.SLA, logger,
+		)
+		if err := startDigestScheduler(ctx, digestCmd, cfg.Notifications.Digest.Time, logger); err != nil {
+			logger.Error("failed to schedule review digest", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// --- HTTP API ---
 	router := http.NewRouter(logger, http.RouterDeps{
@@ -67,9 +218,44 @@ func Run() {
 		MergePR:          mergePRCmd,
 		ReassignReviewer: reassignReviewerCmd,
 		SetUserActive:    setUserActiveCmd,
+		SetUserRole:      setUserRoleCmd,
+		SetUserOnLeave:   setUserOnLeaveCmd,
+		SetNotifications: setNotificationPreferencesCmd,
+		SyncTeams:        syncTeamsCmd,
+		ProvisionUser:    provisionUserCmd,
+		UpdateProfile:    updateProfileCmd,
+		DeprovisionUser:  deprovisionUserCmd,
+		SetTeamMembers:   setTeamMembersCmd,
+		DeleteTeam:       deleteTeamCmd,
+		Authenticate:     authenticateCmd,
+		SetPassword:      setPasswordCmd,
+		RevokeSessions:   revokeSessionsCmd,
+		CreateAPIToken:   createAPITokenCmd,
+		RevokeAPIToken:   revokeAPITokenCmd,
 		GetTeam:          getTeamQuery,
 		GetUserReviews:   getUserReviewsQuery,
+		ListPRs:          listPRsQuery,
+		PreviewPR:        previewPRQuery,
+		ExplainPR:        explainPRQuery,
+		GetUser:          getUserQuery,
+		ListUsers:        listUsersQuery,
+		ListTeams:        listTeamsQuery,
+		ListAPITokens:    listAPITokensQuery,
+		VerifyAPIToken:   verifyAPITokenQuery,
 		UserRepo:         userRepo,
+		RefreshTokenRepo: refreshTokenRepo,
+		Tokens:           tokens,
+		Denylist:         denylist,
+		OIDC:             oidc,
+		Authorizer:       authorizer,
+		RateLimiter:      rateLimiter,
+		RateLimits:       rateLimits,
+		TrustProxy:       cfg.RateLimit.TrustProxy,
+		Idempotency:      store.idempotency,
+		IdempotencyTTL:   cfg.Server.IdempotencyKeyTTL,
+		Readiness:        readiness,
+		Metrics:          appMetrics,
+		MetricsHandler:   appMetrics.Handler(),
 	})
 
 	http.StartServer(ctx, logger, cfg.Server, router)
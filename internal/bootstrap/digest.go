@@ -0,0 +1,57 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+)
+
+func startDigestScheduler(ctx context.Context, cmd *commands.SendReviewDigestCommand, at string, logger *slog.Logger) error {
+	hour, minute, err := parseTimeOfDay(at)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			next := nextDigestRun(time.Now().UTC(), hour, minute)
+			logger.Info("Next review digest scheduled", "at", next)
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			sent, err := cmd.Execute(ctx)
+			if err != nil {
+				logger.Error("review digest finished with errors", "sent", sent, "error", err)
+				continue
+			}
+			logger.Info("Review digest sent", "sent", sent)
+		}
+	}()
+
+	return nil
+}
+
+func parseTimeOfDay(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid digest time %q: %w", value, err)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+func nextDigestRun(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,28 @@
+package bootstrap
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+const idempotencyPruneInterval = 10 * time.Minute
+
+// pruneIdempotencyKeys deletes expired idempotency records until ctx is done.
+func pruneIdempotencyKeys(ctx context.Context, repo ports.IdempotencyRepository, logger *slog.Logger) {
+	ticker := time.NewTicker(idempotencyPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := repo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("failed to prune idempotency keys", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+)
+
+// openMemoryStorage backs the service with the in-memory repositories. State
+// is restored from MEMORY_SNAPSHOT_PATH when that file exists, otherwise it is
+// seeded from MEMORY_FIXTURE_PATH. When a snapshot path is set the state is
+// written back to it on Close.
+func openMemoryStorage(cfg *config.Config, logger *slog.Logger) (*storage, error) {
+	store := repositories.NewInMemoryStore()
+
+	seed, source, err := loadMemorySeed(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+	if seed != nil {
+		if err := hashFixturePasswords(seed, cfg.Auth.BcryptCost); err != nil {
+			return nil, err
+		}
+		if err := store.Restore(context.Background(), seed); err != nil {
+			return nil, fmt.Errorf("restoring %s: %w", source, err)
+		}
+		logger.Info("Memory storage loaded", "source", source, "teams", len(seed.Teams), "pull_requests", len(seed.PullRequests))
+	}
+
+	snapshotPath := cfg.DB.MemorySnapshotPath
+	return &storage{
+		driver:        cfg.DB.Driver,
+		teams:         store.Teams,
+		users:         store.Users,
+		prs:           store.PRs,
+		refreshTokens: store.RefreshTokens,
+		credentials:   store.Credentials,
+		apiTokens:     store.APITokens,
+		revocations:   store.Revocations,
+		teamSync:      repositories.NewInMemoryTeamSyncRepository(store),
+		idempotency:   store.Idempotency,
+		decisions:     store.Decisions,
+		digestRuns:    store.DigestRuns,
+		close: func() error {
+			if snapshotPath == "" {
+				return nil
+			}
+			if err := writeMemorySnapshot(snapshotPath, store.Snapshot()); err != nil {
+				return err
+			}
+			logger.Info("Memory snapshot saved", "path", snapshotPath)
+			return nil
+		},
+	}, nil
+}
+
+func loadMemorySeed(cfg config.DBConfig) (*repositories.MemorySnapshot, string, error) {
+	if cfg.MemorySnapshotPath != "" {
+		seed, err := readMemorySnapshot(cfg.MemorySnapshotPath)
+		if err == nil {
+			return seed, cfg.MemorySnapshotPath, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", err
+		}
+	}
+	if cfg.MemoryFixturePath != "" {
+		seed, err := readMemorySnapshot(cfg.MemoryFixturePath)
+		if err != nil {
+			return nil, "", err
+		}
+		return seed, cfg.MemoryFixturePath, nil
+	}
+	return nil, "", nil
+}
+
+func readMemorySnapshot(path string) (*repositories.MemorySnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var snapshot repositories.MemorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// writeMemorySnapshot replaces the file atomically so that a crash while
+// saving leaves the previous snapshot intact.
+func writeMemorySnapshot(path string, snapshot *repositories.MemorySnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("saving snapshot: %w", err)
+	}
+	return nil
+}
+
+// hashFixturePasswords turns plain fixture passwords into credentials. A user
+// that already has stored credentials keeps them.
+func hashFixturePasswords(seed *repositories.MemorySnapshot, cost int) error {
+	existing := make(map[string]bool, len(seed.Credentials))
+	for _, c := range seed.Credentials {
+		existing[c.UserID] = true
+	}
+
+	hasher := security.NewBcryptHasher(cost)
+	for i := range seed.Teams {
+		for j := range seed.Teams[i].Members {
+			member := &seed.Teams[i].Members[j]
+			if member.Password == "" {
+				continue
+			}
+			if !existing[member.ID] {
+				hash, err := hasher.Hash(member.Password)
+				if err != nil {
+					return fmt.Errorf("hashing password for %s: %w", member.ID, err)
+				}
+				c := entities.NewCredentials(member.ID, hash)
+				seed.Credentials = append(seed.Credentials, repositories.SnapshotCredentials{
+					UserID:       c.UserID,
+					PasswordHash: c.PasswordHash,
+					UpdatedAt:    c.UpdatedAt,
+				})
+				existing[member.ID] = true
+			}
+			member.Password = ""
+		}
+	}
+	return nil
+}
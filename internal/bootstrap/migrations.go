@@ -2,59 +2,130 @@ package bootstrap
 
 import (
 	"context"
-	"database/sql"
+	"flag"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
-	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
 )
 
-func runMigrationCommand(db *sql.DB, logger *slog.Logger, cfg *config.Config) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+func ensureSchema(ctx context.Context, migrationRepo ports.MigrationRepository, cfg config.DBConfig, logger *slog.Logger) error {
+	return commands.NewEnsureSchemaCommand(migrationRepo, cfg.SchemaLockTimeout, logger).Execute(ctx, cfg.AutoMigrate)
+}
 
-	migrationsPath := cfg.DB.MigrationsPath
-	if migrationsPath == "" {
-		migrationsPath = "./migrations"
+func runMigrationCommand(store *storage, logger *slog.Logger, cfg *config.Config) {
+	if store.migrations == nil {
+		logger.Error(cfg.Command.Name + " is not available with STORAGE_DRIVER=" + cfg.DB.Driver)
+		os.Exit(1)
 	}
 
-	migrationRepo, err := repositories.NewPostgresMigrationRepository(db, migrationsPath)
-	if err != nil {
-		logger.Error("failed to create migration repository", "error", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := migrationCLI(ctx, store.migrations, logger, cfg.Command, os.Stdout); err != nil {
+		logger.Error(cfg.Command.Name+" failed", "error", err)
 		os.Exit(1)
 	}
+}
 
-	switch cfg.Command.Name {
+func migrationCLI(
+	ctx context.Context,
+	migrationRepo ports.MigrationRepository,
+	logger *slog.Logger,
+	command config.Command,
+	out io.Writer,
+) error {
+	flags := flag.NewFlagSet(command.Name, flag.ContinueOnError)
+	flags.SetOutput(out)
+
+	switch command.Name {
 	case "migrate":
-		logger.Info("Starting database migrations...")
-		applyCmd := commands.NewApplyMigrationsCommand(migrationRepo, logger)
-		if err := applyCmd.Execute(ctx); err != nil {
-			logger.Error("migration failed", "error", err)
-			os.Exit(1)
+		to := flags.Int("to", -1, "migrate up or down to this version instead of the latest")
+		dryRun := flags.Bool("dry-run", false, "print the SQL that would run without applying it")
+		if err := flags.Parse(command.Args); err != nil {
+			return err
 		}
-		logger.Info("Migrations applied successfully")
+
+		var target *uint
+		if *to >= 0 {
+			version := uint(*to)
+			target = &version
+		}
+		steps, err := commands.NewApplyMigrationsCommand(migrationRepo, logger).Execute(ctx, target, *dryRun)
+		if err != nil {
+			return err
+		}
+		reportMigrationSteps(logger, out, steps, *dryRun)
 
 	case "rollback":
-		logger.Info("Starting database rollback...")
-		rollbackCmd := commands.NewRollbackMigrationsCommand(migrationRepo, logger)
-		if err := rollbackCmd.Execute(ctx); err != nil {
-			logger.Error("rollback failed", "error", err)
-			os.Exit(1)
-		}
-		logger.Info("Migrations rolled back successfully")
-
-	case "migration-status":
-		logger.Info("Checking migration status...")
-		statusQuery := queries.NewGetMigrationStatusQuery(migrationRepo, logger)
-		status, err := statusQuery.Execute(ctx)
+		steps := flags.Int("steps", 1, "number of migrations to revert")
+		dryRun := flags.Bool("dry-run", false, "print the SQL that would run without applying it")
+		if err := flags.Parse(command.Args); err != nil {
+			return err
+		}
+
+		reverted, err := commands.NewRollbackMigrationsCommand(migrationRepo, logger).Execute(ctx, *steps, *dryRun)
 		if err != nil {
-			logger.Error("failed to get migration status", "error", err)
-			os.Exit(1)
+			return err
+		}
+		reportMigrationSteps(logger, out, reverted, *dryRun)
+
+	case "force":
+		if err := flags.Parse(command.Args); err != nil {
+			return err
 		}
-		logger.Info("Migration status", "version", status.Version, "dirty", status.Dirty)
+		if flags.NArg() != 1 {
+			return fmt.Errorf("usage: force <version>")
+		}
+		version, err := strconv.Atoi(flags.Arg(0))
+		if err != nil || version < -1 {
+			return fmt.Errorf("invalid version %q", flags.Arg(0))
+		}
+		return commands.NewForceMigrationVersionCommand(migrationRepo, logger).Execute(ctx, version)
+
+	case "migration-status", "migration-pending":
+		status, err := queries.NewGetMigrationStatusQuery(migrationRepo, logger).Execute(ctx)
+		if err != nil {
+			return err
+		}
+		if command.Name == "migration-status" {
+			logger.Info("Migration status",
+				"version", status.Version,
+				"dirty", status.Dirty,
+				"latest", status.Latest,
+				"pending", len(status.Pending),
+			)
+			return nil
+		}
+		if len(status.Pending) == 0 {
+			fmt.Fprintln(out, "No pending migrations")
+		}
+		for _, m := range status.Pending {
+			fmt.Fprintf(out, "%06d_%s\n", m.Version, m.Name)
+		}
+
+	default:
+		return fmt.Errorf("unknown migration command %q", command.Name)
+	}
+	return nil
+}
+
+func reportMigrationSteps(logger *slog.Logger, out io.Writer, steps []ports.MigrationStep, dryRun bool) {
+	if !dryRun {
+		logger.Info("Migrations finished", "steps", len(steps))
+		return
+	}
+	if len(steps) == 0 {
+		fmt.Fprintln(out, "-- nothing to do")
+	}
+	for _, step := range steps {
+		fmt.Fprintf(out, "-- %06d_%s.%s.sql\n%s\n", step.Version, step.Name, step.Direction, step.SQL)
 	}
 }
@@ -0,0 +1,69 @@
+package bootstrap
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/notifications"
+)
+
+// buildNotifier returns the combined assignment notifier and, when SMTP is
+// configured, the email sender used for daily digests (nil otherwise).
+func buildNotifier(cfg config.NotificationsConfig, logger *slog.Logger) (ports.Notifier, ports.DigestSender, error) {
+	slackTemplate, err := readTemplate(cfg.Slack.Template, cfg.Slack.TemplateFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slack, err := notifications.NewSlackNotifier(notifications.SlackConfig{
+		DefaultWebhookURL: cfg.Slack.DefaultWebhookURL,
+		Template:          slackTemplate,
+		Timeout:           cfg.Slack.Timeout,
+	}, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !cfg.Email.Enabled() {
+		return notifications.NewMultiNotifier(slack), nil, nil
+	}
+
+	assignmentTemplate, err := readTemplate("", cfg.Email.AssignmentTemplateFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	digestTemplate, err := readTemplate("", cfg.Email.DigestTemplateFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	email, err := notifications.NewEmailNotifier(notifications.EmailConfig{
+		Host:               cfg.Email.SMTPHost,
+		Port:               cfg.Email.SMTPPort,
+		Username:           cfg.Email.SMTPUsername,
+		Password:           cfg.Email.SMTPPassword,
+		From:               cfg.Email.From,
+		AssignmentTemplate: assignmentTemplate,
+		DigestTemplate:     digestTemplate,
+		Timeout:            cfg.Email.Timeout,
+	}, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return notifications.NewMultiNotifier(slack, email), email, nil
+}
+
+func readTemplate(inline, path string) (string, error) {
+	if path == "" {
+		return inline, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read template %s: %w", path, err)
+	}
+	return string(content), nil
+}
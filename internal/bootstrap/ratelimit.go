@@ -0,0 +1,51 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/ratelimit"
+)
+
+// buildRateLimiter returns a nil limiter when rate limiting is disabled.
+func buildRateLimiter(
+	ctx context.Context,
+	cfg config.RateLimitConfig,
+	store *storage,
+	logger *slog.Logger,
+) (ratelimit.Limiter, ratelimit.Policy, error) {
+	if !cfg.Enabled {
+		return nil, ratelimit.Policy{}, nil
+	}
+
+	defaultLimit, err := ratelimit.ParseLimit(cfg.Default)
+	if err != nil {
+		return nil, ratelimit.Policy{}, err
+	}
+	routes, err := ratelimit.ParseRoutes(cfg.Routes)
+	if err != nil {
+		return nil, ratelimit.Policy{}, err
+	}
+	policy := ratelimit.Policy{Default: defaultLimit, Routes: routes}
+
+	switch cfg.Backend {
+	case "memory":
+		return ratelimit.NewMemoryLimiter(), policy, nil
+	case "postgres":
+		if store.driver != "postgres" {
+			return nil, ratelimit.Policy{}, fmt.Errorf("rate limit backend %q requires STORAGE_DRIVER=postgres", cfg.Backend)
+		}
+		limiter := ratelimit.NewPostgresLimiter(store.db, logger)
+		// A bucket idle for its longest window has refilled and can go.
+		maxIdle := defaultLimit.Window
+		for _, limit := range routes {
+			maxIdle = max(maxIdle, limit.Window)
+		}
+		go limiter.Run(ctx, cfg.PruneInterval, maxIdle)
+		return limiter, policy, nil
+	default:
+		return nil, ratelimit.Policy{}, fmt.Errorf("unknown rate limit backend %q", cfg.Backend)
+	}
+}
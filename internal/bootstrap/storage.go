@@ -0,0 +1,110 @@
+package bootstrap
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/migrations"
+)
+
+// storage holds the repositories of the backend selected by STORAGE_DRIVER.
+// db and migrations are nil in memory mode.
+type storage struct {
+	driver        string
+	db            *sql.DB
+	teams         ports.TeamRepository
+	users         ports.UserRepository
+	prs           ports.PRRepository
+	refreshTokens ports.RefreshTokenRepository
+	credentials   ports.CredentialRepository
+	apiTokens     ports.APITokenRepository
+	revocations   ports.TokenRevocationRepository
+	teamSync      ports.TeamSyncRepository
+	idempotency   ports.IdempotencyRepository
+	decisions     ports.AssignmentDecisionRepository
+	digestRuns    ports.DigestRunRepository
+	migrations    ports.MigrationRepository
+	close         func() error
+}
+
+func openStorage(cfg *config.Config, logger *slog.Logger) (*storage, error) {
+	switch cfg.DB.Driver {
+	case "postgres":
+		db, err := config.NewPostgresConnection(&cfg.DB)
+		if err != nil {
+			return nil, err
+		}
+		migrationRepo, err := repositories.NewPostgresMigrationRepository(db, migrationSource(cfg.DB, migrations.FS))
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		return &storage{
+			driver:        cfg.DB.Driver,
+			db:            db,
+			teams:         repositories.NewPostgresTeamRepository(db),
+			users:         repositories.NewPostgresUserRepository(db),
+			prs:           repositories.NewPostgresPRRepository(db),
+			refreshTokens: repositories.NewPostgresRefreshTokenRepository(db),
+			credentials:   repositories.NewPostgresCredentialRepository(db),
+			apiTokens:     repositories.NewPostgresAPITokenRepository(db),
+			revocations:   repositories.NewPostgresTokenRevocationRepository(db),
+			teamSync:      repositories.NewPostgresTeamSyncRepository(db),
+			idempotency:   repositories.NewPostgresIdempotencyRepository(db),
+			decisions:     repositories.NewPostgresAssignmentDecisionRepository(db),
+			digestRuns:    repositories.NewPostgresDigestRunRepository(db),
+			migrations:    migrationRepo,
+			close:         db.Close,
+		}, nil
+	case "sqlite":
+		db, err := config.NewSQLiteConnection(&cfg.DB)
+		if err != nil {
+			return nil, err
+		}
+		migrationRepo, err := repositories.NewSQLiteMigrationRepository(db, migrationSource(cfg.DB, migrations.SQLiteFS))
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		return &storage{
+			driver:        cfg.DB.Driver,
+			db:            db,
+			teams:         repositories.NewSQLiteTeamRepository(db),
+			users:         repositories.NewSQLiteUserRepository(db),
+			prs:           repositories.NewSQLitePRRepository(db),
+			refreshTokens: repositories.NewSQLiteRefreshTokenRepository(db),
+			credentials:   repositories.NewSQLiteCredentialRepository(db),
+			apiTokens:     repositories.NewSQLiteAPITokenRepository(db),
+			revocations:   repositories.NewSQLiteTokenRevocationRepository(db),
+			teamSync:      repositories.NewSQLiteTeamSyncRepository(db),
+			idempotency:   repositories.NewSQLiteIdempotencyRepository(db),
+			decisions:     repositories.NewSQLiteAssignmentDecisionRepository(db),
+			digestRuns:    repositories.NewSQLiteDigestRunRepository(db),
+			migrations:    migrationRepo,
+			close:         db.Close,
+		}, nil
+	case "memory":
+		return openMemoryStorage(cfg, logger)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.DB.Driver)
+	}
+}
+
+// migrationSource returns the backend's migrations embedded in the binary
+// unless MIGRATIONS_PATH points at a directory to use instead.
+func migrationSource(cfg config.DBConfig, embedded fs.FS) fs.FS {
+	if cfg.MigrationsPath != "" {
+		return os.DirFS(cfg.MigrationsPath)
+	}
+	return embedded
+}
+
+func (s *storage) Close() error {
+	return s.close()
+}
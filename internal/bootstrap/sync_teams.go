@@ -0,0 +1,77 @@
+package bootstrap
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/roster"
+)
+
+// runSyncTeamsCommand handles `sync-teams [--apply] <file>`, which reconciles
+// the teams named in a YAML or JSON roster with the database. The plan is
+// always printed; it is applied in one transaction only with --apply. A file
+// of "-" reads the roster from stdin.
+func runSyncTeamsCommand(store *storage, logger *slog.Logger, cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	syncTeams := commands.NewSyncTeamsCommand(store.teams, store.users, store.prs, store.teamSync, logger)
+	if err := syncTeamsCLI(ctx, syncTeams, cfg.Command, os.Stdin, os.Stdout); err != nil {
+		logger.Error("sync-teams failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func syncTeamsCLI(ctx context.Context, syncTeams *commands.SyncTeamsCommand, command config.Command, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet(command.Name, flag.ContinueOnError)
+	flags.SetOutput(out)
+	apply := flags.Bool("apply", false, "apply the plan instead of only printing it")
+	if err := flags.Parse(command.Args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: sync-teams [--apply] <file>")
+	}
+
+	data, err := readRoster(flags.Arg(0), in)
+	if err != nil {
+		return err
+	}
+	teams, err := roster.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	plan, err := syncTeams.Execute(ctx, teams, *apply)
+	if err != nil {
+		return err
+	}
+
+	roster.WritePlan(out, plan)
+	if !*apply && !plan.IsEmpty() {
+		fmt.Fprintln(out, "Dry run: rerun with --apply to make these changes")
+	}
+	return nil
+}
+
+func readRoster(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading roster from stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading roster: %w", err)
+	}
+	return data, nil
+}
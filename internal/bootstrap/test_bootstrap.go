@@ -5,12 +5,18 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
 	apphttp "github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/http"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/notifications"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type TestApplication struct {
@@ -24,18 +30,67 @@ func NewTestApplication(db *sql.DB) *TestApplication {
 	teamRepo := repositories.NewPostgresTeamRepository(db)
 	userRepo := repositories.NewPostgresUserRepository(db)
 	prRepo := repositories.NewPostgresPRRepository(db)
+	refreshTokenRepo := repositories.NewPostgresRefreshTokenRepository(db)
+	credentialRepo := repositories.NewPostgresCredentialRepository(db)
+	apiTokenRepo := repositories.NewPostgresAPITokenRepository(db)
+	revocationRepo := repositories.NewPostgresTokenRevocationRepository(db)
+	teamSyncRepo := repositories.NewPostgresTeamSyncRepository(db)
+	decisionRepo := repositories.NewPostgresAssignmentDecisionRepository(db)
+
+	tokens, err := apphttp.NewTokenManager(apphttp.TokenConfig{
+		ActiveKey:       apphttp.SigningKey{ID: "test", Secret: []byte("test-secret")},
+		Issuer:          "pr-reviewer-test",
+		Audience:        "pr-reviewer-test",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	clock := services.NewRealClock()
+	denylist := security.NewDenylist(revocationRepo, refreshTokenRepo, clock, logger)
 
 	randomizer := services.NewDefaultRandomizer()
-	assignmentService := services.NewReviewerAssignmentService(randomizer)
+	assignmentService := services.NewReviewerAssignmentService(randomizer, 0)
 
 	createTeamCmd := commands.NewCreateTeamCommand(teamRepo, userRepo)
-	createPRCmd := commands.NewCreatePRCommand(teamRepo, userRepo, prRepo, assignmentService)
+	notifier := notifications.NoopNotifier{}
+
+	createPRCmd := commands.NewCreatePRCommand(teamRepo, userRepo, prRepo, assignmentService, notifier, nil, logger)
 	mergePRCmd := commands.NewMergePRCommand(prRepo)
-	reassignReviewerCmd := commands.NewReassignReviewerCommand(teamRepo, userRepo, prRepo, assignmentService)
-	setUserActiveCmd := commands.NewSetUserActiveCommand(userRepo)
+	reassignReviewerCmd := commands.NewReassignReviewerCommand(teamRepo, userRepo, prRepo, assignmentService, notifier, nil, clock, logger)
+	setUserActiveCmd := commands.NewSetUserActiveCommand(userRepo, denylist)
+	setUserRoleCmd := commands.NewSetUserRoleCommand(userRepo)
+	setUserOnLeaveCmd := commands.NewSetUserOnLeaveCommand(userRepo)
+	setNotificationPreferencesCmd := commands.NewSetNotificationPreferencesCommand(userRepo)
+	syncTeamsCmd := commands.NewSyncTeamsCommand(teamRepo, userRepo, prRepo, teamSyncRepo, logger)
+	provisionUserCmd := commands.NewProvisionUserCommand(userRepo, teamRepo, teamSyncRepo, "unassigned", logger)
+	updateProfileCmd := commands.NewUpdateUserProfileCommand(userRepo)
+	deprovisionUserCmd := commands.NewDeprovisionUserCommand(userRepo, teamSyncRepo, denylist, logger)
+	setTeamMembersCmd := commands.NewSetTeamMembersCommand(teamRepo, userRepo, teamSyncRepo, "unassigned", logger)
+	deleteTeamCmd := commands.NewDeleteTeamCommand(teamRepo, teamSyncRepo, "unassigned", logger)
+	hasher := security.NewBcryptHasher(bcrypt.MinCost)
+	lockout := entities.LockoutPolicy{MaxFailedAttempts: 5, LockoutDuration: time.Minute}
+	authenticateCmd := commands.NewAuthenticateCommand(userRepo, credentialRepo, hasher, lockout, clock, logger)
+	setPasswordCmd := commands.NewSetPasswordCommand(userRepo, credentialRepo, hasher, clock)
+	revokeSessionsCmd := commands.NewRevokeUserSessionsCommand(userRepo, denylist)
+
+	createAPITokenCmd := commands.NewCreateAPITokenCommand(apiTokenRepo, userRepo, clock)
+	revokeAPITokenCmd := commands.NewRevokeAPITokenCommand(apiTokenRepo, clock)
 
 	getTeamQuery := queries.NewGetTeamQuery(teamRepo)
 	getUserReviewsQuery := queries.NewGetUserReviewsQuery(prRepo)
+	listPRsQuery := queries.NewListPullRequestsQuery(prRepo, teamRepo, clock)
+	previewPRQuery := queries.NewPreviewAssignmentQuery(teamRepo, userRepo, prRepo, assignmentService)
+	explainPRQuery := queries.NewExplainAssignmentQuery(prRepo, decisionRepo)
+	getUserQuery := queries.NewGetUserQuery(userRepo)
+	listUsersQuery := queries.NewListUsersQuery(userRepo)
+	listTeamsQuery := queries.NewListTeamsQuery(teamRepo)
+	listAPITokensQuery := queries.NewListAPITokensQuery(apiTokenRepo)
+	verifyAPITokenQuery := queries.NewVerifyAPITokenQuery(apiTokenRepo, userRepo, clock)
+
+	authorizer := authz.NewAuthorizer(userRepo, prRepo)
 
 	router := apphttp.NewRouter(logger, apphttp.RouterDeps{
 		CreateTeam:       createTeamCmd,
@@ -43,9 +98,37 @@ func NewTestApplication(db *sql.DB) *TestApplication {
 		MergePR:          mergePRCmd,
 		ReassignReviewer: reassignReviewerCmd,
 		SetUserActive:    setUserActiveCmd,
+		SetUserRole:      setUserRoleCmd,
+		SetUserOnLeave:   setUserOnLeaveCmd,
+		SetNotifications: setNotificationPreferencesCmd,
+		SyncTeams:        syncTeamsCmd,
+		ProvisionUser:    provisionUserCmd,
+		UpdateProfile:    updateProfileCmd,
+		DeprovisionUser:  deprovisionUserCmd,
+		SetTeamMembers:   setTeamMembersCmd,
+		DeleteTeam:       deleteTeamCmd,
+		Authenticate:     authenticateCmd,
+		SetPassword:      setPasswordCmd,
+		RevokeSessions:   revokeSessionsCmd,
+		CreateAPIToken:   createAPITokenCmd,
+		RevokeAPIToken:   revokeAPITokenCmd,
 		GetTeam:          getTeamQuery,
 		GetUserReviews:   getUserReviewsQuery,
+		ListPRs:          listPRsQuery,
+		PreviewPR:        previewPRQuery,
+		ExplainPR:        explainPRQuery,
+		GetUser:          getUserQuery,
+		ListUsers:        listUsersQuery,
+		ListTeams:        listTeamsQuery,
+		ListAPITokens:    listAPITokensQuery,
+		VerifyAPIToken:   verifyAPITokenQuery,
 		UserRepo:         userRepo,
+		RefreshTokenRepo: refreshTokenRepo,
+		Tokens:           tokens,
+		Denylist:         denylist,
+		Authorizer:       authorizer,
+		Idempotency:      repositories.NewPostgresIdempotencyRepository(db),
+		IdempotencyTTL:   time.Hour,
 	})
 
 	return &TestApplication{
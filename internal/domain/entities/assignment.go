@@ -0,0 +1,58 @@
+package entities
+
+import "time"
+
+// ExclusionReason says why a team member cannot review a pull request.
+type ExclusionReason string
+
+const (
+	ExclusionAuthor   ExclusionReason = "author"
+	ExclusionInactive ExclusionReason = "inactive"
+	ExclusionOnLeave  ExclusionReason = "on_leave"
+	// ExclusionReviewer marks a member who already reviews the pull request.
+	ExclusionReviewer ExclusionReason = "reviewer"
+	// ExclusionAtCapacity marks a member who already reviews the maximum
+	// number of open pull requests.
+	ExclusionAtCapacity ExclusionReason = "at_capacity"
+)
+
+type ReviewerExclusion struct {
+	UserID string
+	Reason ExclusionReason
+}
+
+// ReviewerPool splits the author's team into the members reviewers are drawn
+// from and the ones left out, both in team order.
+type ReviewerPool struct {
+	Candidates []string
+	Excluded   []ReviewerExclusion
+}
+
+type AssignmentKind string
+
+const (
+	AssignmentCreate   AssignmentKind = "create"
+	AssignmentReassign AssignmentKind = "reassign"
+)
+
+type ScoredCandidate struct {
+	UserID string
+	Score  float64
+}
+
+// AssignmentDecision records how reviewers were chosen for a pull request,
+// so that an assignment can be explained later. Candidates are scored from
+// Seed in order, and the highest scores become Reviewers.
+type AssignmentDecision struct {
+	PullRequestID string
+	Kind          AssignmentKind
+	// ReplacedReviewerID is the reviewer a reassignment replaced.
+	ReplacedReviewerID string
+	Strategy           string
+	Seed               int64
+	Candidates         []ScoredCandidate
+	Excluded           []ReviewerExclusion
+	// Reviewers are the chosen candidates, best score first.
+	Reviewers []string
+	CreatedAt time.Time
+}
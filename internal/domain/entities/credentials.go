@@ -0,0 +1,53 @@
+package entities
+
+import "time"
+
+const MinPasswordLength = 8
+
+type LockoutPolicy struct {
+	MaxFailedAttempts int
+	LockoutDuration   time.Duration
+}
+
+type Credentials struct {
+	UserID         string
+	PasswordHash   string
+	FailedAttempts int
+	LockedUntil    *time.Time
+	UpdatedAt      time.Time
+}
+
+func NewCredentials(userID, passwordHash string) *Credentials {
+	return &Credentials{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		UpdatedAt:    time.Now().UTC(),
+	}
+}
+
+func (c *Credentials) IsLocked(now time.Time) bool {
+	return c.LockedUntil != nil && now.Before(*c.LockedUntil)
+}
+
+// RecordFailedLogin counts a failed attempt and locks the account once the
+// policy limit is reached. The counter restarts after the lock is applied.
+func (c *Credentials) RecordFailedLogin(now time.Time, policy LockoutPolicy) {
+	c.FailedAttempts++
+	if policy.MaxFailedAttempts > 0 && c.FailedAttempts >= policy.MaxFailedAttempts {
+		lockedUntil := now.Add(policy.LockoutDuration)
+		c.LockedUntil = &lockedUntil
+		c.FailedAttempts = 0
+	}
+}
+
+func (c *Credentials) RecordSuccessfulLogin() {
+	c.FailedAttempts = 0
+	c.LockedUntil = nil
+}
+
+func (c *Credentials) SetPasswordHash(passwordHash string, now time.Time) {
+	c.PasswordHash = passwordHash
+	c.FailedAttempts = 0
+	c.LockedUntil = nil
+	c.UpdatedAt = now
+}
@@ -12,12 +12,28 @@ var (
 	ErrTeamExists       = errors.New("team already exists")
 	ErrNoCandidateFound = errors.New("no active replacement candidate found")
 	ErrMemberExists     = errors.New("member already exists")
+	ErrInvalidRoster    = errors.New("invalid team roster")
+	ErrTeamNotEmpty     = errors.New("team still has members")
 
 	// User errors
 	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user already exists")
+	ErrInvalidRole  = errors.New("invalid role")
+
+	// Auth errors
+	ErrForbidden           = errors.New("action is not permitted")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrAccountLocked       = errors.New("account is temporarily locked")
+	ErrWeakPassword        = errors.New("password is too short")
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrInvalidScope        = errors.New("invalid api token scope")
+	ErrAPITokenNotFound    = errors.New("api token not found")
+	ErrAPITokenInvalid     = errors.New("api token is invalid, expired or revoked")
 
 	// PR errors
 	ErrPRExists        = errors.New("pull request already exists")
 	ErrPRNotFound      = errors.New("pull request not found")
 	ErrInvalidPRStatus = errors.New("invalid pull request status")
+	ErrInvalidCursor   = errors.New("invalid page cursor")
 )
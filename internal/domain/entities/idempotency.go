@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// IdempotencyRecord remembers the response to a request sent with an
+// Idempotency-Key, so that a retry gets the original answer instead of
+// repeating the change. Keys are scoped to the user who sent them.
+type IdempotencyRecord struct {
+	Key    string
+	UserID string
+	// Owner identifies the request holding the reservation. A retry that
+	// takes over an expired reservation gets a new owner, and the earlier
+	// request can then no longer complete or release the record.
+	Owner string
+	// RequestHash fingerprints the method, path and body; a retry must match.
+	RequestHash string
+	// StatusCode is zero while the first request is still being handled.
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r *IdempotencyRecord) IsComplete() bool {
+	return r.StatusCode != 0
+}
+
+// Complete stores the response and keeps it until expiresAt.
+func (r *IdempotencyRecord) Complete(statusCode int, contentType string, body []byte, expiresAt time.Time) {
+	r.StatusCode = statusCode
+	r.ContentType = contentType
+	r.Body = body
+	r.ExpiresAt = expiresAt
+}
@@ -1,6 +1,10 @@
 package entities
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+)
 
 type PRStatus string
 
@@ -31,6 +35,7 @@ type PullRequest struct {
 	AuthorID          string
 	Status            PRStatus
 	AssignedReviewers []string
+	Labels            []string
 	CreatedAt         time.Time
 	MergedAt          *time.Time
 }
@@ -98,3 +103,15 @@ func (pr *PullRequest) ReassignReviewer(oldReviewerID, newReviewerID string) err
 
 	return ErrReviewerNotAssigned
 }
+
+// SetLabels replaces the labels, trimmed, deduplicated and sorted.
+func (pr *PullRequest) SetLabels(labels []string) {
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != "" {
+			normalized = append(normalized, label)
+		}
+	}
+	slices.Sort(normalized)
+	pr.Labels = slices.Compact(normalized)
+}
@@ -0,0 +1,25 @@
+package entities
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleTeamLead Role = "team_lead"
+	RoleMember   Role = "member"
+)
+
+func (r Role) String() string {
+	return string(r)
+}
+
+func (r Role) IsValid() bool {
+	return r == RoleAdmin || r == RoleTeamLead || r == RoleMember
+}
+
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if !role.IsValid() {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
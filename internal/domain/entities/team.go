@@ -1,8 +1,9 @@
 package entities
 
 type Team struct {
-	Name    string
-	Members []*User
+	Name           string
+	Members        []*User
+	ChatWebhookURL string
 }
 
 func NewTeam(name string, members []*User) *Team {
@@ -0,0 +1,39 @@
+package entities
+
+// TeamSyncPlan lists the changes that bring the stored teams in line with a
+// roster document. Every user carries its state after the change.
+type TeamSyncPlan struct {
+	CreateTeams []string
+	Add         []*User
+	Move        []UserMove
+	// Update holds renamed or reactivated users that stay in their team.
+	Update     []*User
+	Deactivate []*User
+	// Remove holds users dropped from the roster that have no pull request
+	// history. Dropped users with history are deactivated instead, so their
+	// pull requests survive.
+	Remove []*User
+	// DeleteTeams is applied last and only to teams left without members.
+	DeleteTeams []string
+}
+
+type UserMove struct {
+	User     *User
+	FromTeam string
+}
+
+func (p *TeamSyncPlan) IsEmpty() bool {
+	return len(p.CreateTeams) == 0 && len(p.Add) == 0 && len(p.Move) == 0 &&
+		len(p.Update) == 0 && len(p.Deactivate) == 0 && len(p.Remove) == 0 && len(p.DeleteTeams) == 0
+}
+
+// Saves returns every user the plan writes, in plan order.
+func (p *TeamSyncPlan) Saves() []*User {
+	users := make([]*User, 0, len(p.Add)+len(p.Move)+len(p.Update)+len(p.Deactivate))
+	users = append(users, p.Add...)
+	for _, move := range p.Move {
+		users = append(users, move.User)
+	}
+	users = append(users, p.Update...)
+	return append(users, p.Deactivate...)
+}
@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// TokenDenylist records revoked access tokens. Individual tokens are revoked
+// by their jti until they expire; revoking all sessions of a user invalidates
+// every token issued to them up to that moment.
+type TokenDenylist struct {
+	Tokens map[string]time.Time
+	Users  map[string]time.Time
+}
+
+func NewTokenDenylist() *TokenDenylist {
+	return &TokenDenylist{
+		Tokens: make(map[string]time.Time),
+		Users:  make(map[string]time.Time),
+	}
+}
+
+func (d *TokenDenylist) RevokeToken(jti string, expiresAt time.Time) {
+	d.Tokens[jti] = expiresAt
+}
+
+// RevokeUser invalidates tokens issued to the user at or before revokedAt.
+// Token issue times only have second precision, so the cutoff is truncated to
+// the second; a token issued in that same second is treated as revoked.
+func (d *TokenDenylist) RevokeUser(userID string, revokedAt time.Time) {
+	cutoff := revokedAt.Truncate(time.Second)
+	if current, ok := d.Users[userID]; !ok || cutoff.After(current) {
+		d.Users[userID] = cutoff
+	}
+}
+
+func (d *TokenDenylist) IsRevoked(jti, userID string, issuedAt time.Time) bool {
+	if jti != "" {
+		if _, ok := d.Tokens[jti]; ok {
+			return true
+		}
+	}
+	cutoff, ok := d.Users[userID]
+	return ok && !issuedAt.After(cutoff)
+}
@@ -1,10 +1,20 @@
 package entities
 
+type NotificationPreferences struct {
+	EmailOnAssignment bool
+	EmailDigest       bool
+}
+
 type User struct {
-	ID       string
-	Username string
-	TeamName string
-	IsActive bool
+	ID            string
+	Username      string
+	TeamName      string
+	IsActive      bool
+	OnLeave       bool
+	Role          Role
+	ChatHandle    string
+	Email         string
+	Notifications NotificationPreferences
 }
 
 func NewUser(id, username, teamName string, isActive bool) *User {
@@ -13,9 +23,42 @@ func NewUser(id, username, teamName string, isActive bool) *User {
 		Username: username,
 		TeamName: teamName,
 		IsActive: isActive,
+		Role:     RoleMember,
 	}
 }
 
 func (u *User) SetActive(isActive bool) {
 	u.IsActive = isActive
 }
+
+// SetOnLeave keeps an active user out of reviewer assignment while they
+// are away.
+func (u *User) SetOnLeave(onLeave bool) {
+	u.OnLeave = onLeave
+}
+
+func (u *User) SetRole(role Role) {
+	u.Role = role
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// LeadsTeam reports whether the user is a team lead of the given team.
+func (u *User) LeadsTeam(teamName string) bool {
+	return u.Role == RoleTeamLead && u.TeamName == teamName
+}
+
+func (u *User) SetNotificationPreferences(email string, prefs NotificationPreferences) {
+	u.Email = email
+	u.Notifications = prefs
+}
+
+func (u *User) WantsAssignmentEmails() bool {
+	return u.Email != "" && u.Notifications.EmailOnAssignment
+}
+
+func (u *User) WantsDigestEmails() bool {
+	return u.Email != "" && u.Notifications.EmailDigest
+}
@@ -2,21 +2,27 @@ package services
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 )
 
+// Randomizer supplies the seed of each assignment decision. The seed is
+// recorded with the decision, so that it can be replayed.
 type Randomizer interface {
-	Perm(n int) []int
-	Intn(n int) int
+	Seed() int64
 }
 
 type defaultRandomizer struct {
-	r *rand.Rand
+	mu sync.Mutex
+	r  *rand.Rand
 }
 
 func NewDefaultRandomizer() Randomizer {
 	return &defaultRandomizer{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
 }
 
-func (d *defaultRandomizer) Perm(n int) []int { return d.r.Perm(n) }
-func (d *defaultRandomizer) Intn(n int) int   { return d.r.Intn(n) }
+func (d *defaultRandomizer) Seed() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.r.Int63()
+}
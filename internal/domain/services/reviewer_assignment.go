@@ -1,69 +1,108 @@
 package services
 
 import (
+	"cmp"
+	"math/rand"
+	"slices"
+
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
+// RandomStrategy scores active teammates with random numbers drawn from the
+// decision's seed and picks the highest scores.
+const RandomStrategy = "random"
+
+const reviewersPerPR = 2
+
 type ReviewerAssignmentService struct {
 	rnd Randomizer
+	// maxOpenReviews is how many open pull requests a member may review at
+	// once; zero means no limit.
+	maxOpenReviews int
 }
 
-func NewReviewerAssignmentService(rnd Randomizer) *ReviewerAssignmentService {
+func NewReviewerAssignmentService(rnd Randomizer, maxOpenReviews int) *ReviewerAssignmentService {
 	if rnd == nil {
 		rnd = NewDefaultRandomizer()
 	}
-	return &ReviewerAssignmentService{rnd: rnd}
+	return &ReviewerAssignmentService{rnd: rnd, maxOpenReviews: maxOpenReviews}
 }
 
-func (s *ReviewerAssignmentService) SelectReviewers(team *entities.Team, authorID string) ([]string, error) {
-	active := team.GetActiveMembers()
-	candidates := make([]string, 0, len(active))
-	for _, u := range active {
-		if u.ID == authorID {
-			continue
-		}
-		candidates = append(candidates, u.ID)
+// Strategy names the selection strategy in use.
+func (s *ReviewerAssignmentService) Strategy() string {
+	return RandomStrategy
+}
+
+// Assign decides who reviews a new pull request by authorID. openReviews
+// holds how many open pull requests each member reviews. Unlike
+// SelectReviewers it does not fail without candidates; the decision then
+// has no reviewers.
+func (s *ReviewerAssignmentService) Assign(team *entities.Team, authorID string, openReviews map[string]int) *entities.AssignmentDecision {
+	decision := s.decide(s.pool(team, authorID, nil, openReviews), reviewersPerPR)
+	decision.Kind = entities.AssignmentCreate
+	return decision
+}
+
+func (s *ReviewerAssignmentService) SelectReviewers(team *entities.Team, authorID string, openReviews map[string]int) (*entities.AssignmentDecision, error) {
+	decision := s.Assign(team, authorID, openReviews)
+	if len(decision.Reviewers) == 0 {
+		return nil, entities.ErrNoCandidateFound
 	}
+	return decision, nil
+}
 
-	if len(candidates) == 0 {
+// FindReplacement picks one new reviewer among the members who do not review
+// the pull request yet.
+func (s *ReviewerAssignmentService) FindReplacement(team *entities.Team, authorID string, currentReviewers []string, openReviews map[string]int) (*entities.AssignmentDecision, error) {
+	decision := s.decide(s.pool(team, authorID, currentReviewers, openReviews), 1)
+	if len(decision.Reviewers) == 0 {
 		return nil, entities.ErrNoCandidateFound
 	}
+	decision.Kind = entities.AssignmentReassign
+	return decision, nil
+}
 
-	perm := s.rnd.Perm(len(candidates))
-	limit := 2
+func (s *ReviewerAssignmentService) decide(pool *entities.ReviewerPool, count int) *entities.AssignmentDecision {
+	seed := s.rnd.Seed()
+	rnd := rand.New(rand.NewSource(seed))
 
-	if len(candidates) < limit {
-		limit = len(candidates)
+	decision := &entities.AssignmentDecision{
+		Strategy:   s.Strategy(),
+		Seed:       seed,
+		Candidates: make([]entities.ScoredCandidate, 0, len(pool.Candidates)),
+		Excluded:   pool.Excluded,
 	}
-
-	result := make([]string, 0, limit)
-	for i := 0; i < limit; i++ {
-		result = append(result, candidates[perm[i]])
+	for _, id := range pool.Candidates {
+		decision.Candidates = append(decision.Candidates, entities.ScoredCandidate{UserID: id, Score: rnd.Float64()})
 	}
-	return result, nil
-}
 
-func (s *ReviewerAssignmentService) FindReplacement(team *entities.Team, authorID string, currentReviewers []string) (string, error) {
-	active := team.GetActiveMembers()
-	current := make(map[string]struct{}, len(currentReviewers))
-	for _, id := range currentReviewers {
-		current[id] = struct{}{}
+	ranked := slices.Clone(decision.Candidates)
+	slices.SortStableFunc(ranked, func(a, b entities.ScoredCandidate) int { return cmp.Compare(b.Score, a.Score) })
+	for _, candidate := range ranked[:min(count, len(ranked))] {
+		decision.Reviewers = append(decision.Reviewers, candidate.UserID)
 	}
+	return decision
+}
 
-	candidates := make([]string, 0)
-	for _, u := range active {
-		if u.ID == authorID {
-			continue
+// pool splits the team into candidates and excluded members; reviewers are
+// the members who already review the pull request.
+func (s *ReviewerAssignmentService) pool(team *entities.Team, authorID string, reviewers []string, openReviews map[string]int) *entities.ReviewerPool {
+	pool := &entities.ReviewerPool{Candidates: make([]string, 0, len(team.Members))}
+	for _, u := range team.Members {
+		switch {
+		case u.ID == authorID:
+			pool.Excluded = append(pool.Excluded, entities.ReviewerExclusion{UserID: u.ID, Reason: entities.ExclusionAuthor})
+		case !u.IsActive:
+			pool.Excluded = append(pool.Excluded, entities.ReviewerExclusion{UserID: u.ID, Reason: entities.ExclusionInactive})
+		case u.OnLeave:
+			pool.Excluded = append(pool.Excluded, entities.ReviewerExclusion{UserID: u.ID, Reason: entities.ExclusionOnLeave})
+		case slices.Contains(reviewers, u.ID):
+			pool.Excluded = append(pool.Excluded, entities.ReviewerExclusion{UserID: u.ID, Reason: entities.ExclusionReviewer})
+		case s.maxOpenReviews > 0 && openReviews[u.ID] >= s.maxOpenReviews:
+			pool.Excluded = append(pool.Excluded, entities.ReviewerExclusion{UserID: u.ID, Reason: entities.ExclusionAtCapacity})
+		default:
+			pool.Candidates = append(pool.Candidates, u.ID)
 		}
-		if _, ok := current[u.ID]; ok {
-			continue
-		}
-		candidates = append(candidates, u.ID)
-	}
-
-	if len(candidates) == 0 {
-		return "", entities.ErrNoCandidateFound
 	}
-
-	return candidates[s.rnd.Intn(len(candidates))], nil
+	return pool
 }
@@ -1,22 +1,19 @@
 package services
 
 import (
+	"cmp"
+	"slices"
 	"testing"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
 type MockRandomizer struct {
-	permResult []int
-	intnResult int
+	seed int64
 }
 
-func (m *MockRandomizer) Perm(n int) []int {
-	return m.permResult
-}
-
-func (m *MockRandomizer) Intn(n int) int {
-	return m.intnResult
+func (m *MockRandomizer) Seed() int64 {
+	return m.seed
 }
 
 func TestSelectReviewers(t *testing.T) {
@@ -38,12 +35,10 @@ func TestSelectReviewers(t *testing.T) {
 					entities.NewUser("user3", "Charlie", "Backend", true),
 				},
 			},
-			authorID: "user1",
-			mockRandomizer: &MockRandomizer{
-				permResult: []int{0, 1, 2},
-			},
-			expectedCount: 2,
-			expectError:   false,
+			authorID:       "user1",
+			mockRandomizer: &MockRandomizer{seed: 1},
+			expectedCount:  2,
+			expectError:    false,
 		},
 		{
 			name: "Select 1 reviewer from 2 active members (excluding author)",
@@ -54,12 +49,10 @@ func TestSelectReviewers(t *testing.T) {
 					entities.NewUser("user2", "Bob", "Backend", true),
 				},
 			},
-			authorID: "user1",
-			mockRandomizer: &MockRandomizer{
-				permResult: []int{0, 1},
-			},
-			expectedCount: 1,
-			expectError:   false,
+			authorID:       "user1",
+			mockRandomizer: &MockRandomizer{seed: 1},
+			expectedCount:  1,
+			expectError:    false,
 		},
 		{
 			name: "No active members except author",
@@ -79,9 +72,13 @@ func TestSelectReviewers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewReviewerAssignmentService(tt.mockRandomizer)
+			service := NewReviewerAssignmentService(tt.mockRandomizer, 0)
 
-			reviewers, err := service.SelectReviewers(tt.team, tt.authorID)
+			decision, err := service.SelectReviewers(tt.team, tt.authorID, nil)
+			var reviewers []string
+			if decision != nil {
+				reviewers = decision.Reviewers
+			}
 
 			if tt.expectError && err == nil {
 				t.Error("expected error, got nil")
@@ -124,10 +121,8 @@ func TestFindReplacement(t *testing.T) {
 			},
 			authorID:         "user1",
 			currentReviewers: []string{"user2"},
-			mockRandomizer: &MockRandomizer{
-				intnResult: 0,
-			},
-			expectError: false,
+			mockRandomizer:   &MockRandomizer{seed: 1},
+			expectError:      false,
 		},
 		{
 			name: "No replacement available",
@@ -147,9 +142,13 @@ func TestFindReplacement(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewReviewerAssignmentService(tt.mockRandomizer)
+			service := NewReviewerAssignmentService(tt.mockRandomizer, 0)
 
-			replacement, err := service.FindReplacement(tt.team, tt.authorID, tt.currentReviewers)
+			decision, err := service.FindReplacement(tt.team, tt.authorID, tt.currentReviewers, nil)
+			var replacement string
+			if decision != nil {
+				replacement = decision.Reviewers[0]
+			}
 
 			if tt.expectError && err == nil {
 				t.Error("expected error, got nil")
@@ -172,3 +171,104 @@ func TestFindReplacement(t *testing.T) {
 		})
 	}
 }
+
+func TestAssignRecordsDecision(t *testing.T) {
+	team := &entities.Team{
+		Name: "Backend",
+		Members: []*entities.User{
+			entities.NewUser("user1", "Alice", "Backend", true),
+			entities.NewUser("user2", "Bob", "Backend", false),
+			entities.NewUser("user3", "Charlie", "Backend", true),
+			entities.NewUser("user4", "Dave", "Backend", true),
+			entities.NewUser("user5", "Eve", "Backend", true),
+		},
+	}
+	service := NewReviewerAssignmentService(&MockRandomizer{seed: 42}, 0)
+
+	decision := service.Assign(team, "user1", nil)
+	if decision.Kind != entities.AssignmentCreate || decision.Strategy != RandomStrategy || decision.Seed != 42 {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+	wantExcluded := []entities.ReviewerExclusion{
+		{UserID: "user1", Reason: entities.ExclusionAuthor},
+		{UserID: "user2", Reason: entities.ExclusionInactive},
+	}
+	if !slices.Equal(decision.Excluded, wantExcluded) {
+		t.Errorf("expected exclusions %v, got %v", wantExcluded, decision.Excluded)
+	}
+
+	ids := make([]string, 0, len(decision.Candidates))
+	for _, candidate := range decision.Candidates {
+		ids = append(ids, candidate.UserID)
+	}
+	if !slices.Equal(ids, []string{"user3", "user4", "user5"}) {
+		t.Fatalf("expected candidates in team order, got %v", ids)
+	}
+	ranked := slices.Clone(decision.Candidates)
+	slices.SortFunc(ranked, func(a, b entities.ScoredCandidate) int { return cmp.Compare(b.Score, a.Score) })
+	if want := []string{ranked[0].UserID, ranked[1].UserID}; !slices.Equal(decision.Reviewers, want) {
+		t.Errorf("expected the two best scores %v, got %v", want, decision.Reviewers)
+	}
+
+	// The seed replays the decision.
+	if again := service.Assign(team, "user1", nil); !slices.Equal(again.Candidates, decision.Candidates) {
+		t.Errorf("expected the same scores from the same seed, got %v and %v", again.Candidates, decision.Candidates)
+	}
+
+	replacement, err := service.FindReplacement(team, "user1", decision.Reviewers, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replacement.Kind != entities.AssignmentReassign || len(replacement.Reviewers) != 1 {
+		t.Errorf("unexpected replacement %+v", replacement)
+	}
+	reasons := map[string]entities.ExclusionReason{}
+	for _, exclusion := range replacement.Excluded {
+		reasons[exclusion.UserID] = exclusion.Reason
+	}
+	for _, id := range decision.Reviewers {
+		if reasons[id] != entities.ExclusionReviewer {
+			t.Errorf("expected current reviewer %s to be excluded, got %q", id, reasons[id])
+		}
+	}
+}
+
+func TestAssignSkipsMembersOnLeaveOrAtCapacity(t *testing.T) {
+	away := entities.NewUser("user2", "Bob", "Backend", true)
+	away.SetOnLeave(true)
+	team := &entities.Team{
+		Name: "Backend",
+		Members: []*entities.User{
+			entities.NewUser("user1", "Alice", "Backend", true),
+			away,
+			entities.NewUser("user3", "Charlie", "Backend", true),
+			entities.NewUser("user4", "Dave", "Backend", true),
+			entities.NewUser("user5", "Eve", "Backend", true),
+		},
+	}
+	openReviews := map[string]int{"user3": 3, "user4": 2, "user5": 5}
+	service := NewReviewerAssignmentService(&MockRandomizer{seed: 42}, 3)
+
+	decision := service.Assign(team, "user1", openReviews)
+	wantExcluded := []entities.ReviewerExclusion{
+		{UserID: "user1", Reason: entities.ExclusionAuthor},
+		{UserID: "user2", Reason: entities.ExclusionOnLeave},
+		{UserID: "user3", Reason: entities.ExclusionAtCapacity},
+		{UserID: "user5", Reason: entities.ExclusionAtCapacity},
+	}
+	if !slices.Equal(decision.Excluded, wantExcluded) {
+		t.Errorf("expected exclusions %v, got %v", wantExcluded, decision.Excluded)
+	}
+	if !slices.Equal(decision.Reviewers, []string{"user4"}) {
+		t.Errorf("expected only user4 to be assigned, got %v", decision.Reviewers)
+	}
+
+	if _, err := service.FindReplacement(team, "user1", []string{"user4"}, openReviews); err != entities.ErrNoCandidateFound {
+		t.Errorf("expected no replacement when everyone else is away or busy, got %v", err)
+	}
+
+	unlimited := NewReviewerAssignmentService(&MockRandomizer{seed: 42}, 0).Assign(team, "user1", openReviews)
+	if len(unlimited.Reviewers) != 2 {
+		t.Errorf("expected no capacity limit by default, got %v", unlimited.Reviewers)
+	}
+}
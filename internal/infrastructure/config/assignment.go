@@ -0,0 +1,13 @@
+package config
+
+type AssignmentConfig struct {
+	// MaxOpenReviews is how many open pull requests a member may review
+	// before they are skipped by reviewer assignment; zero means no limit.
+	MaxOpenReviews int
+}
+
+func loadAssignmentConfig() AssignmentConfig {
+	return AssignmentConfig{
+		MaxOpenReviews: getEnvInt("REVIEWER_MAX_OPEN_REVIEWS", 0),
+	}
+}
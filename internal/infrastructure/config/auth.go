@@ -0,0 +1,53 @@
+package config
+
+import "time"
+
+type AuthConfig struct {
+	JWTSecret     string
+	JWTSecretFile string
+	JWTKeyID      string
+	// JWTPreviousKeys lists retired keys that are still accepted for
+	// verification, as comma-separated kid=secret pairs.
+	JWTPreviousKeys string
+	JWTIssuer       string
+	JWTAudience     string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	BcryptCost      int
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+	// DenylistReloadInterval controls how quickly token revocations made by
+	// other instances take effect.
+	DenylistReloadInterval time.Duration
+	// OIDC settings; tokens from the identity provider are accepted only when
+	// OIDCJWKSURL is set.
+	OIDCJWKSURL      string
+	OIDCIssuer       string
+	OIDCAudience     string
+	OIDCUserIDClaim  string
+	OIDCJWKSCacheTTL time.Duration
+}
+
+func loadAuthConfig() AuthConfig {
+	return AuthConfig{
+		JWTSecret:       getEnvWithDefault("JWT_SECRET", ""),
+		JWTSecretFile:   getEnvWithDefault("JWT_SECRET_FILE", ""),
+		JWTKeyID:        getEnvWithDefault("JWT_KEY_ID", "default"),
+		JWTPreviousKeys: getEnvWithDefault("JWT_PREVIOUS_KEYS", ""),
+		JWTIssuer:       getEnvWithDefault("JWT_ISSUER", "pr-reviewer"),
+		JWTAudience:     getEnvWithDefault("JWT_AUDIENCE", "pr-reviewer-api"),
+		AccessTokenTTL:  time.Duration(getEnvInt("JWT_ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute,
+		RefreshTokenTTL: time.Duration(getEnvInt("JWT_REFRESH_TOKEN_TTL_HOURS", 720)) * time.Hour,
+		BcryptCost:      getEnvInt("AUTH_BCRYPT_COST", 12),
+		MaxFailedLogins: getEnvInt("AUTH_MAX_FAILED_LOGINS", 5),
+		LockoutDuration: time.Duration(getEnvInt("AUTH_LOCKOUT_MINUTES", 15)) * time.Minute,
+
+		DenylistReloadInterval: time.Duration(getEnvPositiveInt("AUTH_DENYLIST_RELOAD_SECONDS", 30)) * time.Second,
+
+		OIDCJWKSURL:      getEnvWithDefault("OIDC_JWKS_URL", ""),
+		OIDCIssuer:       getEnvWithDefault("OIDC_ISSUER", ""),
+		OIDCAudience:     getEnvWithDefault("OIDC_AUDIENCE", ""),
+		OIDCUserIDClaim:  getEnvWithDefault("OIDC_USER_ID_CLAIM", "sub"),
+		OIDCJWKSCacheTTL: time.Duration(getEnvInt("OIDC_JWKS_CACHE_TTL_MINUTES", 60)) * time.Minute,
+	}
+}
@@ -8,9 +8,15 @@ import (
 )
 
 type Config struct {
-	DB      DBConfig
-	Server  ServerConfig
-	Command Command
+	DB            DBConfig
+	Server        ServerConfig
+	Auth          AuthConfig
+	Notifications NotificationsConfig
+	RateLimit     RateLimitConfig
+	Tracing       TracingConfig
+	SCIM          SCIMConfig
+	Assignment    AssignmentConfig
+	Command       Command
 }
 
 type ServerConfig struct {
@@ -18,6 +24,11 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// ReadinessTimeout bounds each /readyz dependency check.
+	ReadinessTimeout time.Duration
+	// IdempotencyKeyTTL is how long responses are kept for replays of a
+	// request with the same Idempotency-Key.
+	IdempotencyKeyTTL time.Duration
 }
 
 type Command struct {
@@ -26,23 +37,41 @@ type Command struct {
 }
 
 func (c *Command) IsMigrationCommand() bool {
-	return c.Name == "migrate" || c.Name == "rollback" || c.Name == "migration-status"
+	switch c.Name {
+	case "migrate", "rollback", "force", "migration-status", "migration-pending":
+		return true
+	}
+	return false
+}
+
+func (c *Command) IsAdminCommand() bool {
+	return c.Name == "bootstrap-admin"
+}
+
+func (c *Command) IsSyncTeamsCommand() bool {
+	return c.Name == "sync-teams"
 }
 
 func Load(logger *slog.Logger) *Config {
 	command := parseCommand()
 
 	dbConfig := DBConfig{
-		Host:            getEnvWithDefault("DB_HOST", "localhost"),
-		Port:            getEnvWithDefault("DB_PORT", "5432"),
-		User:            getEnvWithDefault("DB_USER", "postgres"),
-		Password:        getEnvWithDefault("DB_PASSWORD", "postgres123"),
-		DBName:          getEnvWithDefault("DB_NAME", "pr_reviewer"),
-		SSLMode:         getEnvWithDefault("DB_SSL_MODE", "disable"),
-		MigrationsPath:  getEnvWithDefault("MIGRATIONS_PATH", "./migrations"),
-		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 25),
-		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
-		ConnMaxLifetime: time.Duration(getEnvInt("DB_CONN_MAX_LIFETIME_SECONDS", 300)) * time.Second,
+		Driver:             getEnvWithDefault("STORAGE_DRIVER", "postgres"),
+		SQLitePath:         getEnvWithDefault("SQLITE_PATH", "pr-reviewer.db"),
+		MemoryFixturePath:  getEnvWithDefault("MEMORY_FIXTURE_PATH", ""),
+		MemorySnapshotPath: getEnvWithDefault("MEMORY_SNAPSHOT_PATH", ""),
+		Host:               getEnvWithDefault("DB_HOST", "localhost"),
+		Port:               getEnvWithDefault("DB_PORT", "5432"),
+		User:               getEnvWithDefault("DB_USER", "postgres"),
+		Password:           getEnvWithDefault("DB_PASSWORD", "postgres123"),
+		DBName:             getEnvWithDefault("DB_NAME", "pr_reviewer"),
+		SSLMode:            getEnvWithDefault("DB_SSL_MODE", "disable"),
+		MigrationsPath:     getEnvWithDefault("MIGRATIONS_PATH", ""),
+		AutoMigrate:        getEnvBool("DB_AUTO_MIGRATE", false),
+		SchemaLockTimeout:  time.Duration(getEnvInt("DB_SCHEMA_LOCK_TIMEOUT_SECONDS", 120)) * time.Second,
+		MaxOpenConns:       getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:       getEnvInt("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime:    time.Duration(getEnvInt("DB_CONN_MAX_LIFETIME_SECONDS", 300)) * time.Second,
 	}
 
 	serverConfig := ServerConfig{
@@ -50,12 +79,21 @@ func Load(logger *slog.Logger) *Config {
 		ReadTimeout:  time.Duration(getEnvInt("SERVER_READ_TIMEOUT", 15)) * time.Second,
 		WriteTimeout: time.Duration(getEnvInt("SERVER_WRITE_TIMEOUT", 15)) * time.Second,
 		IdleTimeout:  time.Duration(getEnvInt("SERVER_IDLE_TIMEOUT", 60)) * time.Second,
+
+		ReadinessTimeout:  time.Duration(getEnvInt("READINESS_CHECK_TIMEOUT_SECONDS", 2)) * time.Second,
+		IdempotencyKeyTTL: time.Duration(getEnvInt("IDEMPOTENCY_KEY_TTL_HOURS", 24)) * time.Hour,
 	}
 
 	return &Config{
-		DB:      dbConfig,
-		Server:  serverConfig,
-		Command: command,
+		DB:            dbConfig,
+		Server:        serverConfig,
+		Auth:          loadAuthConfig(),
+		Notifications: loadNotificationsConfig(),
+		RateLimit:     loadRateLimitConfig(),
+		Tracing:       loadTracingConfig(),
+		SCIM:          loadSCIMConfig(),
+		Assignment:    loadAssignmentConfig(),
+		Command:       command,
 	}
 }
 
@@ -78,6 +116,26 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvPositiveInt is getEnvInt for values that must be positive, such as
+// ticker intervals, which panic when they are not.
+func getEnvPositiveInt(key string, defaultValue int) int {
+	i := getEnvInt(key, defaultValue)
+	if i <= 0 {
+		slog.Warn("ignoring non-positive value", "key", key, "default", defaultValue)
+		return defaultValue
+	}
+	return i
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 func getEnvWithDefault(key string, defaultValue string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -3,23 +3,46 @@ package config
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type DBConfig struct {
-	Host            string
-	Port            string
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MigrationsPath  string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	// Driver selects the storage backend: "postgres", "sqlite" or "memory".
+	Driver string
+	// SQLitePath is the database file used by the sqlite driver.
+	SQLitePath string
+	// MemoryFixturePath optionally seeds the memory driver from a JSON file.
+	MemoryFixturePath string
+	// MemorySnapshotPath, when set, is where the memory driver saves its
+	// state on shutdown and restores it from on start.
+	MemorySnapshotPath string
+
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+	// MigrationsPath overrides the embedded migrations with a directory on
+	// disk; leave empty to use the ones built into the binary.
+	MigrationsPath string
+	// AutoMigrate lets the server apply pending migrations at startup
+	// instead of refusing to start on an outdated schema.
+	AutoMigrate bool
+	// SchemaLockTimeout bounds how long startup waits for the schema lock
+	// held by another replica; the migration itself is not time-limited.
+	SchemaLockTimeout time.Duration
+	MaxOpenConns      int
+	MaxIdleConns      int
+	ConnMaxLifetime   time.Duration
 }
 
 func NewPostgresConnection(cfg *DBConfig) (*sql.DB, error) {
@@ -28,7 +51,7 @@ func NewPostgresConnection(cfg *DBConfig) (*sql.DB, error) {
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
 
-	db, err := sql.Open("postgres", connStr)
+	db, err := openTraced("postgres", connStr, semconv.DBSystemNamePostgreSQL)
 	if err != nil {
 		return nil, err
 	}
@@ -43,13 +66,37 @@ func NewPostgresConnection(cfg *DBConfig) (*sql.DB, error) {
 		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	}
 
+	if err := ping(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// openTraced opens a connection pool whose queries made while serving a
+// traced request get a span carrying their statement. Background work such as
+// metric scrapes is not traced, and row iteration and connection housekeeping
+// are left out to keep traces readable.
+func openTraced(driverName, dsn string, system attribute.KeyValue) (*sql.DB, error) {
+	return otelsql.Open(driverName, dsn,
+		otelsql.WithAttributes(system),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitRows:             true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	)
+}
+
+func ping(db *sql.DB) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
 		db.Close()
-		return nil, err
+		return err
 	}
-
-	return db, nil
+	return nil
 }
@@ -0,0 +1,64 @@
+package config
+
+import "time"
+
+type NotificationsConfig struct {
+	Slack  SlackConfig
+	Email  EmailConfig
+	Digest DigestConfig
+}
+
+type SlackConfig struct {
+	DefaultWebhookURL string
+	Template          string
+	TemplateFile      string
+	Timeout           time.Duration
+}
+
+type EmailConfig struct {
+	SMTPHost               string
+	SMTPPort               string
+	SMTPUsername           string
+	SMTPPassword           string
+	From                   string
+	AssignmentTemplateFile string
+	DigestTemplateFile     string
+	Timeout                time.Duration
+}
+
+func (c EmailConfig) Enabled() bool {
+	return c.SMTPHost != ""
+}
+
+type DigestConfig struct {
+	Enabled bool
+	// Time is the UTC time of day the digest is sent at, in HH:MM format.
+	Time string
+	SLA  time.Duration
+}
+
+func loadNotificationsConfig() NotificationsConfig {
+	return NotificationsConfig{
+		Slack: SlackConfig{
+			DefaultWebhookURL: getEnvWithDefault("SLACK_WEBHOOK_URL", ""),
+			Template:          getEnvWithDefault("SLACK_MESSAGE_TEMPLATE", ""),
+			TemplateFile:      getEnvWithDefault("SLACK_MESSAGE_TEMPLATE_FILE", ""),
+			Timeout:           time.Duration(getEnvInt("SLACK_TIMEOUT_SECONDS", 5)) * time.Second,
+		},
+		Email: EmailConfig{
+			SMTPHost:               getEnvWithDefault("SMTP_HOST", ""),
+			SMTPPort:               getEnvWithDefault("SMTP_PORT", "587"),
+			SMTPUsername:           getEnvWithDefault("SMTP_USERNAME", ""),
+			SMTPPassword:           getEnvWithDefault("SMTP_PASSWORD", ""),
+			From:                   getEnvWithDefault("SMTP_FROM", ""),
+			AssignmentTemplateFile: getEnvWithDefault("EMAIL_ASSIGNMENT_TEMPLATE_FILE", ""),
+			DigestTemplateFile:     getEnvWithDefault("EMAIL_DIGEST_TEMPLATE_FILE", ""),
+			Timeout:                time.Duration(getEnvInt("SMTP_TIMEOUT_SECONDS", 10)) * time.Second,
+		},
+		Digest: DigestConfig{
+			Enabled: getEnvBool("DIGEST_ENABLED", false),
+			Time:    getEnvWithDefault("DIGEST_TIME", "09:00"),
+			SLA:     time.Duration(getEnvInt("REVIEW_SLA_HOURS", 48)) * time.Hour,
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+type RateLimitConfig struct {
+	Enabled bool
+	// Backend is "memory" for a per-instance limiter or "postgres" to share
+	// limits between replicas.
+	Backend string
+	// Default applies to every route without an override, e.g. "100/m".
+	Default string
+	// Routes lists per-route overrides as "<METHOD> <path>=<limit>" entries
+	// separated by semicolons.
+	Routes string
+	// TrustProxy keys anonymous callers by X-Forwarded-For; enable it only
+	// behind a proxy that sets the header.
+	TrustProxy    bool
+	PruneInterval time.Duration
+}
+
+func loadRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Enabled:       getEnvBool("RATE_LIMIT_ENABLED", true),
+		Backend:       getEnvWithDefault("RATE_LIMIT_BACKEND", "memory"),
+		Default:       getEnvWithDefault("RATE_LIMIT_DEFAULT", "120/m"),
+		Routes:        getEnvWithDefault("RATE_LIMIT_ROUTES", "POST /login=10/m;POST /token/refresh=30/m;POST /pullRequest/create=30/m"),
+		TrustProxy:    getEnvBool("RATE_LIMIT_TRUST_PROXY", false),
+		PruneInterval: time.Duration(getEnvPositiveInt("RATE_LIMIT_PRUNE_MINUTES", 10)) * time.Minute,
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+type SCIMConfig struct {
+	// DefaultTeam holds provisioned users until a group assigns them a team,
+	// and receives the members of groups that are deleted or shrink.
+	DefaultTeam string
+}
+
+func loadSCIMConfig() SCIMConfig {
+	return SCIMConfig{
+		DefaultTeam: getEnvWithDefault("SCIM_DEFAULT_TEAM", "unassigned"),
+	}
+}
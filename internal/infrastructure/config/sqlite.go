@@ -0,0 +1,37 @@
+package config
+
+import (
+	"database/sql"
+	"net/url"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	_ "modernc.org/sqlite"
+)
+
+// NewSQLiteConnection opens the database file at cfg.SQLitePath with foreign
+// keys enforced. Transactions take the write lock up front and wait for
+// other writers instead of failing with SQLITE_BUSY.
+func NewSQLiteConnection(cfg *DBConfig) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+
+	db, err := openTraced("sqlite", cfg.SQLitePath+"?"+params.Encode(), semconv.DBSystemNameSQLite)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+
+	if err := ping(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
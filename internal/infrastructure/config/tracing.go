@@ -0,0 +1,18 @@
+package config
+
+type TracingConfig struct {
+	// Exporter is "none", "stdout" or "otlp".
+	Exporter    string
+	ServiceName string
+	// OTLPEndpoint is the collector URL, e.g. http://collector:4318. When
+	// empty the standard OTEL_EXPORTER_OTLP_* variables apply.
+	OTLPEndpoint string
+}
+
+func loadTracingConfig() TracingConfig {
+	return TracingConfig{
+		Exporter:     getEnvWithDefault("TRACING_EXPORTER", "none"),
+		ServiceName:  getEnvWithDefault("OTEL_SERVICE_NAME", "pr-reviewer"),
+		OTLPEndpoint: getEnvWithDefault("TRACING_OTLP_ENDPOINT", ""),
+	}
+}
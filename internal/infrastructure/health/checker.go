@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc probes one dependency. The returned detail is reported on success.
+type CheckFunc func(ctx context.Context) (string, error)
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs the readiness checks concurrently, each bounded by timeout.
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]CheckFunc
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: make(map[string]CheckFunc)}
+}
+
+func (c *Checker) Add(name string, check CheckFunc) {
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+		sort.Strings(c.names)
+	}
+	c.checks[name] = check
+}
+
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.names))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check(ctx)
+	result := CheckResult{Status: StatusOK, Detail: detail, DurationMS: time.Since(start).Milliseconds()}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = ""
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// DatabaseCheck pings the database.
+func DatabaseCheck(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		if err := db.PingContext(ctx); err != nil {
+			return "", fmt.Errorf("ping: %w", err)
+		}
+		return "", nil
+	}
+}
+
+// MigrationCheck fails while the applied schema is behind or ahead of the
+// version the binary ships with, or a migration was left dirty.
+func MigrationCheck(repo ports.MigrationRepository) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		current, dirty, err := repo.GetCurrentVersion(ctx)
+		if err != nil {
+			return "", fmt.Errorf("get current version: %w", err)
+		}
+		expected, err := repo.GetLatestVersion(ctx)
+		if err != nil {
+			return "", fmt.Errorf("get expected version: %w", err)
+		}
+
+		if dirty {
+			return "", fmt.Errorf("migration %d is dirty", current)
+		}
+		if current != expected {
+			return "", fmt.Errorf("schema version %d, expected %d", current, expected)
+		}
+		return fmt.Sprintf("version %d", current), nil
+	}
+}
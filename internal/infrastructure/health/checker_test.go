@@ -0,0 +1,85 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/health"
+)
+
+type fakeMigrationRepo struct {
+	current uint
+	dirty   bool
+	latest  uint
+	err     error
+}
+
+func (r *fakeMigrationRepo) MigrateTo(ctx context.Context, version uint) error { return nil }
+func (r *fakeMigrationRepo) Lock(ctx context.Context) (func(), error)          { return func() {}, nil }
+func (r *fakeMigrationRepo) Force(ctx context.Context, version int) error      { return nil }
+
+func (r *fakeMigrationRepo) ListMigrations(ctx context.Context) ([]ports.Migration, error) {
+	return nil, nil
+}
+
+func (r *fakeMigrationRepo) Plan(ctx context.Context, version uint) ([]ports.MigrationStep, error) {
+	return nil, nil
+}
+
+func (r *fakeMigrationRepo) GetCurrentVersion(ctx context.Context) (uint, bool, error) {
+	return r.current, r.dirty, r.err
+}
+
+func (r *fakeMigrationRepo) GetLatestVersion(ctx context.Context) (uint, error) {
+	return r.latest, nil
+}
+
+func TestMigrationCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		repo        *fakeMigrationRepo
+		expectedErr bool
+	}{
+		{"up to date", &fakeMigrationRepo{current: 10, latest: 10}, false},
+		{"behind", &fakeMigrationRepo{current: 9, latest: 10}, true},
+		{"ahead after a newer deploy", &fakeMigrationRepo{current: 11, latest: 10}, true},
+		{"dirty", &fakeMigrationRepo{current: 10, dirty: true, latest: 10}, true},
+		{"unreachable", &fakeMigrationRepo{err: errors.New("connection refused")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, err := health.MigrationCheck(tt.repo)(context.Background())
+			if tt.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil && detail != "version 10" {
+				t.Errorf("unexpected detail %q", detail)
+			}
+		})
+	}
+}
+
+func TestCheckerReportsEachCheck(t *testing.T) {
+	checker := health.NewChecker(50 * time.Millisecond)
+	checker.Add("database", func(ctx context.Context) (string, error) { return "", nil })
+	checker.Add("slow", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	report := checker.Run(context.Background())
+	if report.OK() {
+		t.Fatal("expected report to fail when a check times out")
+	}
+	if got := report.Checks["database"].Status; got != health.StatusOK {
+		t.Errorf("expected database check to pass, got %q", got)
+	}
+	slow := report.Checks["slow"]
+	if slow.Status != health.StatusFail || slow.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("expected slow check to time out, got %+v", slow)
+	}
+}
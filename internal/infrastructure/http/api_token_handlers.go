@@ -0,0 +1,172 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type APITokenHandler struct {
+	createCmd  *commands.CreateAPITokenCommand
+	revokeCmd  *commands.RevokeAPITokenCommand
+	listQuery  *queries.ListAPITokensQuery
+	authorizer *authz.Authorizer
+	logger     *slog.Logger
+}
+
+func NewAPITokenHandler(
+	createCmd *commands.CreateAPITokenCommand,
+	revokeCmd *commands.RevokeAPITokenCommand,
+	listQuery *queries.ListAPITokensQuery,
+	authorizer *authz.Authorizer,
+	logger *slog.Logger,
+) *APITokenHandler {
+	return &APITokenHandler{
+		createCmd:  createCmd,
+		revokeCmd:  revokeCmd,
+		listQuery:  listQuery,
+		authorizer: authorizer,
+		logger:     logger,
+	}
+}
+
+func (h *APITokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.UserID == "" || req.Name == "" || len(req.Scopes) == 0 {
+		h.log(r).Error("validation error", "error", "missing required fields")
+		respondWithError(w, http.StatusBadRequest, "user_id, name and scopes are required")
+		return
+	}
+	if req.ExpiresInDays < 0 {
+		h.log(r).Error("validation error", "error", "expires_in_days is negative")
+		respondWithError(w, http.StatusBadRequest, "expires_in_days cannot be negative")
+		return
+	}
+
+	scopes, err := entities.ParseScopes(req.Scopes)
+	if err != nil {
+		h.log(r).Error("validation error", "error", err, "scopes", req.Scopes)
+		respondWithErrorCode(w, http.StatusBadRequest, "INVALID_SCOPE", "Unknown scope requested")
+		return
+	}
+
+	if !h.authorize(w, r) {
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, raw, err := h.createCmd.Execute(r.Context(), req.UserID, req.Name, scopes, ttl)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.log(r).Info("api token created", "token_id", token.ID, "user_id", token.UserID, "created_by", GetUserIDFromContext(r))
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPITokenResponse{
+		Token:    raw,
+		APIToken: MapAPITokenToResponse(token),
+	})
+}
+
+func (h *APITokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !h.authorize(w, r) {
+		return
+	}
+
+	tokens, err := h.listQuery.Execute(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	responses := make([]APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		responses = append(responses, MapAPITokenToResponse(token))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"api_tokens": responses,
+	})
+}
+
+func (h *APITokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req RevokeAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.TokenID == "" {
+		h.log(r).Error("validation error", "error", "token_id is empty")
+		respondWithError(w, http.StatusBadRequest, "token_id is required")
+		return
+	}
+
+	if !h.authorize(w, r) {
+		return
+	}
+
+	token, err := h.revokeCmd.Execute(r.Context(), req.TokenID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.log(r).Info("api token revoked", "token_id", token.ID, "revoked_by", GetUserIDFromContext(r))
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapAPITokenToResponse(token))
+}
+
+func (h *APITokenHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	err := h.authorizer.Authorize(r.Context(), GetUserIDFromContext(r), authz.ActionManageAPITokens, "")
+	if err != nil {
+		h.handleError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func (h *APITokenHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	h.log(r).Error("error occurred", "error", err)
+
+	switch {
+	case errors.Is(err, entities.ErrForbidden):
+		respondWithErrorCode(w, http.StatusForbidden, "FORBIDDEN", "Action is not permitted")
+	case errors.Is(err, entities.ErrUserNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, "NOT_FOUND", "User not found")
+	case errors.Is(err, entities.ErrAPITokenNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, "NOT_FOUND", "API token not found")
+	case errors.Is(err, entities.ErrInvalidScope):
+		respondWithErrorCode(w, http.StatusBadRequest, "INVALID_SCOPE", "Unknown scope requested")
+	default:
+		respondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func (h *APITokenHandler) log(r *http.Request) *slog.Logger {
+	return requestLogger(r, h.logger)
+}
@@ -0,0 +1,333 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type AuthHandler struct {
+	authenticateCmd   *commands.AuthenticateCommand
+	setPasswordCmd    *commands.SetPasswordCommand
+	revokeSessionsCmd *commands.RevokeUserSessionsCommand
+	userRepo          ports.UserRepository
+	refreshTokenRepo  ports.RefreshTokenRepository
+	revoker           ports.SessionRevoker
+	tokens            *TokenManager
+	authorizer        *authz.Authorizer
+	logger            *slog.Logger
+}
+
+func NewAuthHandler(
+	authenticateCmd *commands.AuthenticateCommand,
+	setPasswordCmd *commands.SetPasswordCommand,
+	revokeSessionsCmd *commands.RevokeUserSessionsCommand,
+	userRepo ports.UserRepository,
+	refreshTokenRepo ports.RefreshTokenRepository,
+	revoker ports.SessionRevoker,
+	tokens *TokenManager,
+	authorizer *authz.Authorizer,
+	logger *slog.Logger,
+) *AuthHandler {
+	return &AuthHandler{
+		authenticateCmd:   authenticateCmd,
+		setPasswordCmd:    setPasswordCmd,
+		revokeSessionsCmd: revokeSessionsCmd,
+		userRepo:          userRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		revoker:           revoker,
+		tokens:            tokens,
+		authorizer:        authorizer,
+		logger:            logger,
+	}
+}
+
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.UserID == "" || req.Password == "" {
+		h.log(r).Error("validation error", "error", "user_id or password is empty")
+		respondWithError(w, http.StatusBadRequest, "user_id and password are required")
+		return
+	}
+
+	user, err := h.authenticateCmd.Execute(r.Context(), req.UserID, req.Password)
+	if err != nil {
+		h.log(r).Error("login failed", "user_id", req.UserID, "error", err)
+		switch {
+		case errors.Is(err, entities.ErrInvalidCredentials):
+			respondWithError(w, http.StatusUnauthorized, "Invalid credentials")
+		case errors.Is(err, entities.ErrAccountLocked):
+			respondWithError(w, http.StatusTooManyRequests, "Account is temporarily locked")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	familyID, err := randomID()
+	if err != nil {
+		h.log(r).Error("failed to generate token family", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	resp, err := h.issueTokens(r.Context(), user.ID, familyID)
+	if err != nil {
+		h.log(r).Error("failed to generate token", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AuthHandler) SetPassword(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req SetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.UserID == "" || req.Password == "" {
+		h.log(r).Error("validation error", "error", "user_id or password is empty")
+		respondWithError(w, http.StatusBadRequest, "user_id and password are required")
+		return
+	}
+
+	err := h.authorizer.Authorize(r.Context(), GetUserIDFromContext(r), authz.ActionSetPassword, req.UserID)
+	if err == nil {
+		err = h.setPasswordCmd.Execute(r.Context(), req.UserID, req.Password)
+	}
+	if err != nil {
+		h.log(r).Error("set password failed", "user_id", req.UserID, "error", err)
+		switch {
+		case errors.Is(err, entities.ErrForbidden):
+			respondWithErrorCode(w, http.StatusForbidden, "FORBIDDEN", "Action is not permitted")
+		case errors.Is(err, entities.ErrUserNotFound):
+			respondWithError(w, http.StatusNotFound, "User not found")
+		case errors.Is(err, entities.ErrWeakPassword):
+			respondWithError(w, http.StatusBadRequest, "Password is too short")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Logout revokes the access token used for the request and, when given, the
+// refresh token family it was issued with.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	principal := GetPrincipalFromContext(r)
+	if principal == nil || principal.TokenID == "" {
+		h.log(r).Error("logout without a session token")
+		respondWithError(w, http.StatusBadRequest, "Only session tokens can be logged out")
+		return
+	}
+
+	var req LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.log(r).Error("invalid request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := h.logout(r.Context(), principal, req.RefreshToken); err != nil {
+		h.log(r).Error("logout failed", "user_id", principal.UserID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to log out")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) logout(ctx context.Context, principal *Principal, rawRefreshToken string) error {
+	if err := h.revoker.RevokeToken(ctx, principal.TokenID, principal.UserID, principal.ExpiresAt); err != nil {
+		return err
+	}
+	if rawRefreshToken == "" {
+		return nil
+	}
+
+	stored, err := h.refreshTokenRepo.GetByHash(ctx, HashRefreshToken(rawRefreshToken))
+	if err != nil {
+		return err
+	}
+	if stored == nil || stored.UserID != principal.UserID {
+		return nil
+	}
+	return h.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// RevokeSessions logs a user out of every session.
+func (h *AuthHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req RevokeSessionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.UserID == "" {
+		h.log(r).Error("validation error", "error", "user_id is empty")
+		respondWithError(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+
+	err := h.authorizer.Authorize(r.Context(), GetUserIDFromContext(r), authz.ActionRevokeSessions, req.UserID)
+	if err == nil {
+		err = h.revokeSessionsCmd.Execute(r.Context(), req.UserID)
+	}
+	if err != nil {
+		h.log(r).Error("revoke sessions failed", "user_id", req.UserID, "error", err)
+		switch {
+		case errors.Is(err, entities.ErrForbidden):
+			respondWithErrorCode(w, http.StatusForbidden, "FORBIDDEN", "Action is not permitted")
+		case errors.Is(err, entities.ErrUserNotFound):
+			respondWithError(w, http.StatusNotFound, "User not found")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	h.log(r).Info("user sessions revoked", "user_id", req.UserID, "revoked_by", GetUserIDFromContext(r))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. Every refresh
+// token is single-use: presenting one that was already rotated revokes the
+// whole token family, since it means the token has leaked.
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.RefreshToken == "" {
+		h.log(r).Error("validation error", "error", "refresh_token is empty")
+		respondWithError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	resp, err := h.rotate(r.Context(), req.RefreshToken)
+	if err != nil {
+		h.log(r).Error("refresh failed", "error", err)
+		switch {
+		case errors.Is(err, entities.ErrRefreshTokenInvalid), errors.Is(err, entities.ErrRefreshTokenReused):
+			respondWithError(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to refresh token")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AuthHandler) rotate(ctx context.Context, rawToken string) (*LoginResponse, error) {
+	stored, err := h.refreshTokenRepo.GetByHash(ctx, HashRefreshToken(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, entities.ErrRefreshTokenInvalid
+	}
+
+	now := time.Now().UTC()
+	if stored.IsRevoked() {
+		if err := h.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, entities.ErrRefreshTokenReused
+	}
+	if stored.IsExpired(now) {
+		return nil, entities.ErrRefreshTokenInvalid
+	}
+
+	user, err := h.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, entities.ErrRefreshTokenInvalid
+	}
+
+	// A concurrent refresh with the same token may have got here first, in
+	// which case this one is a reuse.
+	consumed, err := h.refreshTokenRepo.Consume(ctx, stored.TokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		if err := h.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, entities.ErrRefreshTokenReused
+	}
+
+	return h.issueTokens(ctx, user.ID, stored.FamilyID)
+}
+
+func (h *AuthHandler) issueTokens(ctx context.Context, userID, familyID string) (*LoginResponse, error) {
+	accessToken, err := h.tokens.GenerateToken(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rawRefresh, refreshHash, err := NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshID, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := entities.NewRefreshToken(refreshID, userID, familyID, refreshHash, h.tokens.RefreshTokenTTL())
+	if err := h.refreshTokenRepo.Save(ctx, refresh); err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		Token:        accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(h.tokens.AccessTokenTTL().Seconds()),
+		RefreshToken: rawRefresh,
+	}, nil
+}
+
+func (h *AuthHandler) log(r *http.Request) *slog.Logger {
+	return requestLogger(r, h.logger)
+}
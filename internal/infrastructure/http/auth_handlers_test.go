@@ -0,0 +1,250 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct-horse-battery"
+
+func newTestAuthHandler(t *testing.T) *AuthHandler {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clock := services.NewRealClock()
+
+	admin := entities.NewUser("admin", "root", "ops", true)
+	admin.SetRole(entities.RoleAdmin)
+	userRepo := repositories.NewInMemoryUserRepository()
+	for _, u := range []*entities.User{entities.NewUser("user1", "alice", "backend", true), admin} {
+		if err := userRepo.Save(ctx, u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+
+	credentialRepo := repositories.NewInMemoryCredentialRepository()
+	hasher := security.NewBcryptHasher(bcrypt.MinCost)
+	setPasswordCmd := commands.NewSetPasswordCommand(userRepo, credentialRepo, hasher, clock)
+	if err := setPasswordCmd.Execute(ctx, "user1", testPassword); err != nil {
+		t.Fatalf("failed to set password: %v", err)
+	}
+
+	lockout := entities.LockoutPolicy{MaxFailedAttempts: 3, LockoutDuration: time.Minute}
+	authenticateCmd := commands.NewAuthenticateCommand(userRepo, credentialRepo, hasher, lockout, clock, logger)
+
+	refreshTokenRepo := repositories.NewInMemoryRefreshTokenRepository()
+	denylist := security.NewDenylist(repositories.NewInMemoryTokenRevocationRepository(), refreshTokenRepo, clock, logger)
+
+	return NewAuthHandler(
+		authenticateCmd,
+		setPasswordCmd,
+		commands.NewRevokeUserSessionsCommand(userRepo, denylist),
+		userRepo,
+		refreshTokenRepo,
+		denylist,
+		newTestTokenManager(t, TokenConfig{}),
+		authz.NewAuthorizer(userRepo, repositories.NewInMemoryPRRepository(userRepo, repositories.NewInMemoryAssignmentDecisionRepository())),
+		logger,
+	)
+}
+
+func postJSON(h http.HandlerFunc, body any) (*httptest.ResponseRecorder, LoginResponse) {
+	payload, _ := json.Marshal(body)
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload)))
+	var resp LoginResponse
+	json.NewDecoder(bytes.NewReader(rec.Body.Bytes())).Decode(&resp)
+	return rec, resp
+}
+
+func TestLoginRequiresValidCredentials(t *testing.T) {
+	handler := newTestAuthHandler(t)
+
+	tests := []struct {
+		name           string
+		req            LoginRequest
+		expectedStatus int
+	}{
+		{"Correct password", LoginRequest{UserID: "user1", Password: testPassword}, http.StatusOK},
+		{"Wrong password", LoginRequest{UserID: "user1", Password: "wrong-password"}, http.StatusUnauthorized},
+		{"Unknown user", LoginRequest{UserID: "ghost", Password: testPassword}, http.StatusUnauthorized},
+		{"Missing password", LoginRequest{UserID: "user1"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := postJSON(handler.Login, tt.req)
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestLoginLocksOutAfterRepeatedFailures(t *testing.T) {
+	handler := newTestAuthHandler(t)
+
+	for i := 0; i < 3; i++ {
+		rec, _ := postJSON(handler.Login, LoginRequest{UserID: "user1", Password: "wrong-password"})
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected status 401, got %d", i+1, rec.Code)
+		}
+	}
+
+	rec, _ := postJSON(handler.Login, LoginRequest{UserID: "user1", Password: testPassword})
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected locked account to be rejected with 429, got %d", rec.Code)
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	handler := newTestAuthHandler(t)
+
+	rec, login := postJSON(handler.Login, LoginRequest{UserID: "user1", Password: testPassword})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if login.RefreshToken == "" || login.ExpiresIn == 0 {
+		t.Fatalf("expected refresh token and expiry, got %+v", login)
+	}
+
+	rec, refreshed := postJSON(handler.RefreshToken, RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if refreshed.RefreshToken == login.RefreshToken {
+		t.Error("expected refresh token to be rotated")
+	}
+
+	rec, _ = postJSON(handler.RefreshToken, RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected reused refresh token to be rejected, got %d", rec.Code)
+	}
+
+	rec, _ = postJSON(handler.RefreshToken, RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected token family to be revoked after reuse, got %d", rec.Code)
+	}
+}
+
+func TestConcurrentRefreshesIssueOneTokenPair(t *testing.T) {
+	handler := newTestAuthHandler(t)
+	_, login := postJSON(handler.Login, LoginRequest{UserID: "user1", Password: testPassword})
+
+	const attempts = 8
+	codes := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec, _ := postJSON(handler.RefreshToken, RefreshTokenRequest{RefreshToken: login.RefreshToken})
+			codes <- rec.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	succeeded := 0
+	for code := range codes {
+		if code == http.StatusOK {
+			succeeded++
+		}
+	}
+	if succeeded > 1 {
+		t.Errorf("expected at most one refresh to succeed, got %d", succeeded)
+	}
+}
+
+// authenticatedRequest runs h as the holder of accessToken, the way the router
+// would after AuthMiddleware.
+func authenticatedRequest(handler *AuthHandler, h http.HandlerFunc, accessToken string, body any) *httptest.ResponseRecorder {
+	authenticator := NewAuthenticator(handler.tokens, nil, nil, handler.revoker.(RevocationChecker))
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	rec := httptest.NewRecorder()
+	AuthMiddleware(handler.logger, authenticator, "", h)(rec, req)
+	return rec
+}
+
+func TestLogoutRevokesAccessAndRefreshTokens(t *testing.T) {
+	handler := newTestAuthHandler(t)
+
+	_, login := postJSON(handler.Login, LoginRequest{UserID: "user1", Password: testPassword})
+	_, other := postJSON(handler.Login, LoginRequest{UserID: "user1", Password: testPassword})
+
+	rec := authenticatedRequest(handler, handler.Logout, login.Token, LogoutRequest{RefreshToken: login.RefreshToken})
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = authenticatedRequest(handler, handler.Logout, login.Token, nil)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected logged out token to be rejected, got %d", rec.Code)
+	}
+	rec, _ = postJSON(handler.RefreshToken, RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected refresh token to be revoked, got %d", rec.Code)
+	}
+
+	rec = authenticatedRequest(handler, handler.Logout, other.Token, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected other session to stay valid, got %d", rec.Code)
+	}
+}
+
+func TestRevokeSessionsInvalidatesAllUserTokens(t *testing.T) {
+	handler := newTestAuthHandler(t)
+
+	_, victim := postJSON(handler.Login, LoginRequest{UserID: "user1", Password: testPassword})
+	adminToken, err := handler.tokens.GenerateToken("admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	userToken, err := handler.tokens.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := authenticatedRequest(handler, handler.RevokeSessions, userToken, RevokeSessionsRequest{UserID: "user1"})
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected non-admin to be forbidden, got %d", rec.Code)
+	}
+
+	rec = authenticatedRequest(handler, handler.RevokeSessions, adminToken, RevokeSessionsRequest{UserID: "user1"})
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, token := range []string{victim.Token, userToken} {
+		rec = authenticatedRequest(handler, handler.Logout, token, nil)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected revoked session to be rejected, got %d", rec.Code)
+		}
+	}
+	rec, _ = postJSON(handler.RefreshToken, RefreshTokenRequest{RefreshToken: victim.RefreshToken})
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected refresh token to be revoked, got %d", rec.Code)
+	}
+
+	rec = authenticatedRequest(handler, handler.Logout, adminToken, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected admin session to stay valid, got %d", rec.Code)
+	}
+}
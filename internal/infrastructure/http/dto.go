@@ -5,29 +5,80 @@ import "time"
 // Request DTOs
 
 type LoginRequest struct {
-	UserID string `json:"user_id"`
+	UserID   string `json:"user_id"`
+	Password string `json:"password"`
+}
+
+type SetPasswordRequest struct {
+	UserID   string `json:"user_id"`
+	Password string `json:"password"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 type CreateTeamRequest struct {
-	TeamName string              `json:"team_name"`
-	Members  []CreateUserRequest `json:"members"`
+	TeamName       string              `json:"team_name"`
+	Members        []CreateUserRequest `json:"members"`
+	ChatWebhookURL string              `json:"chat_webhook_url,omitempty"`
 }
 
 type CreateUserRequest struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	IsActive   bool   `json:"is_active"`
+	ChatHandle string `json:"chat_handle,omitempty"`
 }
 
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
+	// RevokeSessions also logs a deactivated user out everywhere.
+	RevokeSessions bool `json:"revoke_sessions,omitempty"`
+}
+
+type RevokeSessionsRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type SetUserRoleRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+type SetUserOnLeaveRequest struct {
+	UserID  string `json:"user_id"`
+	OnLeave bool   `json:"on_leave"`
+}
+
+type SetNotificationPreferencesRequest struct {
+	UserID            string `json:"user_id"`
+	Email             string `json:"email"`
+	EmailOnAssignment bool   `json:"email_on_assignment"`
+	EmailDigest       bool   `json:"email_digest"`
+}
+
+type CreateAPITokenRequest struct {
+	UserID        string   `json:"user_id"`
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty"`
+}
+
+type RevokeAPITokenRequest struct {
+	TokenID string `json:"token_id"`
 }
 
 type CreatePRRequest struct {
-	PRID     string `json:"pull_request_id"`
-	PRName   string `json:"pull_request_name"`
-	AuthorID string `json:"author_id"`
+	PRID     string   `json:"pull_request_id"`
+	PRName   string   `json:"pull_request_name"`
+	AuthorID string   `json:"author_id"`
+	Labels   []string `json:"labels,omitempty"`
 }
 
 type MergePRRequest struct {
@@ -42,7 +93,26 @@ type ReassignReviewerRequest struct {
 // Response DTOs
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type APITokenResponse struct {
+	ID        string     `json:"token_id"`
+	Name      string     `json:"name"`
+	UserID    string     `json:"user_id"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreateAPITokenResponse struct {
+	// Token is the raw secret; it is only returned once, on creation.
+	Token    string           `json:"token"`
+	APIToken APITokenResponse `json:"api_token"`
 }
 
 type TeamResponse struct {
@@ -51,10 +121,20 @@ type TeamResponse struct {
 }
 
 type UserResponse struct {
-	ID       string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	ID         string `json:"user_id"`
+	Username   string `json:"username"`
+	TeamName   string `json:"team_name"`
+	IsActive   bool   `json:"is_active"`
+	OnLeave    bool   `json:"on_leave,omitempty"`
+	Role       string `json:"role"`
+	ChatHandle string `json:"chat_handle,omitempty"`
+}
+
+type NotificationPreferencesResponse struct {
+	UserID            string `json:"user_id"`
+	Email             string `json:"email"`
+	EmailOnAssignment bool   `json:"email_on_assignment"`
+	EmailDigest       bool   `json:"email_digest"`
 }
 
 type PRResponse struct {
@@ -63,12 +143,84 @@ type PRResponse struct {
 	AuthorID          string     `json:"author_id"`
 	Status            string     `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
+	Labels            []string   `json:"labels,omitempty"`
 	CreatedAt         time.Time  `json:"created_at"`
 	MergedAt          *time.Time `json:"merged_at,omitempty"`
 }
 
+type UserReviewsResponse struct {
+	UserID       string       `json:"user_id"`
+	PullRequests []PRResponse `json:"pull_requests"`
+	// NextCursor is omitted on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type PRListResponse struct {
+	PullRequests []PRResponse `json:"pull_requests"`
+	// NextCursor is omitted on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total"`
+}
+
+type AssignmentPreviewResponse struct {
+	AuthorID  string   `json:"author_id"`
+	TeamName  string   `json:"team_name"`
+	Strategy  string   `json:"strategy"`
+	Reviewers []string `json:"assigned_reviewers"`
+	// Candidates are the members reviewers are drawn from.
+	Candidates []string                    `json:"candidates"`
+	Excluded   []ReviewerExclusionResponse `json:"excluded"`
+}
+
+type ReviewerExclusionResponse struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+type AssignmentExplanationResponse struct {
+	PRID      string                       `json:"pull_request_id"`
+	Reviewers []string                     `json:"assigned_reviewers"`
+	Decisions []AssignmentDecisionResponse `json:"decisions"`
+}
+
+type AssignmentDecisionResponse struct {
+	Kind               string                      `json:"kind"`
+	ReplacedReviewerID string                      `json:"replaced_reviewer_id,omitempty"`
+	Strategy           string                      `json:"strategy"`
+	Seed               int64                       `json:"seed"`
+	Candidates         []ScoredCandidateResponse   `json:"candidates"`
+	Excluded           []ReviewerExclusionResponse `json:"excluded"`
+	Reviewers          []string                    `json:"assigned_reviewers"`
+	CreatedAt          time.Time                   `json:"created_at"`
+}
+
+type ScoredCandidateResponse struct {
+	UserID string  `json:"user_id"`
+	Score  float64 `json:"score"`
+}
+
+type TeamSyncResponse struct {
+	Applied     bool                   `json:"applied"`
+	CreateTeams []string               `json:"create_teams"`
+	Add         []TeamSyncUserResponse `json:"add"`
+	Move        []TeamSyncUserResponse `json:"move"`
+	Update      []TeamSyncUserResponse `json:"update"`
+	Deactivate  []TeamSyncUserResponse `json:"deactivate"`
+	Remove      []TeamSyncUserResponse `json:"remove"`
+	DeleteTeams []string               `json:"delete_teams"`
+}
+
+type TeamSyncUserResponse struct {
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	TeamName     string `json:"team_name"`
+	IsActive     bool   `json:"is_active"`
+	FromTeamName string `json:"from_team_name,omitempty"`
+}
+
 type ErrorResponse struct {
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
 }
 
 type HealthResponse struct {
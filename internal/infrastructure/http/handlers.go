@@ -2,53 +2,80 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/mail"
+	"strconv"
 
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
-	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/roster"
 )
 
 type Handler struct {
 	// Commands
 	createTeamCmd       *commands.CreateTeamCommand
+	setTeamMembersCmd   *commands.SetTeamMembersCommand
 	createPRCmd         *commands.CreatePRCommand
 	mergePRCmd          *commands.MergePRCommand
 	reassignReviewerCmd *commands.ReassignReviewerCommand
 	setUserActiveCmd    *commands.SetUserActiveCommand
+	setUserRoleCmd      *commands.SetUserRoleCommand
+	setUserOnLeaveCmd   *commands.SetUserOnLeaveCommand
+	setNotificationsCmd *commands.SetNotificationPreferencesCommand
+	syncTeamsCmd        *commands.SyncTeamsCommand
 
 	// Queries
 	getTeamQuery        *queries.GetTeamQuery
 	getUserReviewsQuery *queries.GetUserReviewsQuery
+	listPRsQuery        *queries.ListPullRequestsQuery
+	previewQuery        *queries.PreviewAssignmentQuery
+	explainQuery        *queries.ExplainAssignmentQuery
 
-	// Repository
-	userRepo ports.UserRepository
-
-	logger *slog.Logger
+	authorizer *authz.Authorizer
+	logger     *slog.Logger
 }
 
 func NewHandler(
 	createTeamCmd *commands.CreateTeamCommand,
+	setTeamMembersCmd *commands.SetTeamMembersCommand,
 	createPRCmd *commands.CreatePRCommand,
 	mergePRCmd *commands.MergePRCommand,
 	reassignReviewerCmd *commands.ReassignReviewerCommand,
 	setUserActiveCmd *commands.SetUserActiveCommand,
+	setUserRoleCmd *commands.SetUserRoleCommand,
+	setUserOnLeaveCmd *commands.SetUserOnLeaveCommand,
+	setNotificationsCmd *commands.SetNotificationPreferencesCommand,
+	syncTeamsCmd *commands.SyncTeamsCommand,
 	getTeamQuery *queries.GetTeamQuery,
 	getUserReviewsQuery *queries.GetUserReviewsQuery,
-	userRepo ports.UserRepository,
+	listPRsQuery *queries.ListPullRequestsQuery,
+	previewQuery *queries.PreviewAssignmentQuery,
+	explainQuery *queries.ExplainAssignmentQuery,
+	authorizer *authz.Authorizer,
 	logger *slog.Logger,
 ) *Handler {
 	return &Handler{
 		createTeamCmd:       createTeamCmd,
+		setTeamMembersCmd:   setTeamMembersCmd,
 		createPRCmd:         createPRCmd,
 		mergePRCmd:          mergePRCmd,
 		reassignReviewerCmd: reassignReviewerCmd,
 		setUserActiveCmd:    setUserActiveCmd,
+		setUserRoleCmd:      setUserRoleCmd,
+		setUserOnLeaveCmd:   setUserOnLeaveCmd,
+		setNotificationsCmd: setNotificationsCmd,
+		syncTeamsCmd:        syncTeamsCmd,
 		getTeamQuery:        getTeamQuery,
 		getUserReviewsQuery: getUserReviewsQuery,
-		userRepo:            userRepo,
+		listPRsQuery:        listPRsQuery,
+		previewQuery:        previewQuery,
+		explainQuery:        explainQuery,
+		authorizer:          authorizer,
 		logger:              logger,
 	}
 }
@@ -58,36 +85,48 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 
 	var req CreateTeamRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("invalid request body", "error", err)
+		h.log(r).Error("invalid request body", "error", err)
 		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
 	if req.TeamName == "" {
-		h.logger.Error("validation error", "error", "team_name is empty")
+		h.log(r).Error("validation error", "error", "team_name is empty")
 		h.respondWithError(w, http.StatusBadRequest, "team_name cannot be empty")
 		return
 	}
 
 	if len(req.Members) == 0 {
-		h.logger.Error("validation error", "error", "members is empty")
+		h.log(r).Error("validation error", "error", "members is empty")
 		h.respondWithError(w, http.StatusBadRequest, "members cannot be empty")
 		return
 	}
 
 	for _, member := range req.Members {
 		if member.UserID == "" || member.Username == "" {
-			h.logger.Error("validation error", "error", "user_id or username is empty")
+			h.log(r).Error("validation error", "error", "user_id or username is empty")
 			h.respondWithError(w, http.StatusBadRequest, "user_id and username cannot be empty")
 			return
 		}
 	}
 
+	if _, err := h.getTeamQuery.Execute(r.Context(), req.TeamName); err == nil {
+		h.setTeamMembers(w, r, req)
+		return
+	} else if !errors.Is(err, entities.ErrTeamNotFound) {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionCreateTeam, req.TeamName) {
+		return
+	}
+
 	members := MapCreateTeamRequestToUsers(req)
 
-	team, err := h.createTeamCmd.Execute(r.Context(), req.TeamName, members)
+	team, err := h.createTeamCmd.Execute(r.Context(), req.TeamName, req.ChatWebhookURL, members)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -95,19 +134,42 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(MapTeamToResponse(team))
 }
 
+// setTeamMembers handles /team/add for a team that already exists: the listed
+// users, who must exist, become its members and the others move to the
+// default team. Only user_id is read from each member.
+func (h *Handler) setTeamMembers(w http.ResponseWriter, r *http.Request, req CreateTeamRequest) {
+	if !h.authorize(w, r, authz.ActionSetTeamMembers, req.TeamName) {
+		return
+	}
+
+	memberIDs := make([]string, 0, len(req.Members))
+	for _, member := range req.Members {
+		memberIDs = append(memberIDs, member.UserID)
+	}
+
+	team, err := h.setTeamMembersCmd.Execute(r.Context(), req.TeamName, memberIDs, false)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapTeamToResponse(team))
+}
+
 func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	teamName := r.URL.Query().Get("team_name")
 	if teamName == "" {
-		h.logger.Error("validation error", "error", "team_name is empty")
+		h.log(r).Error("validation error", "error", "team_name is empty")
 		h.respondWithError(w, http.StatusBadRequest, "team_name is required")
 		return
 	}
 
 	team, err := h.getTeamQuery.Execute(r.Context(), teamName)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -115,25 +177,144 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(MapTeamToResponse(team))
 }
 
+// maxRosterSize bounds the roster document accepted by SyncTeams.
+const maxRosterSize = 1 << 20
+
+// SyncTeams reconciles the teams in a YAML or JSON roster body with the
+// stored ones. The plan is returned and applied only with apply=true.
+func (h *Handler) SyncTeams(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	apply := false
+	if value := r.URL.Query().Get("apply"); value != "" {
+		var err error
+		if apply, err = strconv.ParseBool(value); err != nil {
+			h.log(r).Error("validation error", "error", err)
+			h.respondWithError(w, http.StatusBadRequest, "apply must be true or false")
+			return
+		}
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRosterSize))
+	if err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	teams, err := roster.Parse(data)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionSyncTeams, "") {
+		return
+	}
+
+	plan, err := h.syncTeamsCmd.Execute(r.Context(), teams, apply)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if apply {
+		h.log(r).Info("teams synced", "synced_by", GetUserIDFromContext(r))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapTeamSyncPlanToResponse(plan, apply))
+}
+
 func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req SetUserActiveRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("invalid request body", "error", err)
+		h.log(r).Error("invalid request body", "error", err)
+		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.UserID == "" {
+		h.log(r).Error("validation error", "error", "user_id is empty")
+		h.respondWithError(w, http.StatusBadRequest, "user_id cannot be empty")
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionSetUserActive, req.UserID) {
+		return
+	}
+
+	user, err := h.setUserActiveCmd.Execute(r.Context(), req.UserID, req.IsActive, req.RevokeSessions)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapUserToResponse(user))
+}
+
+func (h *Handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req SetUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.UserID == "" {
+		h.log(r).Error("validation error", "error", "user_id is empty")
+		h.respondWithError(w, http.StatusBadRequest, "user_id cannot be empty")
+		return
+	}
+
+	role, err := entities.ParseRole(req.Role)
+	if err != nil {
+		h.log(r).Error("validation error", "error", err, "role", req.Role)
+		h.respondWithError(w, http.StatusBadRequest, "role must be one of admin, team_lead, member")
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionSetRole, req.UserID) {
+		return
+	}
+
+	user, err := h.setUserRoleCmd.Execute(r.Context(), req.UserID, role)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapUserToResponse(user))
+}
+
+func (h *Handler) SetUserOnLeave(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req SetUserOnLeaveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
 		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
 	if req.UserID == "" {
-		h.logger.Error("validation error", "error", "user_id is empty")
+		h.log(r).Error("validation error", "error", "user_id is empty")
 		h.respondWithError(w, http.StatusBadRequest, "user_id cannot be empty")
 		return
 	}
 
-	user, err := h.setUserActiveCmd.Execute(r.Context(), req.UserID, req.IsActive)
+	if !h.authorize(w, r, authz.ActionSetOnLeave, req.UserID) {
+		return
+	}
+
+	user, err := h.setUserOnLeaveCmd.Execute(r.Context(), req.UserID, req.OnLeave)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -141,25 +322,78 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(MapUserToResponse(user))
 }
 
+func (h *Handler) SetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req SetNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.UserID == "" {
+		h.log(r).Error("validation error", "error", "user_id is empty")
+		h.respondWithError(w, http.StatusBadRequest, "user_id cannot be empty")
+		return
+	}
+
+	if req.Email == "" && (req.EmailOnAssignment || req.EmailDigest) {
+		h.log(r).Error("validation error", "error", "email is empty")
+		h.respondWithError(w, http.StatusBadRequest, "email is required to opt in to email notifications")
+		return
+	}
+
+	if req.Email != "" {
+		if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email {
+			h.log(r).Error("validation error", "error", "invalid email")
+			h.respondWithError(w, http.StatusBadRequest, "email must be a plain address such as user@example.com")
+			return
+		}
+	}
+
+	if !h.authorize(w, r, authz.ActionSetNotifications, req.UserID) {
+		return
+	}
+
+	prefs := entities.NotificationPreferences{
+		EmailOnAssignment: req.EmailOnAssignment,
+		EmailDigest:       req.EmailDigest,
+	}
+
+	user, err := h.setNotificationsCmd.Execute(r.Context(), req.UserID, req.Email, prefs)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapNotificationPreferencesToResponse(user))
+}
+
 func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req CreatePRRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("invalid request body", "error", err)
+		h.log(r).Error("invalid request body", "error", err)
 		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
 	if req.PRID == "" || req.PRName == "" || req.AuthorID == "" {
-		h.logger.Error("validation error", "error", "missing required fields")
+		h.log(r).Error("validation error", "error", "missing required fields")
 		h.respondWithError(w, http.StatusBadRequest, "pull_request_id, name, and author_id are required")
 		return
 	}
 
-	pr, err := h.createPRCmd.Execute(r.Context(), req.PRID, req.PRName, req.AuthorID)
+	if !h.authorize(w, r, authz.ActionCreatePR, req.AuthorID) {
+		return
+	}
+
+	pr, err := h.createPRCmd.Execute(r.Context(), req.PRID, req.PRName, req.AuthorID, req.Labels...)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -167,25 +401,77 @@ func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(MapPRToResponse(pr))
 }
 
+// PreviewPR takes the same body as CreatePR and returns who would be assigned
+// to review it, without creating it.
+func (h *Handler) PreviewPR(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req CreatePRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r).Error("invalid request body", "error", err)
+		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.AuthorID == "" {
+		h.log(r).Error("validation error", "error", "author_id is empty")
+		h.respondWithError(w, http.StatusBadRequest, "author_id is required")
+		return
+	}
+
+	preview, err := h.previewQuery.Execute(r.Context(), req.AuthorID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(MapAssignmentPreviewToResponse(preview))
+}
+
+// ExplainPR returns the recorded decisions behind a pull request's reviewers.
+func (h *Handler) ExplainPR(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		h.log(r).Error("validation error", "error", "pull_request_id is empty")
+		h.respondWithError(w, http.StatusBadRequest, "pull_request_id is required")
+		return
+	}
+
+	explanation, err := h.explainQuery.Execute(r.Context(), prID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapAssignmentExplanationToResponse(explanation))
+}
+
 func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req MergePRRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("invalid request body", "error", err)
+		h.log(r).Error("invalid request body", "error", err)
 		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
 	if req.PRID == "" {
-		h.logger.Error("validation error", "error", "pull_request_id is empty")
+		h.log(r).Error("validation error", "error", "pull_request_id is empty")
 		h.respondWithError(w, http.StatusBadRequest, "pull_request_id is required")
 		return
 	}
 
+	if !h.authorize(w, r, authz.ActionMergePR, req.PRID) {
+		return
+	}
+
 	pr, err := h.mergePRCmd.Execute(r.Context(), req.PRID)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -198,20 +484,24 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 
 	var req ReassignReviewerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("invalid request body", "error", err)
+		h.log(r).Error("invalid request body", "error", err)
 		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
 	if req.PRID == "" || req.OldReviewerID == "" {
-		h.logger.Error("validation error", "error", "missing required fields")
+		h.log(r).Error("validation error", "error", "missing required fields")
 		h.respondWithError(w, http.StatusBadRequest, "pull_request_id and old_reviewer_id are required")
 		return
 	}
 
+	if !h.authorize(w, r, authz.ActionReassignReviewer, req.PRID) {
+		return
+	}
+
 	result, err := h.reassignReviewerCmd.Execute(r.Context(), req.PRID, req.OldReviewerID)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -227,99 +517,154 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
-		h.logger.Error("validation error", "error", "user_id is empty")
+		h.log(r).Error("validation error", "error", "user_id is empty")
 		h.respondWithError(w, http.StatusBadRequest, "user_id is required")
 		return
 	}
 
-	prs, err := h.getUserReviewsQuery.Execute(r.Context(), userID)
+	params, err := parsePRListParams(r.URL.Query())
 	if err != nil {
-		h.handleError(w, err)
+		h.log(r).Error("validation error", "error", err)
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	responses := make([]PRResponse, 0, len(prs))
-	for _, pr := range prs {
+	page, err := h.getUserReviewsQuery.Execute(r.Context(), queries.UserReviewsFilter{
+		UserID:      userID,
+		Status:      params.Status,
+		AuthorID:    params.AuthorID,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		Order:       params.Order,
+		Cursor:      params.Cursor,
+		Limit:       params.Limit,
+	})
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	responses := make([]PRResponse, 0, len(page.PullRequests))
+	for _, pr := range page.PullRequests {
 		responses = append(responses, MapPRToResponse(pr))
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"pull_requests": responses,
+	json.NewEncoder(w).Encode(UserReviewsResponse{
+		UserID:       userID,
+		PullRequests: responses,
+		NextCursor:   page.NextCursor,
 	})
 }
 
-func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) ListPRs(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
-}
-
-func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("invalid request body", "error", err)
-		h.respondWithError(w, http.StatusBadRequest, "Invalid request")
-		return
-	}
 
-	if req.UserID == "" {
-		h.logger.Error("validation error", "error", "user_id is empty")
-		h.respondWithError(w, http.StatusBadRequest, "user_id is required")
+	params, err := parsePRListParams(r.URL.Query())
+	if err != nil {
+		h.log(r).Error("validation error", "error", err)
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	user, err := h.userRepo.GetByID(r.Context(), req.UserID)
+	page, err := h.listPRsQuery.Execute(r.Context(), queries.PRListFilter{
+		TeamName:    params.TeamName,
+		AuthorID:    params.AuthorID,
+		ReviewerID:  params.ReviewerID,
+		Status:      params.Status,
+		Label:       params.Label,
+		Search:      params.Search,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		MinAge:      params.MinAge,
+		MaxAge:      params.MaxAge,
+		Order:       params.Order,
+		Cursor:      params.Cursor,
+		Limit:       params.Limit,
+	})
 	if err != nil {
-		h.handleError(w, err)
-		return
-	}
-	if user == nil {
-		h.respondWithError(w, http.StatusNotFound, "User not found")
+		h.handleError(w, r, err)
 		return
 	}
 
-	token, err := GenerateToken(req.UserID)
-	if err != nil {
-		h.logger.Error("failed to generate token", "error", err)
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
-		return
+	responses := make([]PRResponse, 0, len(page.PullRequests))
+	for _, pr := range page.PullRequests {
+		responses = append(responses, MapPRToResponse(pr))
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(LoginResponse{Token: token})
+	json.NewEncoder(w).Encode(PRListResponse{
+		PullRequests: responses,
+		NextCursor:   page.NextCursor,
+		Total:        page.Total,
+	})
+}
+
+func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
+}
+
+// authorize checks the authenticated caller against the policy and writes the
+// error response itself when the call is denied.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, action authz.Action, resourceID string) bool {
+	err := h.authorizer.Authorize(r.Context(), GetUserIDFromContext(r), action, resourceID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return false
+	}
+	return true
 }
 
 func (h *Handler) respondWithError(w http.ResponseWriter, statusCode int, message string) {
+	respondWithError(w, statusCode, message)
+}
+
+func respondWithError(w http.ResponseWriter, statusCode int, message string) {
+	respondWithErrorCode(w, statusCode, "", message)
+}
+
+func respondWithErrorCode(w http.ResponseWriter, statusCode int, code, message string) {
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message, Code: code})
 }
 
-func (h *Handler) handleError(w http.ResponseWriter, err error) {
-	h.logger.Error("error occurred", "error", err)
-
-	switch err {
-	case entities.ErrTeamExists:
-		h.respondWithError(w, http.StatusConflict, "Team already exists")
-	case entities.ErrTeamNotFound:
-		h.respondWithError(w, http.StatusNotFound, "Team not found")
-	case entities.ErrUserNotFound:
-		h.respondWithError(w, http.StatusNotFound, "User not found")
-	case entities.ErrPRExists:
-		h.respondWithError(w, http.StatusConflict, "Pull request already exists")
-	case entities.ErrPRNotFound:
-		h.respondWithError(w, http.StatusNotFound, "Pull request not found")
-	case entities.ErrPRMerged:
-		h.respondWithError(w, http.StatusConflict, "Pull request is already merged")
-	case entities.ErrReviewerNotAssigned:
-		h.respondWithError(w, http.StatusBadRequest, "Reviewer is not assigned to this pull request")
-	case entities.ErrNoCandidateFound:
-		h.respondWithError(w, http.StatusConflict, "No active reviewer available")
-	case entities.ErrMemberExists:
-		h.respondWithError(w, http.StatusConflict, "Member already exists in team")
+func (h *Handler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	h.log(r).Error("error occurred", "error", err)
+
+	switch {
+	case errors.Is(err, entities.ErrForbidden):
+		respondWithErrorCode(w, http.StatusForbidden, "FORBIDDEN", "Action is not permitted")
+	case errors.Is(err, entities.ErrTeamExists):
+		respondWithErrorCode(w, http.StatusConflict, "TEAM_EXISTS", "Team already exists")
+	case errors.Is(err, entities.ErrTeamNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, "NOT_FOUND", "Team not found")
+	case errors.Is(err, entities.ErrUserNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, "NOT_FOUND", "User not found")
+	case errors.Is(err, entities.ErrPRExists):
+		respondWithErrorCode(w, http.StatusConflict, "PR_EXISTS", "Pull request already exists")
+	case errors.Is(err, entities.ErrPRNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, "NOT_FOUND", "Pull request not found")
+	case errors.Is(err, entities.ErrPRMerged):
+		respondWithErrorCode(w, http.StatusConflict, "PR_MERGED", "Pull request is already merged")
+	case errors.Is(err, entities.ErrReviewerNotAssigned):
+		respondWithErrorCode(w, http.StatusBadRequest, "NOT_ASSIGNED", "Reviewer is not assigned to this pull request")
+	case errors.Is(err, entities.ErrNoCandidateFound):
+		respondWithErrorCode(w, http.StatusConflict, "NO_CANDIDATE", "No active reviewer available")
+	case errors.Is(err, entities.ErrMemberExists):
+		respondWithErrorCode(w, http.StatusConflict, "MEMBER_EXISTS", "Member already exists in team")
+	case errors.Is(err, entities.ErrInvalidRole):
+		respondWithErrorCode(w, http.StatusBadRequest, "INVALID_ROLE", "Invalid role")
+	case errors.Is(err, entities.ErrInvalidCursor):
+		respondWithErrorCode(w, http.StatusBadRequest, "INVALID_CURSOR", "Invalid page cursor")
+	case errors.Is(err, entities.ErrInvalidRoster):
+		respondWithErrorCode(w, http.StatusBadRequest, "INVALID_ROSTER", err.Error())
 	default:
 		h.respondWithError(w, http.StatusInternalServerError, "Internal server error")
 	}
 }
+
+func (h *Handler) log(r *http.Request) *slog.Logger {
+	return requestLogger(r, h.logger)
+}
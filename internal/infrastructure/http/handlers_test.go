@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+)
+
+func TestTeamLeadSetsMembersOfOwnTeam(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clock := services.NewRealClock()
+	store := repositories.NewInMemoryStore()
+
+	admin := entities.NewUser("admin", "root", "ops", true)
+	admin.SetRole(entities.RoleAdmin)
+	lead := entities.NewUser("lead", "lena", "backend", true)
+	lead.SetRole(entities.RoleTeamLead)
+	otherLead := entities.NewUser("other-lead", "oleg", "frontend", true)
+	otherLead.SetRole(entities.RoleTeamLead)
+	teams := map[string][]*entities.User{
+		"ops":        {admin},
+		"backend":    {lead, entities.NewUser("u1", "alice", "backend", true), entities.NewUser("u2", "bob", "backend", true)},
+		"frontend":   {otherLead},
+		"unassigned": {entities.NewUser("u3", "carol", "unassigned", true)},
+	}
+	for name, members := range teams {
+		if err := store.Teams.Save(ctx, entities.NewTeam(name, members)); err != nil {
+			t.Fatalf("failed to save team: %v", err)
+		}
+	}
+
+	tokens := make(map[string]string)
+	for _, id := range []string{"admin", "lead", "other-lead"} {
+		_, raw, err := commands.NewCreateAPITokenCommand(store.APITokens, store.Users, clock).
+			Execute(ctx, id, id, []entities.Scope{entities.ScopeTeamWrite}, 0)
+		if err != nil {
+			t.Fatalf("failed to create api token: %v", err)
+		}
+		tokens[id] = raw
+	}
+
+	syncRepo := repositories.NewInMemoryTeamSyncRepository(store)
+	router := NewRouter(logger, RouterDeps{
+		CreateTeam:     commands.NewCreateTeamCommand(store.Teams, store.Users),
+		SetTeamMembers: commands.NewSetTeamMembersCommand(store.Teams, store.Users, syncRepo, "unassigned", logger),
+		GetTeam:        queries.NewGetTeamQuery(store.Teams),
+		VerifyAPIToken: queries.NewVerifyAPITokenQuery(store.APITokens, store.Users, clock),
+		UserRepo:       store.Users,
+		Denylist:       security.NewDenylist(store.Revocations, store.RefreshTokens, clock, logger),
+		Authorizer:     authz.NewAuthorizer(store.Users, store.PRs),
+	})
+	addTeam := func(actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tokens[actor])
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	backend := `{"team_name":"backend","members":[
+		{"user_id":"lead","username":"lena","is_active":true},
+		{"user_id":"u1","username":"alice","is_active":true},
+		{"user_id":"u3","username":"carol","is_active":true}]}`
+
+	if rec := addTeam("other-lead", backend); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected another team's lead to get 403, got %d", rec.Code)
+	}
+
+	rec := addTeam("lead", backend)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the lead to set members of their team, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp TeamResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	var ids []string
+	for _, m := range resp.Members {
+		ids = append(ids, m.ID)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []string{"lead", "u1", "u3"}) {
+		t.Errorf("expected members [lead u1 u3], got %v", ids)
+	}
+	if dropped, _ := store.Users.GetByID(ctx, "u2"); dropped.TeamName != "unassigned" {
+		t.Errorf("expected u2 to move to the default team, got %q", dropped.TeamName)
+	}
+
+	newTeam := `{"team_name":"mobile","members":[{"user_id":"u4","username":"dave","is_active":true}]}`
+	if rec := addTeam("lead", newTeam); rec.Code != http.StatusForbidden {
+		t.Errorf("expected a lead to get 403 creating a team, got %d", rec.Code)
+	}
+	if rec := addTeam("admin", newTeam); rec.Code != http.StatusCreated {
+		t.Errorf("expected an admin to create a team, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestSetNotificationPreferencesRejectsInvalidEmail(t *testing.T) {
+	h := &Handler{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, email := range []string{"not-an-email", "alice@", "Alice <alice@example.com>", "alice@example.com, bob@example.com"} {
+		body := `{"user_id":"u1","email":` + strconv.Quote(email) + `,"email_on_assignment":true}`
+		req := httptest.NewRequest(http.MethodPost, "/users/setNotificationPreferences", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.SetNotificationPreferences(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for email %q, got %d", email, rec.Code)
+		}
+	}
+}
+
+func TestMetricsRequireStatsReadScope(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clock := services.NewRealClock()
+	store := repositories.NewInMemoryStore()
+	if err := store.Users.Save(ctx, entities.NewUser("prometheus", "prometheus", "ops", true)); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+
+	newToken := func(scope entities.Scope) string {
+		_, raw, err := commands.NewCreateAPITokenCommand(store.APITokens, store.Users, clock).
+			Execute(ctx, "prometheus", string(scope), []entities.Scope{scope}, 0)
+		if err != nil {
+			t.Fatalf("failed to create api token: %v", err)
+		}
+		return raw
+	}
+
+	router := NewRouter(logger, RouterDeps{
+		VerifyAPIToken: queries.NewVerifyAPITokenQuery(store.APITokens, store.Users, clock),
+		UserRepo:       store.Users,
+		Denylist:       security.NewDenylist(store.Revocations, store.RefreshTokens, clock, logger),
+		MetricsHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	})
+
+	tests := []struct {
+		name           string
+		credential     string
+		expectedStatus int
+	}{
+		{"no credentials", "", http.StatusUnauthorized},
+		{"api token without stats:read", newToken(entities.ScopePRRead), http.StatusForbidden},
+		{"api token with stats:read", newToken(entities.ScopeStatsRead), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.credential != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.credential)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
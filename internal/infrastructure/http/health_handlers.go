@@ -0,0 +1,41 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/health"
+)
+
+type HealthHandler struct {
+	readiness *health.Checker
+}
+
+// NewHealthHandler builds the probe handlers. readiness may be nil, in which
+// case /readyz reports ready without running any checks.
+func NewHealthHandler(readiness *health.Checker) *HealthHandler {
+	return &HealthHandler{readiness: readiness}
+}
+
+// Livez reports that the process is up; it never touches dependencies.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(HealthResponse{Status: health.StatusOK})
+}
+
+// Readyz reports whether the instance can serve traffic.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := health.Report{Status: health.StatusOK, Checks: map[string]health.CheckResult{}}
+	if h.readiness != nil {
+		report = h.readiness.Run(r.Context())
+	}
+
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
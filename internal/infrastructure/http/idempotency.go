@@ -0,0 +1,168 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentBodySize     = 1 << 20
+	// idempotencyLockWindow bounds how long a request that never finishes,
+	// for example because the server crashed, holds its key. A slower request
+	// can lose its key to a retry, after which its outcome is not stored.
+	idempotencyLockWindow = time.Minute
+)
+
+// IdempotencyMiddleware replays the stored response when a request is
+// retried with the same Idempotency-Key, so that a timed out call can be
+// repeated safely. Keys are scoped to the caller, so it must run inside
+// AuthMiddleware. Requests without the header, and every request while the
+// store fails, are handled as usual. Server errors are not stored, so the
+// request can be retried.
+func IdempotencyMiddleware(
+	logger *slog.Logger,
+	repo ports.IdempotencyRepository,
+	ttl time.Duration,
+	next http.HandlerFunc,
+) http.HandlerFunc {
+	if repo == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		userID := GetUserIDFromContext(r)
+		if key == "" || userID == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			w.Header().Set("Content-Type", "application/json")
+			respondWithErrorCode(w, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY", "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			w.Header().Set("Content-Type", "application/json")
+			if errors.As(err, &tooLarge) {
+				respondWithErrorCode(w, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE", "Request body is too large")
+				return
+			}
+			respondWithErrorCode(w, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		owner, err := randomID()
+		if err != nil {
+			requestLogger(r, logger).Warn("idempotency store unavailable", "error", err)
+			next(w, r)
+			return
+		}
+		now := time.Now().UTC()
+		record := &entities.IdempotencyRecord{
+			Key:         key,
+			UserID:      userID,
+			Owner:       owner,
+			RequestHash: requestHash(r, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotencyLockWindow),
+		}
+		existing, err := repo.Reserve(r.Context(), record, now)
+		if err != nil {
+			requestLogger(r, logger).Warn("idempotency store unavailable", "error", err)
+			next(w, r)
+			return
+		}
+		if existing != nil {
+			replayIdempotent(w, r, logger, record, existing)
+			return
+		}
+
+		recorder := &responseCapture{ResponseWriter: w}
+		// Store the outcome even if the client gave up, since that is exactly
+		// when it will retry.
+		ctx := context.WithoutCancel(r.Context())
+		defer func() {
+			if recorder.status == 0 || recorder.status >= http.StatusInternalServerError {
+				if err := repo.Release(ctx, record); err != nil {
+					requestLogger(r, logger).Warn("failed to release idempotency key", "error", err)
+				}
+				return
+			}
+			record.Complete(recorder.status, w.Header().Get("Content-Type"), recorder.body.Bytes(), time.Now().UTC().Add(ttl))
+			if err := repo.Complete(ctx, record); err != nil {
+				requestLogger(r, logger).Warn("failed to store idempotent response", "error", err)
+			}
+		}()
+		next(recorder, r)
+	}
+}
+
+func replayIdempotent(w http.ResponseWriter, r *http.Request, logger *slog.Logger, record, existing *entities.IdempotencyRecord) {
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		requestLogger(r, logger).Warn("idempotency key reused with a different request", "key", record.Key)
+		w.Header().Set("Content-Type", "application/json")
+		respondWithErrorCode(w, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used for a different request")
+	case !existing.IsComplete():
+		w.Header().Set("Content-Type", "application/json")
+		respondWithErrorCode(w, http.StatusConflict, "IDEMPOTENCY_KEY_IN_USE", "A request with this Idempotency-Key is still in progress")
+	default:
+		if existing.ContentType != "" {
+			w.Header().Set("Content-Type", existing.ContentType)
+		}
+		w.Header().Set(idempotencyReplayedHeader, "true")
+		w.WriteHeader(existing.StatusCode)
+		w.Write(existing.Body)
+	}
+}
+
+// requestHash fingerprints the method, target and body of a request.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseCapture passes a response through while keeping a copy of it.
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+func (c *responseCapture) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	calls := 0
+	status := http.StatusCreated
+	handler := IdempotencyMiddleware(logger, repositories.NewInMemoryIdempotencyRepository(), time.Hour,
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(`{"call":` + strconv.Itoa(calls) + `,"body":` + string(body) + `}`))
+		})
+
+	send := func(userID, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), userIDCtxKey, userID))
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	first := send("user1", "k1", `{"id":1}`)
+	if first.Code != http.StatusCreated || first.Body.String() != `{"call":1,"body":{"id":1}}` {
+		t.Fatalf("unexpected first response %d %s", first.Code, first.Body.String())
+	}
+
+	replay := send("user1", "k1", `{"id":1}`)
+	if calls != 1 {
+		t.Errorf("expected the replay not to reach the handler, got %d calls", calls)
+	}
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+		t.Errorf("expected the original response, got %d %s", replay.Code, replay.Body.String())
+	}
+	if got := replay.Header().Get(idempotencyReplayedHeader); got != "true" {
+		t.Errorf("expected %s header, got %q", idempotencyReplayedHeader, got)
+	}
+	if got := replay.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected the original content type, got %q", got)
+	}
+
+	if rec := send("user1", "k1", `{"id":2}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for a different payload, got %d", rec.Code)
+	}
+	if rec := send("user2", "k1", `{"id":2}`); rec.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("expected keys to be scoped per user, got %d after %d calls", rec.Code, calls)
+	}
+	if rec := send("user1", "", `{"id":1}`); rec.Code != http.StatusCreated || calls != 3 {
+		t.Errorf("expected requests without a key to pass through, got %d after %d calls", rec.Code, calls)
+	}
+	if rec := send("user1", strings.Repeat("k", 256), `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an overlong key, got %d", rec.Code)
+	}
+
+	// Server errors are not stored, so the request can be retried.
+	status = http.StatusInternalServerError
+	send("user1", "k2", `{}`)
+	status = http.StatusOK
+	if rec := send("user1", "k2", `{}`); rec.Code != http.StatusOK || calls != 5 {
+		t.Errorf("expected a retry after a server error to run, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsConcurrentRetry(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var handler http.HandlerFunc
+	var retry *httptest.ResponseRecorder
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{}`))
+		req = req.WithContext(context.WithValue(req.Context(), userIDCtxKey, "user1"))
+		req.Header.Set(idempotencyKeyHeader, "k1")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	handler = IdempotencyMiddleware(logger, repositories.NewInMemoryIdempotencyRepository(), time.Hour,
+		func(w http.ResponseWriter, r *http.Request) {
+			// The retry arrives while the first request is still running.
+			if retry == nil {
+				retry = send()
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+
+	if rec := send(); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if retry.Code != http.StatusConflict {
+		t.Errorf("expected 409 while the first request is in progress, got %d", retry.Code)
+	}
+}
+
+func TestIdempotencyMiddlewareKeepsRecordOfRetryThatTookOver(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := repositories.NewInMemoryIdempotencyRepository()
+	later := time.Now().UTC().Add(2 * idempotencyLockWindow)
+	retry := &entities.IdempotencyRecord{Key: "k1", UserID: "user1", Owner: "retry", RequestHash: "h", CreatedAt: later, ExpiresAt: later.Add(idempotencyLockWindow)}
+
+	handler := IdempotencyMiddleware(logger, repo, time.Hour, func(w http.ResponseWriter, r *http.Request) {
+		// The lock window runs out and a retry takes the key over before
+		// this request finishes.
+		if existing, err := repo.Reserve(r.Context(), retry, later); err != nil || existing != nil {
+			t.Fatalf("expected the retry to take the key over, got %+v (%v)", existing, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{}`))
+	req = req.WithContext(context.WithValue(req.Context(), userIDCtxKey, "user1"))
+	req.Header.Set(idempotencyKeyHeader, "k1")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	probe := &entities.IdempotencyRecord{Key: "k1", UserID: "user1", Owner: "probe", CreatedAt: later, ExpiresAt: later}
+	existing, err := repo.Reserve(context.Background(), probe, later)
+	if err != nil {
+		t.Fatalf("failed to reserve: %v", err)
+	}
+	if existing == nil || existing.Owner != "retry" || existing.IsComplete() {
+		t.Errorf("expected the retry's pending reservation to be kept, got %+v", existing)
+	}
+}
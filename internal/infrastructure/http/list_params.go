@@ -0,0 +1,112 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// prListParams holds the filter, sort and paging parameters of the pull
+// request listing endpoints.
+type prListParams struct {
+	Status      entities.PRStatus
+	AuthorID    string
+	TeamName    string
+	ReviewerID  string
+	Label       string
+	Search      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MinAge      time.Duration
+	MaxAge      time.Duration
+	Order       ports.SortOrder
+	Cursor      string
+	Limit       int
+}
+
+// parsePRListParams reads status, author_id, team_name, reviewer_id, label,
+// q, created_from, created_to, min_age, max_age, sort, cursor and limit. Dates
+// are RFC 3339 timestamps or plain YYYY-MM-DD days; ages are Go durations or
+// a whole number of days such as 7d.
+func parsePRListParams(values url.Values) (prListParams, error) {
+	params := prListParams{
+		AuthorID:   values.Get("author_id"),
+		TeamName:   values.Get("team_name"),
+		ReviewerID: values.Get("reviewer_id"),
+		Label:      strings.TrimSpace(values.Get("label")),
+		Search:     strings.TrimSpace(values.Get("q")),
+		Cursor:     values.Get("cursor"),
+	}
+
+	if status := values.Get("status"); status != "" {
+		parsed, err := entities.ParsePRStatus(status)
+		if err != nil {
+			return params, fmt.Errorf("status must be OPEN or MERGED")
+		}
+		params.Status = parsed
+	}
+
+	var err error
+	if params.CreatedFrom, err = parseTimeParam(values, "created_from"); err != nil {
+		return params, err
+	}
+	if params.CreatedTo, err = parseTimeParam(values, "created_to"); err != nil {
+		return params, err
+	}
+	if params.MinAge, err = parseAgeParam(values, "min_age"); err != nil {
+		return params, err
+	}
+	if params.MaxAge, err = parseAgeParam(values, "max_age"); err != nil {
+		return params, err
+	}
+
+	switch sort := values.Get("sort"); sort {
+	case "":
+	case string(ports.SortAscending), string(ports.SortDescending):
+		params.Order = ports.SortOrder(sort)
+	default:
+		return params, fmt.Errorf("sort must be asc or desc")
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		params.Limit, err = strconv.Atoi(limit)
+		if err != nil || params.Limit < 1 {
+			return params, fmt.Errorf("limit must be a positive integer")
+		}
+	}
+
+	return params, nil
+}
+
+func parseTimeParam(values url.Values, name string) (*time.Time, error) {
+	value := values.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+}
+
+func parseAgeParam(values url.Values, name string) (time.Duration, error) {
+	value := values.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("%s must be a positive duration such as 36h or 7d", name)
+}
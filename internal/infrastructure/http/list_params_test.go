@@ -0,0 +1,73 @@
+package http
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+func TestParsePRListParams(t *testing.T) {
+	values := url.Values{
+		"status":       {"MERGED"},
+		"author_id":    {"u1"},
+		"team_name":    {"backend"},
+		"reviewer_id":  {"u2"},
+		"label":        {" bug "},
+		"q":            {"login page"},
+		"created_from": {"2026-01-02"},
+		"created_to":   {"2026-01-03T12:00:00Z"},
+		"min_age":      {"36h"},
+		"max_age":      {"7d"},
+		"sort":         {"asc"},
+		"cursor":       {"abc"},
+		"limit":        {"5"},
+	}
+
+	params, err := parsePRListParams(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Status != entities.PRStatusMerged || params.AuthorID != "u1" || params.Order != ports.SortAscending ||
+		params.Cursor != "abc" || params.Limit != 5 {
+		t.Errorf("unexpected params: %+v", params)
+	}
+	if params.TeamName != "backend" || params.ReviewerID != "u2" || params.Label != "bug" || params.Search != "login page" {
+		t.Errorf("unexpected filters: %+v", params)
+	}
+	if params.MinAge != 36*time.Hour || params.MaxAge != 7*24*time.Hour {
+		t.Errorf("unexpected ages: min %v max %v", params.MinAge, params.MaxAge)
+	}
+	if params.CreatedFrom == nil || !params.CreatedFrom.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected created_from: %v", params.CreatedFrom)
+	}
+	if params.CreatedTo == nil || !params.CreatedTo.Equal(time.Date(2026, 1, 3, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected created_to: %v", params.CreatedTo)
+	}
+}
+
+func TestParsePRListParamsRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+	}{
+		{"status", url.Values{"status": {"closed"}}},
+		{"date", url.Values{"created_from": {"yesterday"}}},
+		{"sort", url.Values{"sort": {"up"}}},
+		{"age", url.Values{"min_age": {"week"}}},
+		{"negative age", url.Values{"max_age": {"-1h"}}},
+		{"zero days", url.Values{"max_age": {"0d"}}},
+		{"zero limit", url.Values{"limit": {"0"}}},
+		{"non-numeric limit", url.Values{"limit": {"ten"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePRListParams(tt.values); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
@@ -17,10 +18,38 @@ func MapTeamToResponse(team *entities.Team) TeamResponse {
 
 func MapUserToResponse(user *entities.User) UserResponse {
 	return UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		TeamName: user.TeamName,
-		IsActive: user.IsActive,
+		ID:         user.ID,
+		Username:   user.Username,
+		TeamName:   user.TeamName,
+		IsActive:   user.IsActive,
+		OnLeave:    user.OnLeave,
+		Role:       user.Role.String(),
+		ChatHandle: user.ChatHandle,
+	}
+}
+
+func MapNotificationPreferencesToResponse(user *entities.User) NotificationPreferencesResponse {
+	return NotificationPreferencesResponse{
+		UserID:            user.ID,
+		Email:             user.Email,
+		EmailOnAssignment: user.Notifications.EmailOnAssignment,
+		EmailDigest:       user.Notifications.EmailDigest,
+	}
+}
+
+func MapAPITokenToResponse(token *entities.APIToken) APITokenResponse {
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, scope.String())
+	}
+	return APITokenResponse{
+		ID:        token.ID,
+		Name:      token.Name,
+		UserID:    token.UserID,
+		Scopes:    scopes,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		RevokedAt: token.RevokedAt,
 	}
 }
 
@@ -35,6 +64,7 @@ func MapPRToResponse(pr *entities.PullRequest) PRResponse {
 		AuthorID:          pr.AuthorID,
 		Status:            pr.Status.String(),
 		AssignedReviewers: reviewers,
+		Labels:            pr.Labels,
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
 	}
@@ -44,7 +74,92 @@ func MapCreateTeamRequestToUsers(req CreateTeamRequest) []*entities.User {
 	users := make([]*entities.User, 0, len(req.Members))
 	for _, member := range req.Members {
 		user := entities.NewUser(member.UserID, member.Username, req.TeamName, member.IsActive)
+		user.ChatHandle = member.ChatHandle
 		users = append(users, user)
 	}
 	return users
 }
+
+func MapTeamSyncPlanToResponse(plan *entities.TeamSyncPlan, applied bool) TeamSyncResponse {
+	resp := TeamSyncResponse{
+		Applied:     applied,
+		CreateTeams: append([]string{}, plan.CreateTeams...),
+		Add:         mapTeamSyncUsers(plan.Add),
+		Move:        make([]TeamSyncUserResponse, 0, len(plan.Move)),
+		Update:      mapTeamSyncUsers(plan.Update),
+		Deactivate:  mapTeamSyncUsers(plan.Deactivate),
+		Remove:      mapTeamSyncUsers(plan.Remove),
+		DeleteTeams: append([]string{}, plan.DeleteTeams...),
+	}
+	for _, move := range plan.Move {
+		user := mapTeamSyncUser(move.User)
+		user.FromTeamName = move.FromTeam
+		resp.Move = append(resp.Move, user)
+	}
+	return resp
+}
+
+func MapAssignmentPreviewToResponse(preview *queries.AssignmentPreview) AssignmentPreviewResponse {
+	candidates := make([]string, 0, len(preview.Decision.Candidates))
+	for _, candidate := range preview.Decision.Candidates {
+		candidates = append(candidates, candidate.UserID)
+	}
+	return AssignmentPreviewResponse{
+		AuthorID:   preview.AuthorID,
+		TeamName:   preview.TeamName,
+		Strategy:   preview.Decision.Strategy,
+		Reviewers:  append([]string{}, preview.Decision.Reviewers...),
+		Candidates: candidates,
+		Excluded:   mapReviewerExclusions(preview.Decision.Excluded),
+	}
+}
+
+func MapAssignmentExplanationToResponse(explanation *queries.AssignmentExplanation) AssignmentExplanationResponse {
+	decisions := make([]AssignmentDecisionResponse, 0, len(explanation.Decisions))
+	for _, decision := range explanation.Decisions {
+		candidates := make([]ScoredCandidateResponse, 0, len(decision.Candidates))
+		for _, candidate := range decision.Candidates {
+			candidates = append(candidates, ScoredCandidateResponse{UserID: candidate.UserID, Score: candidate.Score})
+		}
+		decisions = append(decisions, AssignmentDecisionResponse{
+			Kind:               string(decision.Kind),
+			ReplacedReviewerID: decision.ReplacedReviewerID,
+			Strategy:           decision.Strategy,
+			Seed:               decision.Seed,
+			Candidates:         candidates,
+			Excluded:           mapReviewerExclusions(decision.Excluded),
+			Reviewers:          append([]string{}, decision.Reviewers...),
+			CreatedAt:          decision.CreatedAt,
+		})
+	}
+	return AssignmentExplanationResponse{
+		PRID:      explanation.PR.ID,
+		Reviewers: append([]string{}, explanation.PR.AssignedReviewers...),
+		Decisions: decisions,
+	}
+}
+
+func mapReviewerExclusions(exclusions []entities.ReviewerExclusion) []ReviewerExclusionResponse {
+	responses := make([]ReviewerExclusionResponse, 0, len(exclusions))
+	for _, exclusion := range exclusions {
+		responses = append(responses, ReviewerExclusionResponse{UserID: exclusion.UserID, Reason: string(exclusion.Reason)})
+	}
+	return responses
+}
+
+func mapTeamSyncUsers(users []*entities.User) []TeamSyncUserResponse {
+	responses := make([]TeamSyncUserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, mapTeamSyncUser(user))
+	}
+	return responses
+}
+
+func mapTeamSyncUser(user *entities.User) TeamSyncUserResponse {
+	return TeamSyncUserResponse{
+		UserID:   user.ID,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		IsActive: user.IsActive,
+	}
+}
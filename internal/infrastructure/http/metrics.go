@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestObserver records per-route request metrics.
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// MetricsMiddleware reports every request served by a route registered under
+// pattern. Routes are labelled by their path so label cardinality stays
+// bounded.
+func MetricsMiddleware(observer RequestObserver, pattern string, next http.Handler) http.Handler {
+	if observer == nil {
+		return next
+	}
+
+	route := pattern
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		route = path
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		observer.ObserveRequest(r.Method, route, status, time.Since(start))
+	})
+}
@@ -6,39 +6,21 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
 const (
-	jwtSecret    = "your-secret-key-change-in-production"
-	userIDCtxKey = "userID"
+	userIDCtxKey    = "userID"
+	principalCtxKey = "principal"
 )
 
-type Claims struct {
-	UserID string `json:"user_id"`
-	jwt.RegisteredClaims
-}
-
-func GenerateToken(userID string) (string, error) {
-	claims := Claims{
-		UserID:           userID,
-		RegisteredClaims: jwt.RegisteredClaims{},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
-}
-
-func AuthMiddleware(logger *slog.Logger, next http.HandlerFunc) http.HandlerFunc {
+// AuthMiddleware authenticates the bearer credential and rejects callers whose
+// token lacks the scope required by the route.
+func AuthMiddleware(logger *slog.Logger, authenticator *Authenticator, scope entities.Scope, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			logger.Error("missing authorization header")
+			requestLogger(r, logger).Error("missing authorization header")
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(`{"error":"Missing authorization header"}`))
@@ -47,7 +29,7 @@ func AuthMiddleware(logger *slog.Logger, next http.HandlerFunc) http.HandlerFunc
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			logger.Error("invalid authorization header format")
+			requestLogger(r, logger).Error("invalid authorization header format")
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(`{"error":"Invalid authorization header format"}`))
@@ -56,20 +38,24 @@ func AuthMiddleware(logger *slog.Logger, next http.HandlerFunc) http.HandlerFunc
 
 		tokenString := parts[1]
 
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			logger.Error("invalid or expired token", "error", err)
+		principal, err := authenticator.Authenticate(r.Context(), tokenString)
+		if err != nil {
+			requestLogger(r, logger).Error("invalid or expired token", "error", err)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(`{"error":"Invalid or expired token"}`))
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), userIDCtxKey, claims.UserID)
+		if !principal.HasScope(scope) {
+			requestLogger(r, logger).Error("token lacks required scope", "user_id", principal.UserID, "api_token_id", principal.APITokenID, "scope", scope)
+			w.Header().Set("Content-Type", "application/json")
+			respondWithErrorCode(w, http.StatusForbidden, "INSUFFICIENT_SCOPE", "Token lacks required scope "+scope.String())
+			return
+		}
+
+		ctx := context.WithValue(setRequestUser(r, logger, principal.UserID), userIDCtxKey, principal.UserID)
+		ctx = context.WithValue(ctx, principalCtxKey, principal)
 		next(w, r.WithContext(ctx))
 	}
 }
@@ -81,3 +67,8 @@ func GetUserIDFromContext(r *http.Request) string {
 	}
 	return userID
 }
+
+func GetPrincipalFromContext(r *http.Request) *Principal {
+	principal, _ := r.Context().Value(principalCtxKey).(*Principal)
+	return principal
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+	"github.com/KKittyCatik/redesigned-umbrella/migrations"
+)
+
+func TestAuthMiddlewareEnforcesScopes(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clock := services.NewRealClock()
+
+	userRepo := repositories.NewInMemoryUserRepository()
+	for _, u := range []*entities.User{
+		entities.NewUser("ci-bot", "ci", "bots", true),
+		entities.NewUser("old-bot", "old", "bots", false),
+		entities.NewUser("user1", "alice", "backend", true),
+	} {
+		if err := userRepo.Save(ctx, u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+
+	apiTokenRepo := repositories.NewInMemoryAPITokenRepository()
+	createCmd := commands.NewCreateAPITokenCommand(apiTokenRepo, userRepo, clock)
+	revokeCmd := commands.NewRevokeAPITokenCommand(apiTokenRepo, clock)
+
+	newToken := func(userID string, ttl time.Duration) (*entities.APIToken, string) {
+		token, raw, err := createCmd.Execute(ctx, userID, "ci", []entities.Scope{entities.ScopePRWrite}, ttl)
+		if err != nil {
+			t.Fatalf("failed to create api token: %v", err)
+		}
+		return token, raw
+	}
+
+	_, scoped := newToken("ci-bot", 0)
+	revokedToken, revoked := newToken("ci-bot", 0)
+	if _, err := revokeCmd.Execute(ctx, revokedToken.ID); err != nil {
+		t.Fatalf("failed to revoke api token: %v", err)
+	}
+	_, expired := newToken("ci-bot", time.Nanosecond)
+	_, inactive := newToken("old-bot", 0)
+
+	tokens := newTestTokenManager(t, TokenConfig{})
+	jwt, err := tokens.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("failed to generate jwt: %v", err)
+	}
+
+	authenticator := NewAuthenticator(tokens, nil, queries.NewVerifyAPITokenQuery(apiTokenRepo, userRepo, clock), nil)
+
+	tests := []struct {
+		name           string
+		credential     string
+		scope          entities.Scope
+		expectedStatus int
+		expectedUser   string
+	}{
+		{"jwt is not limited by scopes", jwt, entities.ScopeTeamWrite, http.StatusOK, "user1"},
+		{"api token with scope", scoped, entities.ScopePRWrite, http.StatusOK, "ci-bot"},
+		{"api token without scope", scoped, entities.ScopeTeamWrite, http.StatusForbidden, ""},
+		{"revoked api token", revoked, entities.ScopePRWrite, http.StatusUnauthorized, ""},
+		{"expired api token", expired, entities.ScopePRWrite, http.StatusUnauthorized, ""},
+		{"api token of inactive account", inactive, entities.ScopePRWrite, http.StatusUnauthorized, ""},
+		{"unknown api token", entities.APITokenPrefix + "nope", entities.ScopePRWrite, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser string
+			next := func(w http.ResponseWriter, r *http.Request) {
+				gotUser = GetUserIDFromContext(r)
+				w.WriteHeader(http.StatusOK)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.credential)
+			rec := httptest.NewRecorder()
+			AuthMiddleware(logger, authenticator, tt.scope, next)(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotUser != tt.expectedUser {
+				t.Errorf("expected user %q, got %q", tt.expectedUser, gotUser)
+			}
+		})
+	}
+}
+
+// TestDeprovisionedUserTokenStaysRevoked runs on SQLite so that deleting the
+// user goes through the real schema, then reloads the denylist the way
+// another instance would.
+func TestDeprovisionedUserTokenStaysRevoked(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clock := services.NewRealClock()
+
+	db, err := config.NewSQLiteConnection(&config.DBConfig{SQLitePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrationRepo, err := repositories.NewSQLiteMigrationRepository(db, migrations.SQLiteFS)
+	if err != nil {
+		t.Fatalf("failed to create migration repository: %v", err)
+	}
+	latest, _ := migrationRepo.GetLatestVersion(ctx)
+	if err := migrationRepo.MigrateTo(ctx, latest); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	teamRepo := repositories.NewSQLiteTeamRepository(db)
+	userRepo := repositories.NewSQLiteUserRepository(db)
+	revocationRepo := repositories.NewSQLiteTokenRevocationRepository(db)
+	refreshTokenRepo := repositories.NewSQLiteRefreshTokenRepository(db)
+	if err := teamRepo.Save(ctx, entities.NewTeam("backend", []*entities.User{
+		entities.NewUser("user1", "alice", "backend", true),
+	})); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	tokens := newTestTokenManager(t, TokenConfig{})
+	jwt, err := tokens.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("failed to generate jwt: %v", err)
+	}
+
+	denylist := security.NewDenylist(revocationRepo, refreshTokenRepo, clock, logger)
+	deprovision := commands.NewDeprovisionUserCommand(userRepo, repositories.NewSQLiteTeamSyncRepository(db), denylist, logger)
+	if err := deprovision.Execute(ctx, "user1"); err != nil {
+		t.Fatalf("failed to deprovision user: %v", err)
+	}
+
+	request := func(denylist *security.Denylist) int {
+		if err := denylist.Reload(ctx); err != nil {
+			t.Fatalf("failed to reload denylist: %v", err)
+		}
+		authenticator := NewAuthenticator(tokens, nil, nil, denylist)
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview", nil)
+		req.Header.Set("Authorization", "Bearer "+jwt)
+		rec := httptest.NewRecorder()
+		AuthMiddleware(logger, authenticator, entities.ScopePRRead, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})(rec, req)
+		return rec.Code
+	}
+
+	if code := request(security.NewDenylist(revocationRepo, refreshTokenRepo, clock, logger)); code != http.StatusUnauthorized {
+		t.Errorf("expected the old token to be rejected after a reload, got %d", code)
+	}
+
+	if err := teamRepo.Save(ctx, entities.NewTeam("backend", []*entities.User{
+		entities.NewUser("user1", "alice", "backend", true),
+	})); err != nil {
+		t.Fatalf("failed to provision user again: %v", err)
+	}
+	if code := request(denylist); code != http.StatusUnauthorized {
+		t.Errorf("expected the old token to be rejected for a re-provisioned user, got %d", code)
+	}
+}
@@ -0,0 +1,318 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minJWKSRefreshInterval bounds how often an unknown key id can force a
+// refetch of the key set, so forged tokens cannot hammer the identity provider.
+// A failed fetch is not retried sooner either, so requests are not held up by
+// an unreachable provider.
+const minJWKSRefreshInterval = 30 * time.Second
+
+type OIDCConfig struct {
+	JWKSURL  string
+	Issuer   string
+	Audience string
+	// UserIDClaim names the claim that holds the internal user ID. Defaults
+	// to "sub".
+	UserIDClaim string
+	// CacheTTL is how long a fetched key set is used before it is refetched.
+	CacheTTL   time.Duration
+	HTTPClient *http.Client
+}
+
+// OIDCVerifier validates RS256/ES256 tokens issued by an external identity
+// provider against its published JWKS.
+type OIDCVerifier struct {
+	jwksURL     string
+	issuer      string
+	audience    string
+	userIDClaim string
+	cacheTTL    time.Duration
+	client      *http.Client
+	now         func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	// failedAt and fetchErr describe the last fetch if it failed.
+	failedAt time.Time
+	fetchErr error
+	// fetching is set while a key set is being fetched, so that concurrent
+	// callers wait for that fetch instead of starting their own.
+	fetching *jwksFetch
+}
+
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+func NewOIDCVerifier(cfg OIDCConfig) (*OIDCVerifier, error) {
+	if cfg.JWKSURL == "" {
+		return nil, errors.New("oidc jwks url is required")
+	}
+	if cfg.Issuer == "" {
+		return nil, errors.New("oidc issuer is required")
+	}
+	if cfg.Audience == "" {
+		return nil, errors.New("oidc audience is required")
+	}
+
+	userIDClaim := cfg.UserIDClaim
+	if userIDClaim == "" {
+		userIDClaim = "sub"
+	}
+	cacheTTL := cfg.CacheTTL
+	if cacheTTL <= 0 {
+		cacheTTL = time.Hour
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &OIDCVerifier{
+		jwksURL:     cfg.JWKSURL,
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		userIDClaim: userIDClaim,
+		cacheTTL:    cacheTTL,
+		client:      client,
+		now:         time.Now,
+	}, nil
+}
+
+// Verify checks the token signature, issuer, audience and expiry. The
+// principal's user ID is taken from the configured claim.
+func (v *OIDCVerifier) Verify(ctx context.Context, tokenString string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return v.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithAudience(v.audience),
+		jwt.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := claims[v.userIDClaim].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("token has no %q claim", v.userIDClaim)
+	}
+
+	principal := &Principal{UserID: userID}
+	principal.TokenID, _ = claims["jti"].(string)
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		principal.IssuedAt = iat.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		principal.ExpiresAt = exp.Time
+	}
+	return principal, nil
+}
+
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	now := v.now()
+	stale := now.Sub(v.fetchedAt) >= v.cacheTTL
+	backoff := now.Sub(v.failedAt) < minJWKSRefreshInterval
+	fetchErr := v.fetchErr
+	key, ok := v.lookup(kid)
+	v.mu.Unlock()
+
+	if backoff && !ok {
+		return nil, fetchErr
+	}
+	if !backoff && (stale || (!ok && now.Sub(v.fetchedAt) >= minJWKSRefreshInterval)) {
+		if err := v.refresh(ctx); err != nil {
+			// Keep serving a known key while the provider is unreachable.
+			if ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		v.mu.Lock()
+		key, ok = v.lookup(kid)
+		v.mu.Unlock()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+func (v *OIDCVerifier) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the key set without holding v.mu, so a slow provider only
+// delays the requests that need new keys.
+func (v *OIDCVerifier) refresh(ctx context.Context) error {
+	v.mu.Lock()
+	if fetch := v.fetching; fetch != nil {
+		v.mu.Unlock()
+		select {
+		case <-fetch.done:
+			return fetch.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	fetch := &jwksFetch{done: make(chan struct{})}
+	v.fetching = fetch
+	v.mu.Unlock()
+
+	keys, err := v.fetch(ctx)
+
+	v.mu.Lock()
+	if err == nil {
+		v.keys = keys
+		v.fetchedAt = v.now()
+		v.failedAt, v.fetchErr = time.Time{}, nil
+	} else if ctx.Err() == nil {
+		// A caller giving up is not a provider failure.
+		v.failedAt, v.fetchErr = v.now(), err
+	}
+	v.fetching = nil
+	v.mu.Unlock()
+
+	fetch.err = err
+	close(fetch.done)
+	return err
+}
+
+func (v *OIDCVerifier) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build jwks request: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// A key that does not parse only fails the tokens signed with it.
+		key, err := jwk.publicKey()
+		if err == nil && key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey returns nil for key types the verifier does not use.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y coordinate: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid point: %w", err)
+		}
+		return key, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
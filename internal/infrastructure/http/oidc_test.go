@@ -0,0 +1,355 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer publishes the public halves of its keys and counts fetches.
+// While down is set it answers every fetch with 503.
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    []map[string]string
+	fetches atomic.Int32
+	down    atomic.Bool
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		if s.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) publish(kid string, key crypto.PublicKey) {
+	b64 := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+
+	var jwk map[string]string
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk = map[string]string{"kty": "RSA", "n": b64(k.N), "e": b64(big.NewInt(int64(k.E)))}
+	case *ecdsa.PublicKey:
+		jwk = map[string]string{"kty": "EC", "crv": "P-256", "x": b64(k.X), "y": b64(k.Y)}
+	}
+	jwk["kid"] = kid
+	jwk["use"] = "sig"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, jwk)
+}
+
+func signIDPToken(t *testing.T, method jwt.SigningMethod, kid string, key crypto.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func idpClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   "https://idp.example.com",
+		"aud":   "pr-reviewer",
+		"sub":   "idp-subject-1",
+		"email": "user1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ec key: %v", err)
+	}
+	strangerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+
+	server := newJWKSServer(t)
+	server.publish("rsa-1", &rsaKey.PublicKey)
+	server.publish("ec-1", &ecKey.PublicKey)
+
+	verifier, err := NewOIDCVerifier(OIDCConfig{
+		JWKSURL:     server.URL,
+		Issuer:      "https://idp.example.com",
+		Audience:    "pr-reviewer",
+		UserIDClaim: "email",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		token        string
+		expectedUser string
+	}{
+		{"rs256", signIDPToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, idpClaims(nil)), "user1"},
+		{"es256", signIDPToken(t, jwt.SigningMethodES256, "ec-1", ecKey, idpClaims(nil)), "user1"},
+		{"wrong issuer", signIDPToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, idpClaims(jwt.MapClaims{"iss": "https://evil.example.com"})), ""},
+		{"wrong audience", signIDPToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, idpClaims(jwt.MapClaims{"aud": "other-service"})), ""},
+		{"expired", signIDPToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, idpClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})), ""},
+		{"missing user claim", signIDPToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, idpClaims(jwt.MapClaims{"email": nil})), ""},
+		{"signed by unknown key", signIDPToken(t, jwt.SigningMethodRS256, "rsa-1", strangerKey, idpClaims(nil)), ""},
+		{"rs384 is not accepted", signIDPToken(t, jwt.SigningMethodRS384, "rsa-1", rsaKey, idpClaims(nil)), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token)
+			if tt.expectedUser == "" {
+				if err == nil {
+					t.Fatalf("expected verification to fail, got user %q", principal.UserID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if principal.UserID != tt.expectedUser {
+				t.Errorf("expected user %q, got %q", tt.expectedUser, principal.UserID)
+			}
+		})
+	}
+
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("expected key set to be fetched once and cached, got %d fetches", got)
+	}
+}
+
+func TestOIDCVerifierRefetchesOnKeyRotation(t *testing.T) {
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	server := newJWKSServer(t)
+	server.publish("old", &oldKey.PublicKey)
+
+	verifier, err := NewOIDCVerifier(OIDCConfig{
+		JWKSURL:  server.URL,
+		Issuer:   "https://idp.example.com",
+		Audience: "pr-reviewer",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if _, err := verifier.Verify(ctx, signIDPToken(t, jwt.SigningMethodRS256, "old", oldKey, idpClaims(nil))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.publish("new", &newKey.PublicKey)
+	rotated := signIDPToken(t, jwt.SigningMethodRS256, "new", newKey, idpClaims(nil))
+
+	if _, err := verifier.Verify(ctx, rotated); err == nil {
+		t.Fatal("expected unknown key to be rejected within the refresh interval")
+	}
+
+	now = now.Add(minJWKSRefreshInterval)
+	principal, err := verifier.Verify(ctx, rotated)
+	if err != nil {
+		t.Fatalf("expected rotated key to be picked up, got %v", err)
+	}
+	if principal.UserID != "idp-subject-1" {
+		t.Errorf("expected sub claim as user id, got %q", principal.UserID)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("expected 2 fetches, got %d", got)
+	}
+}
+
+func TestOIDCVerifierFetchesWithoutBlockingKnownKeys(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	b64 := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+	keys := []map[string]string{
+		{"kty": "RSA", "kid": "broken", "use": "sig", "n": "!!", "e": "AQAB"},
+		{"kty": "RSA", "kid": "good", "use": "sig", "n": b64(key.N), "e": b64(big.NewInt(int64(key.E)))},
+	}
+
+	var fetches atomic.Int32
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			close(fetching)
+			<-release
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	t.Cleanup(server.Close)
+
+	verifier, err := NewOIDCVerifier(OIDCConfig{
+		JWKSURL:  server.URL,
+		Issuer:   "https://idp.example.com",
+		Audience: "pr-reviewer",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now()
+	verifier.now = func() time.Time { return start }
+
+	ctx := context.Background()
+	good := signIDPToken(t, jwt.SigningMethodRS256, "good", key, idpClaims(nil))
+	if _, err := verifier.Verify(ctx, good); err != nil {
+		t.Fatalf("expected a broken key not to reject the whole set, got %v", err)
+	}
+
+	verifier.now = func() time.Time { return start.Add(minJWKSRefreshInterval) }
+	unknown := make(chan error, 1)
+	go func() {
+		_, err := verifier.Verify(ctx, signIDPToken(t, jwt.SigningMethodRS256, "other", key, idpClaims(nil)))
+		unknown <- err
+	}()
+	<-fetching
+
+	verified := make(chan error, 1)
+	go func() {
+		_, err := verifier.Verify(ctx, good)
+		verified <- err
+	}()
+	select {
+	case err := <-verified:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("expected a known key to verify while the key set is being fetched")
+	}
+
+	close(release)
+	if err := <-unknown; err == nil {
+		t.Error("expected an unknown key to be rejected")
+	}
+}
+
+func TestOIDCVerifierBacksOffWhileProviderIsDown(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	server := newJWKSServer(t)
+	server.publish("current", &key.PublicKey)
+
+	verifier, err := NewOIDCVerifier(OIDCConfig{
+		JWKSURL:  server.URL,
+		Issuer:   "https://idp.example.com",
+		Audience: "pr-reviewer",
+		CacheTTL: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	token := signIDPToken(t, jwt.SigningMethodRS256, "current", key, idpClaims(nil))
+	if _, err := verifier.Verify(ctx, token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.down.Store(true)
+	now = now.Add(time.Minute)
+	for range 3 {
+		if _, err := verifier.Verify(ctx, token); err != nil {
+			t.Fatalf("expected the cached key to be served while the provider is down, got %v", err)
+		}
+	}
+	if _, err := verifier.Verify(ctx, signIDPToken(t, jwt.SigningMethodRS256, "other", key, idpClaims(nil))); err == nil {
+		t.Error("expected an unknown key to be rejected while the provider is down")
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Fatalf("expected one failed fetch after the cache went stale, got %d fetches", got-1)
+	}
+
+	server.down.Store(false)
+	now = now.Add(minJWKSRefreshInterval - time.Second)
+	if _, err := verifier.Verify(ctx, token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("expected no fetch within the backoff, got %d fetches", got)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := verifier.Verify(ctx, token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := server.fetches.Load(); got != 3 {
+		t.Errorf("expected a fetch once the backoff has passed, got %d fetches", got)
+	}
+}
+
+func TestAuthenticatorRoutesIdentityProviderTokens(t *testing.T) {
+	idpKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := newJWKSServer(t)
+	server.publish("rsa-1", &idpKey.PublicKey)
+
+	verifier, err := NewOIDCVerifier(OIDCConfig{
+		JWKSURL:  server.URL,
+		Issuer:   "https://idp.example.com",
+		Audience: "pr-reviewer",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tokens := newTestTokenManager(t, TokenConfig{})
+	authenticator := NewAuthenticator(tokens, verifier, nil, nil)
+
+	local, err := tokens.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	external := signIDPToken(t, jwt.SigningMethodRS256, "rsa-1", idpKey, idpClaims(nil))
+
+	for credential, want := range map[string]string{local: "user1", external: "idp-subject-1"} {
+		principal, err := authenticator.Authenticate(context.Background(), credential)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if principal.UserID != want {
+			t.Errorf("expected user %q, got %q", want, principal.UserID)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/ratelimit"
+)
+
+// RateLimitMiddleware limits requests to a route per caller. Authenticated
+// callers are keyed by user ID, so it must run inside AuthMiddleware; other
+// requests are keyed by client IP. When the limiter fails the request is let
+// through.
+func RateLimitMiddleware(
+	logger *slog.Logger,
+	limiter ratelimit.Limiter,
+	route string,
+	limit ratelimit.Limit,
+	trustProxy bool,
+	next http.HandlerFunc,
+) http.HandlerFunc {
+	if limiter == nil || limit.Unlimited() {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := "ip:" + clientIP(r, trustProxy)
+		if userID := GetUserIDFromContext(r); userID != "" {
+			key = "user:" + userID
+		}
+		key += "|" + route
+
+		result, err := limiter.Allow(r.Context(), key, limit)
+		if err != nil {
+			requestLogger(r, logger).Warn("rate limiter unavailable", "route", route, "error", err)
+			next(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		w.Header().Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(ceilSeconds(limit.Window)))
+
+		if !result.Allowed {
+			requestLogger(r, logger).Warn("rate limit exceeded", "key", key, "limit", limit.String())
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			respondWithErrorCode(w, http.StatusTooManyRequests, "RATE_LIMITED", "Too many requests")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// clientIP returns the address of the caller. X-Forwarded-For is only
+// honoured behind a trusted proxy, and only its last entry, which that proxy
+// appended: the entries before it are whatever the client sent.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			entries := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/ratelimit"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+	handler := RateLimitMiddleware(logger, ratelimit.NewMemoryLimiter(), "POST /pullRequest/create", limit, false,
+		func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	send := func(userID, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+		req.RemoteAddr = remoteAddr
+		if userID != "" {
+			req = req.WithContext(context.WithValue(req.Context(), userIDCtxKey, userID))
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	// The same user is limited regardless of the address they call from.
+	send("user1", "10.0.0.1:1000")
+	rec := send("user1", "10.0.0.2:1000")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("expected RateLimit-Remaining 0, got %q", got)
+	}
+	if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+		t.Errorf("expected RateLimit-Limit 2, got %q", got)
+	}
+
+	rec = send("user1", "10.0.0.3:1000")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("expected Retry-After 30, got %q", got)
+	}
+	if got := rec.Header().Get("RateLimit-Reset"); got != "60" {
+		t.Errorf("expected RateLimit-Reset 60, got %q", got)
+	}
+
+	// Anonymous callers are keyed by IP, separately from users.
+	if rec := send("", "10.0.0.1:2000"); rec.Code != http.StatusOK {
+		t.Errorf("expected anonymous caller to have its own bucket, got %d", rec.Code)
+	}
+	send("", "10.0.0.1:3000")
+	if rec := send("", "10.0.0.1:4000"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected anonymous caller to be limited by ip, got %d", rec.Code)
+	}
+}
+
+func TestClientIPUsesAddressAddedByProxy(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Add("X-Forwarded-For", "1.2.3.4, 5.6.7.8")
+	req.Header.Add("X-Forwarded-For", "203.0.113.7")
+
+	if got := clientIP(req, true); got != "203.0.113.7" {
+		t.Errorf("expected the proxy-added address, got %q", got)
+	}
+	if got := clientIP(req, false); got != "10.0.0.1" {
+		t.Errorf("expected the connection address without a trusted proxy, got %q", got)
+	}
+}
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/logging"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+
+	requestIDCtxKey   = "requestID"
+	requestInfoCtxKey = "requestInfo"
+)
+
+// Middleware wraps a handler with cross-cutting behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middlewares so that the first one listed runs first.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// requestInfo collects details about a request discovered by inner
+// middlewares, such as the authenticated user, for the access log.
+type requestInfo struct {
+	userID string
+}
+
+// TracingMiddleware starts a server span for every request, continuing the
+// trace from an incoming traceparent header. The span is named after the
+// method until RouteSpanMiddleware names it after the matched route.
+func TracingMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return otelhttp.NewHandler(next, "http.server",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method
+			}),
+		)
+	}
+}
+
+// RouteSpanMiddleware names the server span after the route pattern the
+// request matched, such as "GET /scim/v2/Users/{id}", so that span names do
+// not contain IDs. It must run inside the ServeMux, which sets the pattern.
+func RouteSpanMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Pattern != "" {
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(r.Pattern)
+				if _, route, ok := strings.Cut(r.Pattern, " "); ok {
+					span.SetAttributes(attribute.String("http.route", route))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestIDMiddleware propagates the caller's X-Request-ID, or generates one,
+// and stores a logger annotated with it in the request context.
+func RequestIDMiddleware(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(requestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(requestIDHeader, requestID)
+
+			scoped := logger.With("request_id", requestID)
+			if span := trace.SpanFromContext(r.Context()); span.SpanContext().HasTraceID() {
+				span.SetAttributes(attribute.String("http.request_id", requestID))
+				scoped = scoped.With("trace_id", span.SpanContext().TraceID().String())
+			}
+
+			ctx := context.WithValue(r.Context(), requestIDCtxKey, requestID)
+			ctx = logging.WithLogger(ctx, scoped)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AccessLogMiddleware logs every request once it has been served.
+func AccessLogMiddleware(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			info := &requestInfo{}
+			recorder := &statusRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), requestInfoCtxKey, info)))
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logging.FromContext(r.Context(), logger).Log(r.Context(), level, "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", recorder.bytes,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"user_id", info.userID,
+				"remote_addr", r.RemoteAddr,
+			)
+		})
+	}
+}
+
+// RecoveryMiddleware turns a panic into a 500 response with the usual error
+// envelope instead of dropping the connection.
+func RecoveryMiddleware(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(recovered)
+				}
+
+				logging.FromContext(r.Context(), logger).Error("panic while handling request",
+					"panic", recovered,
+					"stack", string(debug.Stack()),
+				)
+				if recorder.status == 0 {
+					w.Header().Set("Content-Type", "application/json")
+					respondWithErrorCode(w, http.StatusInternalServerError, "INTERNAL", "Internal server error")
+				}
+			}()
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// GetRequestIDFromContext returns the ID assigned by RequestIDMiddleware.
+func GetRequestIDFromContext(r *http.Request) string {
+	requestID, _ := r.Context().Value(requestIDCtxKey).(string)
+	return requestID
+}
+
+// setRequestUser records the authenticated user for the access log and
+// returns a context whose logger carries the user ID.
+func setRequestUser(r *http.Request, logger *slog.Logger, userID string) context.Context {
+	if info, ok := r.Context().Value(requestInfoCtxKey).(*requestInfo); ok {
+		info.userID = userID
+	}
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("enduser.id", userID))
+	return logging.WithLogger(r.Context(), requestLogger(r, logger).With("user_id", userID))
+}
+
+// requestLogger returns the request-scoped logger, or fallback outside the
+// middleware chain.
+func requestLogger(r *http.Request, fallback *slog.Logger) *slog.Logger {
+	return logging.FromContext(r.Context(), fallback)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder remembers the status code and body size written by the
+// wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	tokens := newTestTokenManager(t, TokenConfig{})
+	authenticator := NewAuthenticator(tokens, nil, nil, nil)
+	token, err := tokens.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ok", AuthMiddleware(logger, authenticator, "", func(w http.ResponseWriter, r *http.Request) {
+		requestLogger(r, logger).Info("handler ran")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	handler := Chain(mux, RequestIDMiddleware(logger), AccessLogMiddleware(logger), RecoveryMiddleware(logger))
+
+	t.Run("propagates request id and logs the user", func(t *testing.T) {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(requestIDHeader, "req-123")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d", rec.Code)
+		}
+		if got := rec.Header().Get(requestIDHeader); got != "req-123" {
+			t.Errorf("expected request id to be echoed, got %q", got)
+		}
+
+		entries := decodeLogs(t, &logs)
+		if len(entries) != 2 {
+			t.Fatalf("expected handler and access log entries, got %d", len(entries))
+		}
+		for _, entry := range entries {
+			if entry["request_id"] != "req-123" || entry["user_id"] != "user1" {
+				t.Errorf("expected request id and user on every entry, got %v", entry)
+			}
+		}
+		access := entries[1]
+		if access["msg"] != "http request" || access["status"] != float64(http.StatusNoContent) {
+			t.Errorf("unexpected access log entry: %v", access)
+		}
+	})
+
+	t.Run("generates a request id and recovers from panics", func(t *testing.T) {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		req.Header.Set(requestIDHeader, strings.Repeat("x", maxRequestIDLength+1))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rec.Code)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code != "INTERNAL" {
+			t.Errorf("expected JSON error envelope, got %q", rec.Body.String())
+		}
+		requestID := rec.Header().Get(requestIDHeader)
+		if len(requestID) != 32 {
+			t.Errorf("expected a generated request id, got %q", requestID)
+		}
+
+		entries := decodeLogs(t, &logs)
+		if len(entries) != 2 || entries[0]["panic"] != "boom" || entries[1]["status"] != float64(http.StatusInternalServerError) {
+			t.Errorf("expected panic and access log entries, got %v", entries)
+		}
+	})
+}
+
+func decodeLogs(t *testing.T, logs *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
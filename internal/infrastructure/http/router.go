@@ -3,50 +3,211 @@ package http
 import (
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/health"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/ratelimit"
 )
 
+// TokenDenylist both records and checks revoked tokens.
+type TokenDenylist interface {
+	ports.SessionRevoker
+	RevocationChecker
+}
+
 type RouterDeps struct {
 	CreateTeam       *commands.CreateTeamCommand
 	CreatePR         *commands.CreatePRCommand
 	MergePR          *commands.MergePRCommand
 	ReassignReviewer *commands.ReassignReviewerCommand
 	SetUserActive    *commands.SetUserActiveCommand
+	SetUserRole      *commands.SetUserRoleCommand
+	SetUserOnLeave   *commands.SetUserOnLeaveCommand
+	SetNotifications *commands.SetNotificationPreferencesCommand
+	SyncTeams        *commands.SyncTeamsCommand
+	ProvisionUser    *commands.ProvisionUserCommand
+	UpdateProfile    *commands.UpdateUserProfileCommand
+	DeprovisionUser  *commands.DeprovisionUserCommand
+	SetTeamMembers   *commands.SetTeamMembersCommand
+	DeleteTeam       *commands.DeleteTeamCommand
+	Authenticate     *commands.AuthenticateCommand
+	SetPassword      *commands.SetPasswordCommand
+	RevokeSessions   *commands.RevokeUserSessionsCommand
+	CreateAPIToken   *commands.CreateAPITokenCommand
+	RevokeAPIToken   *commands.RevokeAPITokenCommand
 	GetTeam          *queries.GetTeamQuery
 	GetUserReviews   *queries.GetUserReviewsQuery
+	ListPRs          *queries.ListPullRequestsQuery
+	PreviewPR        *queries.PreviewAssignmentQuery
+	ExplainPR        *queries.ExplainAssignmentQuery
+	GetUser          *queries.GetUserQuery
+	ListUsers        *queries.ListUsersQuery
+	ListTeams        *queries.ListTeamsQuery
+	ListAPITokens    *queries.ListAPITokensQuery
+	VerifyAPIToken   *queries.VerifyAPITokenQuery
 	UserRepo         ports.UserRepository
+	RefreshTokenRepo ports.RefreshTokenRepository
+	Tokens           *TokenManager
+	Denylist         TokenDenylist
+	OIDC             *OIDCVerifier
+	Authorizer       *authz.Authorizer
+	// RateLimiter may be nil to disable rate limiting.
+	RateLimiter ratelimit.Limiter
+	RateLimits  ratelimit.Policy
+	// Idempotency may be nil to ignore Idempotency-Key headers.
+	Idempotency    ports.IdempotencyRepository
+	IdempotencyTTL time.Duration
+	// TrustProxy keys anonymous callers by X-Forwarded-For instead of the
+	// connection address.
+	TrustProxy bool
+	// Readiness runs the /readyz checks; nil reports ready unconditionally.
+	Readiness *health.Checker
+	// Metrics and MetricsHandler may be nil to disable /metrics.
+	Metrics        RequestObserver
+	MetricsHandler http.Handler
 }
 
 func NewRouter(logger *slog.Logger, deps RouterDeps) http.Handler {
 	handler := NewHandler(
 		deps.CreateTeam,
+		deps.SetTeamMembers,
 		deps.CreatePR,
 		deps.MergePR,
 		deps.ReassignReviewer,
 		deps.SetUserActive,
+		deps.SetUserRole,
+		deps.SetUserOnLeave,
+		deps.SetNotifications,
+		deps.SyncTeams,
 		deps.GetTeam,
 		deps.GetUserReviews,
+		deps.ListPRs,
+		deps.PreviewPR,
+		deps.ExplainPR,
+		deps.Authorizer,
+		logger,
+	)
+	authHandler := NewAuthHandler(
+		deps.Authenticate,
+		deps.SetPassword,
+		deps.RevokeSessions,
 		deps.UserRepo,
+		deps.RefreshTokenRepo,
+		deps.Denylist,
+		deps.Tokens,
+		deps.Authorizer,
+		logger,
+	)
+
+	apiTokenHandler := NewAPITokenHandler(
+		deps.CreateAPIToken,
+		deps.RevokeAPIToken,
+		deps.ListAPITokens,
+		deps.Authorizer,
+		logger,
+	)
+
+	scimHandler := NewSCIMHandler(
+		deps.ProvisionUser,
+		deps.UpdateProfile,
+		deps.SetUserActive,
+		deps.DeprovisionUser,
+		deps.SetTeamMembers,
+		deps.DeleteTeam,
+		deps.GetUser,
+		deps.ListUsers,
+		deps.GetTeam,
+		deps.ListTeams,
+		deps.Authorizer,
 		logger,
 	)
 
+	healthHandler := NewHealthHandler(deps.Readiness)
+
+	authenticator := NewAuthenticator(deps.Tokens, deps.OIDC, deps.VerifyAPIToken, deps.Denylist)
+
+	limit := func(route string, next http.HandlerFunc) http.HandlerFunc {
+		return RateLimitMiddleware(logger, deps.RateLimiter, route, deps.RateLimits.For(route), deps.TrustProxy, next)
+	}
+
 	mux := http.NewServeMux()
+	handle := func(route string, next http.HandlerFunc) {
+		mux.Handle(route, Chain(MetricsMiddleware(deps.Metrics, route, next), RouteSpanMiddleware()))
+	}
+	public := func(route string, next http.HandlerFunc) {
+		handle(route, limit(route, next))
+	}
+	// Responses are stored for replays, so routes that return secrets opt out.
+	notReplayed := map[string]bool{"POST /admin/apiTokens/create": true}
+	idempotent := func(route string, next http.HandlerFunc) http.HandlerFunc {
+		if strings.HasPrefix(route, http.MethodGet+" ") || notReplayed[route] {
+			return next
+		}
+		return IdempotencyMiddleware(logger, deps.Idempotency, deps.IdempotencyTTL, next)
+	}
+	protected := func(route string, scope entities.Scope, next http.HandlerFunc) {
+		handle(route, AuthMiddleware(logger, authenticator, scope, limit(route, idempotent(route, next))))
+	}
 
 	// Public endpoints
-	mux.HandleFunc("POST /login", handler.Login)
-	mux.HandleFunc("GET /health", handler.Health)
-
-	// Protected endpoints
-	mux.HandleFunc("POST /team/add", AuthMiddleware(logger, handler.CreateTeam))
-	mux.HandleFunc("GET /team/get", AuthMiddleware(logger, handler.GetTeam))
-	mux.HandleFunc("POST /users/setIsActive", AuthMiddleware(logger, handler.SetUserActive))
-	mux.HandleFunc("POST /pullRequest/create", AuthMiddleware(logger, handler.CreatePR))
-	mux.HandleFunc("POST /pullRequest/merge", AuthMiddleware(logger, handler.MergePR))
-	mux.HandleFunc("POST /pullRequest/reassign", AuthMiddleware(logger, handler.ReassignReviewer))
-	mux.HandleFunc("GET /users/getReview", AuthMiddleware(logger, handler.GetUserReviews))
-
-	return mux
+	public("POST /login", authHandler.Login)
+	public("POST /token/refresh", authHandler.RefreshToken)
+	handle("GET /health", handler.Health)
+	handle("GET /livez", healthHandler.Livez)
+	handle("GET /readyz", healthHandler.Readyz)
+
+	// Protected endpoints; API tokens additionally need the listed scope
+	if deps.MetricsHandler != nil {
+		// Scrapes are neither rate limited nor counted in the request metrics.
+		mux.Handle("GET /metrics", AuthMiddleware(logger, authenticator, entities.ScopeStatsRead, deps.MetricsHandler.ServeHTTP))
+	}
+	protected("POST /logout", "", authHandler.Logout)
+	protected("POST /team/add", entities.ScopeTeamWrite, handler.CreateTeam)
+	protected("GET /team/get", entities.ScopeTeamRead, handler.GetTeam)
+	protected("POST /users/setIsActive", entities.ScopeUserWrite, handler.SetUserActive)
+	protected("POST /users/setRole", entities.ScopeUserWrite, handler.SetUserRole)
+	protected("POST /users/setOnLeave", entities.ScopeUserWrite, handler.SetUserOnLeave)
+	protected("POST /users/setPassword", entities.ScopeUserWrite, authHandler.SetPassword)
+	protected("POST /users/setNotificationPreferences", entities.ScopeUserWrite, handler.SetNotificationPreferences)
+	protected("POST /pullRequest/create", entities.ScopePRWrite, handler.CreatePR)
+	protected("POST /pullRequest/preview", entities.ScopePRRead, handler.PreviewPR)
+	protected("POST /pullRequest/merge", entities.ScopePRWrite, handler.MergePR)
+	protected("POST /pullRequest/reassign", entities.ScopePRWrite, handler.ReassignReviewer)
+	protected("GET /pullRequest/list", entities.ScopePRRead, handler.ListPRs)
+	protected("GET /pullRequest/explain", entities.ScopePRRead, handler.ExplainPR)
+	protected("GET /users/getReview", entities.ScopePRRead, handler.GetUserReviews)
+
+	// Admin endpoints
+	protected("POST /admin/apiTokens/create", entities.ScopeAdmin, apiTokenHandler.Create)
+	protected("GET /admin/apiTokens/list", entities.ScopeAdmin, apiTokenHandler.List)
+	protected("POST /admin/apiTokens/revoke", entities.ScopeAdmin, apiTokenHandler.Revoke)
+	protected("POST /admin/users/revokeSessions", entities.ScopeAdmin, authHandler.RevokeSessions)
+	protected("POST /admin/teams/sync", entities.ScopeAdmin, handler.SyncTeams)
+
+	// SCIM 2.0 provisioning for the identity provider
+	protected("GET /scim/v2/Users", entities.ScopeSCIM, scimHandler.ListUsers)
+	protected("POST /scim/v2/Users", entities.ScopeSCIM, scimHandler.CreateUser)
+	protected("GET /scim/v2/Users/{id}", entities.ScopeSCIM, scimHandler.GetUser)
+	protected("PUT /scim/v2/Users/{id}", entities.ScopeSCIM, scimHandler.ReplaceUser)
+	protected("PATCH /scim/v2/Users/{id}", entities.ScopeSCIM, scimHandler.PatchUser)
+	protected("DELETE /scim/v2/Users/{id}", entities.ScopeSCIM, scimHandler.DeleteUser)
+	protected("GET /scim/v2/Groups", entities.ScopeSCIM, scimHandler.ListGroups)
+	protected("POST /scim/v2/Groups", entities.ScopeSCIM, scimHandler.CreateGroup)
+	protected("GET /scim/v2/Groups/{id}", entities.ScopeSCIM, scimHandler.GetGroup)
+	protected("PUT /scim/v2/Groups/{id}", entities.ScopeSCIM, scimHandler.ReplaceGroup)
+	protected("PATCH /scim/v2/Groups/{id}", entities.ScopeSCIM, scimHandler.PatchGroup)
+	protected("DELETE /scim/v2/Groups/{id}", entities.ScopeSCIM, scimHandler.DeleteGroup)
+
+	return Chain(mux,
+		TracingMiddleware(),
+		RequestIDMiddleware(logger),
+		AccessLogMiddleware(logger),
+		RecoveryMiddleware(logger),
+	)
 }
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const (
+	scimUserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimGroupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimPatchOpSchema      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimErrorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimContentType        = "application/scim+json"
+)
+
+// scimError is reported in the RFC 7644 error format.
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string {
+	return e.detail
+}
+
+type SCIMErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+type SCIMMeta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMReference points from a user to a group or from a group to a member.
+type SCIMReference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMUser is both the request and the response body for users. Attributes
+// the service does not store, such as name, are accepted and ignored.
+type SCIMUser struct {
+	Schemas  []string        `json:"schemas"`
+	ID       string          `json:"id,omitempty"`
+	UserName string          `json:"userName"`
+	Active   *bool           `json:"active,omitempty"`
+	Emails   []SCIMEmail     `json:"emails,omitempty"`
+	Groups   []SCIMReference `json:"groups,omitempty"`
+	Meta     *SCIMMeta       `json:"meta,omitempty"`
+}
+
+// SCIMGroup is both the request and the response body for groups, which are
+// teams named by displayName.
+type SCIMGroup struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Members     []SCIMReference `json:"members,omitempty"`
+	Meta        *SCIMMeta       `json:"meta,omitempty"`
+}
+
+type SCIMListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// primaryEmail returns the primary address, or the first one when none is
+// marked primary.
+func (u *SCIMUser) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+func MapUserToSCIM(user *entities.User) SCIMUser {
+	active := user.IsActive
+	resp := SCIMUser{
+		Schemas:  []string{scimUserSchema},
+		ID:       user.ID,
+		UserName: user.Username,
+		Active:   &active,
+		Groups:   []SCIMReference{{Value: user.TeamName, Display: user.TeamName}},
+		Meta:     &SCIMMeta{ResourceType: "User", Location: "/scim/v2/Users/" + user.ID},
+	}
+	if user.Email != "" {
+		resp.Emails = []SCIMEmail{{Value: user.Email, Type: "work", Primary: true}}
+	}
+	return resp
+}
+
+// MapTeamToSCIM leaves out the members when withMembers is false, which
+// identity providers request with excludedAttributes=members.
+func MapTeamToSCIM(team *entities.Team, withMembers bool) SCIMGroup {
+	resp := SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          team.Name,
+		DisplayName: team.Name,
+		Meta:        &SCIMMeta{ResourceType: "Group", Location: "/scim/v2/Groups/" + team.Name},
+	}
+	if withMembers {
+		for _, member := range team.Members {
+			resp.Members = append(resp.Members, SCIMReference{Value: member.ID, Display: member.Username})
+		}
+	}
+	return resp
+}
+
+func scimUserAttributes(user *entities.User) scimAttributes {
+	attrs := scimAttributes{
+		"id":       {values: []string{user.ID}, caseExact: true},
+		"username": {values: []string{user.Username}},
+		"active":   {values: []string{strconv.FormatBool(user.IsActive)}},
+	}
+	if user.Email != "" {
+		attrs["emails.value"] = scimAttribute{values: []string{user.Email}}
+		attrs["emails"] = attrs["emails.value"]
+	}
+	return attrs
+}
+
+var scimUserFilterAttributes = []string{"id", "userName", "active", "emails", "emails.value"}
+
+func scimGroupAttributes(team *entities.Team) scimAttributes {
+	members := make([]string, 0, len(team.Members))
+	for _, member := range team.Members {
+		members = append(members, member.ID)
+	}
+	return scimAttributes{
+		"id":            {values: []string{team.Name}, caseExact: true},
+		"displayname":   {values: []string{team.Name}},
+		"members.value": {values: members, caseExact: true},
+		"members":       {values: members, caseExact: true},
+	}
+}
+
+var scimGroupFilterAttributes = []string{"id", "displayName", "members", "members.value"}
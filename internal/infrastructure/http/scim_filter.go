@@ -0,0 +1,315 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// scimAttribute holds the values a resource exposes to filters under one
+// attribute path.
+type scimAttribute struct {
+	values    []string
+	caseExact bool
+}
+
+// scimAttributes maps lower-cased attribute paths to their values.
+type scimAttributes map[string]scimAttribute
+
+type scimFilter interface {
+	matches(attrs scimAttributes) bool
+}
+
+type scimAndFilter struct{ left, right scimFilter }
+
+func (f scimAndFilter) matches(attrs scimAttributes) bool {
+	return f.left.matches(attrs) && f.right.matches(attrs)
+}
+
+type scimOrFilter struct{ left, right scimFilter }
+
+func (f scimOrFilter) matches(attrs scimAttributes) bool {
+	return f.left.matches(attrs) || f.right.matches(attrs)
+}
+
+type scimNotFilter struct{ inner scimFilter }
+
+func (f scimNotFilter) matches(attrs scimAttributes) bool {
+	return !f.inner.matches(attrs)
+}
+
+type scimCompareFilter struct {
+	path  string
+	op    string
+	value string
+	// null is set for comparisons against the JSON null literal.
+	null bool
+}
+
+func (f scimCompareFilter) matches(attrs scimAttributes) bool {
+	attr := attrs[f.path]
+	present := len(attr.values) > 0
+	switch {
+	case f.op == "pr":
+		return present
+	case f.null && f.op == "eq":
+		return !present
+	case f.null && f.op == "ne":
+		return present
+	case f.op == "ne":
+		return !scimCompareFilter{path: f.path, op: "eq", value: f.value}.matches(attrs)
+	}
+
+	for _, v := range attr.values {
+		want := f.value
+		if !attr.caseExact {
+			v, want = strings.ToLower(v), strings.ToLower(want)
+		}
+		var ok bool
+		switch f.op {
+		case "eq":
+			ok = v == want
+		case "co":
+			ok = strings.Contains(v, want)
+		case "sw":
+			ok = strings.HasPrefix(v, want)
+		case "ew":
+			ok = strings.HasSuffix(v, want)
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+var scimFilterOperators = map[string]bool{"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "pr": true}
+
+// parseSCIMFilter parses the subset of the RFC 7644 filter grammar identity
+// providers send: eq, ne, co, sw, ew and pr comparisons joined with and, or
+// and not, with parentheses for grouping. Attribute names are matched
+// case-insensitively against supported; other attributes are rejected.
+func parseSCIMFilter(filter string, supported []string) (scimFilter, error) {
+	tokens, err := tokenizeSCIMFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &scimFilterParser{tokens: tokens, supported: supported}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, invalidSCIMFilter("unexpected %q", p.tokens[p.pos].text)
+	}
+	return f, nil
+}
+
+type scimToken struct {
+	text string
+	// quoted is set for string literals, whose text is already unquoted.
+	quoted bool
+}
+
+func tokenizeSCIMFilter(filter string) ([]scimToken, error) {
+	var tokens []scimToken
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, scimToken{text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(filter) && filter[end] != '"' {
+				if filter[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(filter) {
+				return nil, invalidSCIMFilter("unterminated string")
+			}
+			var value string
+			if err := json.Unmarshal([]byte(filter[i:end+1]), &value); err != nil {
+				return nil, invalidSCIMFilter("invalid string %s", filter[i:end+1])
+			}
+			tokens = append(tokens, scimToken{text: value, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(filter) && !unicode.IsSpace(rune(filter[end])) && !strings.ContainsRune(`()"`, rune(filter[end])) {
+				end++
+			}
+			tokens = append(tokens, scimToken{text: filter[i:end]})
+			i = end
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, invalidSCIMFilter("filter is empty")
+	}
+	return tokens, nil
+}
+
+type scimFilterParser struct {
+	tokens    []scimToken
+	pos       int
+	supported []string
+}
+
+func (p *scimFilterParser) peekKeyword(keyword string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return false
+	}
+	return strings.EqualFold(p.tokens[p.pos].text, keyword)
+}
+
+func (p *scimFilterParser) next() (scimToken, error) {
+	if p.pos >= len(p.tokens) {
+		return scimToken{}, invalidSCIMFilter("filter ends unexpectedly")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *scimFilterParser) expect(text string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.quoted || t.text != text {
+		return invalidSCIMFilter("expected %q, got %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *scimFilterParser) parseOr() (scimFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = scimOrFilter{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseAnd() (scimFilter, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = scimAndFilter{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseNot() (scimFilter, error) {
+	if !p.peekKeyword("not") {
+		return p.parseAtom()
+	}
+	p.pos++
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	inner, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return scimNotFilter{inner: inner}, nil
+}
+
+func (p *scimFilterParser) parseAtom() (scimFilter, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if !t.quoted && t.text == "(" {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	path, err := p.attributePath(t)
+	if err != nil {
+		return nil, err
+	}
+	opToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(opToken.text)
+	if opToken.quoted || !scimFilterOperators[op] {
+		return nil, invalidSCIMFilter("unsupported operator %q", opToken.text)
+	}
+	if op == "pr" {
+		return scimCompareFilter{path: path, op: op}, nil
+	}
+
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	f := scimCompareFilter{path: path, op: op, value: value.text}
+	if !value.quoted {
+		switch strings.ToLower(value.text) {
+		case "true", "false":
+			f.value = strings.ToLower(value.text)
+		case "null":
+			if op != "eq" && op != "ne" {
+				return nil, invalidSCIMFilter("null can only be compared with eq or ne")
+			}
+			f.null = true
+		default:
+			if value.text == "(" || value.text == ")" {
+				return nil, invalidSCIMFilter("missing value after %s", opToken.text)
+			}
+		}
+	}
+	return f, nil
+}
+
+func (p *scimFilterParser) attributePath(t scimToken) (string, error) {
+	if t.quoted || t.text == ")" {
+		return "", invalidSCIMFilter("expected an attribute, got %q", t.text)
+	}
+	// Attributes may be qualified with their schema URN.
+	path := t.text
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		path = path[i+1:]
+	}
+	path = strings.ToLower(path)
+	for _, s := range p.supported {
+		if strings.ToLower(s) == path {
+			return path, nil
+		}
+	}
+	return "", invalidSCIMFilter("filtering on %s is not supported", t.text)
+}
+
+func invalidSCIMFilter(format string, args ...any) *scimError {
+	return &scimError{status: 400, scimType: "invalidFilter", detail: fmt.Sprintf(format, args...)}
+}
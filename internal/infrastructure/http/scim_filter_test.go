@@ -0,0 +1,69 @@
+package http
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSCIMFilter(t *testing.T) {
+	alice := scimAttributes{
+		"id":           {values: []string{"u1"}, caseExact: true},
+		"username":     {values: []string{"Alice@example.com"}},
+		"active":       {values: []string{"true"}},
+		"emails.value": {values: []string{"alice@example.com", "a@home.example"}},
+	}
+	supported := []string{"id", "userName", "active", "emails.value", "externalId"}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`userName eq "alice@example.com"`, true},
+		{`USERNAME Eq "ALICE@EXAMPLE.COM"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice@example.com"`, true},
+		{`id eq "U1"`, false},
+		{`userName ne "bob"`, true},
+		{`userName sw "alice" and userName ew ".com"`, true},
+		{`emails.value co "home"`, true},
+		{`active eq false or userName eq "bob"`, false},
+		{`active eq true and (id eq "u2" or id eq "u1")`, true},
+		{`not (active eq true)`, false},
+		{`externalId pr`, false},
+		{`externalId eq null`, true},
+		{`userName eq "say \"hi\""`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := parseSCIMFilter(tt.filter, supported)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := filter.matches(alice); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseSCIMFilterRejectsInvalidFilters(t *testing.T) {
+	for _, filter := range []string{
+		``,
+		`title eq "x"`,
+		`userName gt "a"`,
+		`userName eq`,
+		`userName eq "open`,
+		`(userName eq "a"`,
+		`userName eq "a" or`,
+		`userName co null`,
+		`userName eq "a" "b"`,
+	} {
+		t.Run(filter, func(t *testing.T) {
+			_, err := parseSCIMFilter(filter, []string{"userName"})
+			var scimErr *scimError
+			if !errors.As(err, &scimErr) || scimErr.scimType != "invalidFilter" {
+				t.Errorf("expected an invalidFilter error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,444 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const (
+	maxSCIMBodySize      = 1 << 20
+	defaultSCIMPageCount = 100
+	maxSCIMPageCount     = 1000
+)
+
+// SCIMHandler serves the SCIM 2.0 Users and Groups endpoints identity
+// providers use to provision accounts. Users keep their service ID as the SCIM
+// id; groups are teams and use the team name as id and displayName.
+type SCIMHandler struct {
+	provisionUserCmd   *commands.ProvisionUserCommand
+	updateProfileCmd   *commands.UpdateUserProfileCommand
+	setUserActiveCmd   *commands.SetUserActiveCommand
+	deprovisionUserCmd *commands.DeprovisionUserCommand
+	setTeamMembersCmd  *commands.SetTeamMembersCommand
+	deleteTeamCmd      *commands.DeleteTeamCommand
+	getUserQuery       *queries.GetUserQuery
+	listUsersQuery     *queries.ListUsersQuery
+	getTeamQuery       *queries.GetTeamQuery
+	listTeamsQuery     *queries.ListTeamsQuery
+	authorizer         *authz.Authorizer
+	logger             *slog.Logger
+}
+
+func NewSCIMHandler(
+	provisionUserCmd *commands.ProvisionUserCommand,
+	updateProfileCmd *commands.UpdateUserProfileCommand,
+	setUserActiveCmd *commands.SetUserActiveCommand,
+	deprovisionUserCmd *commands.DeprovisionUserCommand,
+	setTeamMembersCmd *commands.SetTeamMembersCommand,
+	deleteTeamCmd *commands.DeleteTeamCommand,
+	getUserQuery *queries.GetUserQuery,
+	listUsersQuery *queries.ListUsersQuery,
+	getTeamQuery *queries.GetTeamQuery,
+	listTeamsQuery *queries.ListTeamsQuery,
+	authorizer *authz.Authorizer,
+	logger *slog.Logger,
+) *SCIMHandler {
+	return &SCIMHandler{
+		provisionUserCmd:   provisionUserCmd,
+		updateProfileCmd:   updateProfileCmd,
+		setUserActiveCmd:   setUserActiveCmd,
+		deprovisionUserCmd: deprovisionUserCmd,
+		setTeamMembersCmd:  setTeamMembersCmd,
+		deleteTeamCmd:      deleteTeamCmd,
+		getUserQuery:       getUserQuery,
+		listUsersQuery:     listUsersQuery,
+		getTeamQuery:       getTeamQuery,
+		listTeamsQuery:     listTeamsQuery,
+		authorizer:         authorizer,
+		logger:             logger,
+	}
+}
+
+func (h *SCIMHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	filter, start, count, err := parseSCIMListParams(r.URL.Query(), scimUserFilterAttributes)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	users, err := h.listUsersQuery.Execute(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	var resources []any
+	for _, user := range users {
+		if filter == nil || filter.matches(scimUserAttributes(user)) {
+			resources = append(resources, MapUserToSCIM(user))
+		}
+	}
+	respondSCIM(w, http.StatusOK, newSCIMListResponse(resources, start, count))
+}
+
+func (h *SCIMHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req SCIMUser
+	if !h.decode(w, r, &req) || !h.authorize(w, r) {
+		return
+	}
+	if req.UserName == "" {
+		h.handleError(w, r, &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: "userName is required"})
+		return
+	}
+
+	// The userName becomes the ID, which cannot change afterwards.
+	user := entities.NewUser(req.UserName, req.UserName, "", req.Active == nil || *req.Active)
+	user.Email = req.primaryEmail()
+	user, err := h.provisionUserCmd.Execute(r.Context(), user)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.log(r).Info("scim user created", "user_id", user.ID, "created_by", GetUserIDFromContext(r))
+	resp := MapUserToSCIM(user)
+	w.Header().Set("Location", resp.Meta.Location)
+	respondSCIM(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	user, err := h.getUserQuery.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	respondSCIM(w, http.StatusOK, MapUserToSCIM(user))
+}
+
+func (h *SCIMHandler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	var req SCIMUser
+	if !h.decode(w, r, &req) || !h.authorize(w, r) {
+		return
+	}
+	if req.UserName == "" {
+		h.handleError(w, r, &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: "userName is required"})
+		return
+	}
+
+	current, err := h.getUserQuery.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	// A replaced resource without active is active, as on creation.
+	h.updateUser(w, r, current, req.UserName, req.primaryEmail(), req.Active == nil || *req.Active)
+}
+
+func (h *SCIMHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	var req SCIMPatchRequest
+	if !h.decode(w, r, &req) || !h.authorize(w, r) {
+		return
+	}
+
+	current, err := h.getUserQuery.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	patch := scimUserPatch{userName: current.Username, email: current.Email, active: current.IsActive}
+	for _, op := range req.Operations {
+		if err := patch.apply(op); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+	}
+	h.updateUser(w, r, current, patch.userName, patch.email, patch.active)
+}
+
+// updateUser stores the changed profile and then the activity, so that
+// deactivation also revokes the user's sessions.
+func (h *SCIMHandler) updateUser(w http.ResponseWriter, r *http.Request, current *entities.User, userName, email string, active bool) {
+	user := current
+	var err error
+	if userName != current.Username || email != current.Email {
+		if user, err = h.updateProfileCmd.Execute(r.Context(), current.ID, userName, email); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+	}
+	if active != current.IsActive {
+		if user, err = h.setUserActiveCmd.Execute(r.Context(), current.ID, active, true); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+		h.log(r).Info("scim user active changed", "user_id", user.ID, "is_active", active, "changed_by", GetUserIDFromContext(r))
+	}
+	respondSCIM(w, http.StatusOK, MapUserToSCIM(user))
+}
+
+func (h *SCIMHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	userID := r.PathValue("id")
+	if err := h.deprovisionUserCmd.Execute(r.Context(), userID); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	h.log(r).Info("scim user deleted", "user_id", userID, "deleted_by", GetUserIDFromContext(r))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	filter, start, count, err := parseSCIMListParams(r.URL.Query(), scimGroupFilterAttributes)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	teams, err := h.listTeamsQuery.Execute(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	withMembers := !excludesMembers(r.URL.Query())
+	var resources []any
+	for _, team := range teams {
+		if filter == nil || filter.matches(scimGroupAttributes(team)) {
+			resources = append(resources, MapTeamToSCIM(team, withMembers))
+		}
+	}
+	respondSCIM(w, http.StatusOK, newSCIMListResponse(resources, start, count))
+}
+
+func (h *SCIMHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var req SCIMGroup
+	if !h.decode(w, r, &req) || !h.authorize(w, r) {
+		return
+	}
+	if req.DisplayName == "" {
+		h.handleError(w, r, &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: "displayName is required"})
+		return
+	}
+
+	team, err := h.setTeamMembersCmd.Execute(r.Context(), req.DisplayName, referenceValues(req.Members), true)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.log(r).Info("scim group created", "team_name", team.Name, "created_by", GetUserIDFromContext(r))
+	resp := MapTeamToSCIM(team, true)
+	w.Header().Set("Location", resp.Meta.Location)
+	respondSCIM(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	team, err := h.getTeamQuery.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	respondSCIM(w, http.StatusOK, MapTeamToSCIM(team, !excludesMembers(r.URL.Query())))
+}
+
+func (h *SCIMHandler) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	var req SCIMGroup
+	if !h.decode(w, r, &req) || !h.authorize(w, r) {
+		return
+	}
+	teamName := r.PathValue("id")
+	if req.DisplayName != "" && req.DisplayName != teamName {
+		h.handleError(w, r, errSCIMGroupRename)
+		return
+	}
+	h.setMembers(w, r, teamName, referenceValues(req.Members))
+}
+
+func (h *SCIMHandler) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	var req SCIMPatchRequest
+	if !h.decode(w, r, &req) || !h.authorize(w, r) {
+		return
+	}
+
+	team, err := h.getTeamQuery.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	patch := scimGroupPatch{displayName: team.Name}
+	for _, member := range team.Members {
+		patch.members = append(patch.members, member.ID)
+	}
+	for _, op := range req.Operations {
+		if err := patch.apply(op); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+	}
+	h.setMembers(w, r, team.Name, patch.members)
+}
+
+func (h *SCIMHandler) setMembers(w http.ResponseWriter, r *http.Request, teamName string, memberIDs []string) {
+	team, err := h.setTeamMembersCmd.Execute(r.Context(), teamName, memberIDs, false)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	h.log(r).Info("scim group members set", "team_name", team.Name, "members", len(team.Members), "changed_by", GetUserIDFromContext(r))
+	respondSCIM(w, http.StatusOK, MapTeamToSCIM(team, true))
+}
+
+func (h *SCIMHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	teamName := r.PathValue("id")
+	if err := h.deleteTeamCmd.Execute(r.Context(), teamName); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	h.log(r).Info("scim group deleted", "team_name", teamName, "deleted_by", GetUserIDFromContext(r))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	err := h.authorizer.Authorize(r.Context(), GetUserIDFromContext(r), authz.ActionProvisionSCIM, "")
+	if err != nil {
+		h.handleError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func (h *SCIMHandler) decode(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSCIMBodySize)).Decode(dst); err != nil {
+		h.handleError(w, r, &scimError{status: http.StatusBadRequest, scimType: "invalidSyntax", detail: "Invalid request body"})
+		return false
+	}
+	return true
+}
+
+func (h *SCIMHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	h.log(r).Error("error occurred", "error", err)
+
+	var scimErr *scimError
+	switch {
+	case errors.As(err, &scimErr):
+		respondSCIMError(w, scimErr.status, scimErr.scimType, scimErr.detail)
+	case errors.Is(err, entities.ErrForbidden):
+		respondSCIMError(w, http.StatusForbidden, "", "Action is not permitted")
+	case errors.Is(err, entities.ErrUserNotFound):
+		respondSCIMError(w, http.StatusNotFound, "", err.Error())
+	case errors.Is(err, entities.ErrTeamNotFound):
+		respondSCIMError(w, http.StatusNotFound, "", "Group not found")
+	case errors.Is(err, entities.ErrUserExists):
+		respondSCIMError(w, http.StatusConflict, "uniqueness", "userName is already taken")
+	case errors.Is(err, entities.ErrTeamExists):
+		respondSCIMError(w, http.StatusConflict, "uniqueness", "displayName is already taken")
+	case errors.Is(err, entities.ErrTeamNotEmpty):
+		respondSCIMError(w, http.StatusConflict, "", "The default group can only be deleted once it is empty")
+	default:
+		respondSCIMError(w, http.StatusInternalServerError, "", "Internal server error")
+	}
+}
+
+func (h *SCIMHandler) log(r *http.Request) *slog.Logger {
+	return requestLogger(r, h.logger)
+}
+
+func respondSCIM(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", scimContentType)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+func respondSCIMError(w http.ResponseWriter, statusCode int, scimType, detail string) {
+	respondSCIM(w, statusCode, SCIMErrorResponse{
+		Schemas:  []string{scimErrorSchema},
+		Status:   strconv.Itoa(statusCode),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// parseSCIMListParams reads filter, the 1-based startIndex and count. A nil
+// filter matches every resource.
+func parseSCIMListParams(values url.Values, attributes []string) (filter scimFilter, start, count int, err error) {
+	if raw := values.Get("filter"); raw != "" {
+		if filter, err = parseSCIMFilter(raw, attributes); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	start, count = 1, defaultSCIMPageCount
+	if raw := values.Get("startIndex"); raw != "" {
+		if start, err = strconv.Atoi(raw); err != nil {
+			return nil, 0, 0, &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: "startIndex must be a number"}
+		}
+		// RFC 7644 treats values below 1 as 1.
+		start = max(start, 1)
+	}
+	if raw := values.Get("count"); raw != "" {
+		if count, err = strconv.Atoi(raw); err != nil {
+			return nil, 0, 0, &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: "count must be a number"}
+		}
+		count = min(max(count, 0), maxSCIMPageCount)
+	}
+	return filter, start, count, nil
+}
+
+func newSCIMListResponse(resources []any, start, count int) SCIMListResponse {
+	page := []any{}
+	if start <= len(resources) {
+		page = resources[start-1 : min(start-1+count, len(resources))]
+	}
+	return SCIMListResponse{
+		Schemas:      []string{scimListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   start,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+func excludesMembers(values url.Values) bool {
+	for _, attr := range strings.Split(values.Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+func referenceValues(refs []SCIMReference) []string {
+	values := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if !slices.Contains(values, ref.Value) {
+			values = append(values, ref.Value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/authz"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/security"
+)
+
+// scimRecording is a provisioning session captured from an identity provider.
+// Expected response bodies are matched as subsets of the actual ones; a null
+// expects the attribute to be absent.
+type scimRecording struct {
+	Exchanges []struct {
+		Name    string `json:"name"`
+		Request struct {
+			Method string          `json:"method"`
+			Path   string          `json:"path"`
+			Body   json.RawMessage `json:"body"`
+		} `json:"request"`
+		Response struct {
+			Status int             `json:"status"`
+			Body   json.RawMessage `json:"body"`
+		} `json:"response"`
+	} `json:"exchanges"`
+}
+
+// newSCIMTestRouter serves SCIM from an in-memory store and returns an API
+// token with the scim scope, owned by an admin as an IdP integration would be.
+func newSCIMTestRouter(t *testing.T) (http.Handler, string) {
+	t.Helper()
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clock := services.NewRealClock()
+	store := repositories.NewInMemoryStore()
+
+	bot := entities.NewUser("idp-bot", "idp", "ops", true)
+	bot.SetRole(entities.RoleAdmin)
+	if err := store.Teams.Save(ctx, entities.NewTeam("ops", []*entities.User{bot})); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	_, raw, err := commands.NewCreateAPITokenCommand(store.APITokens, store.Users, clock).
+		Execute(ctx, bot.ID, "idp", []entities.Scope{entities.ScopeSCIM}, 0)
+	if err != nil {
+		t.Fatalf("failed to create api token: %v", err)
+	}
+
+	syncRepo := repositories.NewInMemoryTeamSyncRepository(store)
+	denylist := security.NewDenylist(store.Revocations, store.RefreshTokens, clock, logger)
+	router := NewRouter(logger, RouterDeps{
+		SetUserActive:   commands.NewSetUserActiveCommand(store.Users, denylist),
+		ProvisionUser:   commands.NewProvisionUserCommand(store.Users, store.Teams, syncRepo, "unassigned", logger),
+		UpdateProfile:   commands.NewUpdateUserProfileCommand(store.Users),
+		DeprovisionUser: commands.NewDeprovisionUserCommand(store.Users, syncRepo, denylist, logger),
+		SetTeamMembers:  commands.NewSetTeamMembersCommand(store.Teams, store.Users, syncRepo, "unassigned", logger),
+		DeleteTeam:      commands.NewDeleteTeamCommand(store.Teams, syncRepo, "unassigned", logger),
+		GetTeam:         queries.NewGetTeamQuery(store.Teams),
+		GetUser:         queries.NewGetUserQuery(store.Users),
+		ListUsers:       queries.NewListUsersQuery(store.Users),
+		ListTeams:       queries.NewListTeamsQuery(store.Teams),
+		VerifyAPIToken:  queries.NewVerifyAPITokenQuery(store.APITokens, store.Users, clock),
+		UserRepo:        store.Users,
+		Denylist:        denylist,
+		Authorizer:      authz.NewAuthorizer(store.Users, store.PRs),
+	})
+	return router, raw
+}
+
+func TestSCIMRecordedIdentityProviders(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "scim", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no scim recordings found: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("failed to read recording: %v", err)
+			}
+			var recording scimRecording
+			if err := json.Unmarshal(data, &recording); err != nil {
+				t.Fatalf("failed to parse recording: %v", err)
+			}
+
+			router, token := newSCIMTestRouter(t)
+			for i, exchange := range recording.Exchanges {
+				var body io.Reader
+				if len(exchange.Request.Body) > 0 {
+					body = bytes.NewReader(exchange.Request.Body)
+				}
+				req := httptest.NewRequest(exchange.Request.Method, exchange.Request.Path, body)
+				req.Header.Set("Authorization", "Bearer "+token)
+				req.Header.Set("Content-Type", scimContentType)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				name := fmt.Sprintf("#%d %s", i+1, exchange.Name)
+				if rec.Code != exchange.Response.Status {
+					t.Fatalf("%s: expected status %d, got %d: %s", name, exchange.Response.Status, rec.Code, rec.Body.String())
+				}
+				if len(exchange.Response.Body) == 0 {
+					continue
+				}
+				if ct := rec.Header().Get("Content-Type"); ct != scimContentType {
+					t.Errorf("%s: expected content type %s, got %q", name, scimContentType, ct)
+				}
+
+				var want, got any
+				if err := json.Unmarshal(exchange.Response.Body, &want); err != nil {
+					t.Fatalf("%s: invalid expected body: %v", name, err)
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("%s: invalid response body %q: %v", name, rec.Body.String(), err)
+				}
+				if path, ok := jsonSubset(want, got, "$"); !ok {
+					t.Errorf("%s: response differs at %s\n got: %s", name, path, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// jsonSubset reports whether got contains every value of want, returning the
+// first path that differs.
+func jsonSubset(want, got any, path string) (string, bool) {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return path, false
+		}
+		for key, value := range w {
+			actual, present := g[key]
+			if value == nil {
+				if present && actual != nil {
+					return path + "." + key, false
+				}
+				continue
+			}
+			if p, ok := jsonSubset(value, actual, path+"."+key); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return path, false
+		}
+		for i := range w {
+			if p, ok := jsonSubset(w[i], g[i], fmt.Sprintf("%s[%d]", path, i)); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	default:
+		return path, want == got
+	}
+}
+
+func TestSCIMRequiresScope(t *testing.T) {
+	router, _ := newSCIMTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scim/v2/Users", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", rec.Code)
+	}
+}
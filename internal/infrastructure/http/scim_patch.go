@@ -0,0 +1,198 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var errSCIMGroupRename = &scimError{
+	status:   http.StatusBadRequest,
+	scimType: "mutability",
+	detail:   "displayName names the team and cannot be changed",
+}
+
+// scimUserPatch collects the user attributes a PatchOp changes. It accepts the
+// forms Okta and Azure AD send: a path per attribute, a value object without a
+// path, and booleans written as strings. Attributes the service does not store
+// are ignored.
+type scimUserPatch struct {
+	userName string
+	email    string
+	active   bool
+}
+
+func (p *scimUserPatch) apply(op SCIMPatchOperation) error {
+	kind, err := scimPatchOp(op)
+	if err != nil {
+		return err
+	}
+	if op.Path == "" {
+		return applyToValueObject(op, p.apply)
+	}
+
+	path := scimAttributePath(op.Path)
+	switch {
+	case path == "active" && kind != "remove":
+		return decodeSCIMBool(op.Value, &p.active)
+	case path == "username" && kind != "remove":
+		return decodeSCIMString(op.Value, &p.userName)
+	case path == "emails" || strings.HasPrefix(path, "emails["):
+		if kind == "remove" {
+			p.email = ""
+			return nil
+		}
+		return p.applyEmail(op.Value)
+	}
+	return nil
+}
+
+// applyEmail accepts either a plain address, sent for paths such as
+// emails[type eq "work"].value, or a list of email objects.
+func (p *scimUserPatch) applyEmail(value json.RawMessage) error {
+	var emails []SCIMEmail
+	if err := json.Unmarshal(value, &emails); err == nil {
+		p.email = (&SCIMUser{Emails: emails}).primaryEmail()
+		return nil
+	}
+	return decodeSCIMString(value, &p.email)
+}
+
+// scimGroupPatch collects the member IDs of a group after a PatchOp.
+type scimGroupPatch struct {
+	displayName string
+	members     []string
+}
+
+func (p *scimGroupPatch) apply(op SCIMPatchOperation) error {
+	kind, err := scimPatchOp(op)
+	if err != nil {
+		return err
+	}
+	if op.Path == "" {
+		return applyToValueObject(op, p.apply)
+	}
+
+	path := scimAttributePath(op.Path)
+	switch {
+	case path == "displayname":
+		var name string
+		if err := decodeSCIMString(op.Value, &name); err != nil {
+			return err
+		}
+		if kind == "remove" || name != p.displayName {
+			return errSCIMGroupRename
+		}
+		return nil
+
+	case path == "members":
+		var refs []SCIMReference
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &refs); err != nil {
+				return invalidSCIMValue("members must be a list of references")
+			}
+		}
+		values := referenceValues(refs)
+		switch {
+		case kind == "replace":
+			p.members = values
+		case kind == "add":
+			for _, v := range values {
+				if !slices.Contains(p.members, v) {
+					p.members = append(p.members, v)
+				}
+			}
+		case len(values) == 0:
+			p.members = nil
+		default:
+			p.members = slices.DeleteFunc(p.members, func(id string) bool { return slices.Contains(values, id) })
+		}
+		return nil
+
+	case strings.HasPrefix(path, "members["):
+		if kind != "remove" {
+			return invalidSCIMValue("only remove supports a members filter")
+		}
+		// The path keeps its case here: member IDs are case-sensitive.
+		raw := op.Path[strings.Index(op.Path, "[")+1:]
+		end := strings.LastIndex(raw, "]")
+		if end < 0 {
+			return &scimError{status: http.StatusBadRequest, scimType: "invalidPath", detail: "unterminated members filter"}
+		}
+		filter, err := parseSCIMFilter(raw[:end], []string{"value"})
+		if err != nil {
+			return err
+		}
+		p.members = slices.DeleteFunc(p.members, func(id string) bool {
+			return filter.matches(scimAttributes{"value": {values: []string{id}, caseExact: true}})
+		})
+		return nil
+	}
+	return nil
+}
+
+func scimPatchOp(op SCIMPatchOperation) (string, error) {
+	kind := strings.ToLower(op.Op)
+	switch kind {
+	case "add", "replace", "remove":
+		return kind, nil
+	}
+	return "", &scimError{status: http.StatusBadRequest, scimType: "invalidSyntax", detail: "unsupported patch op " + strconv.Quote(op.Op)}
+}
+
+// applyToValueObject handles operations without a path, whose value maps
+// attribute names to their new values.
+func applyToValueObject(op SCIMPatchOperation, apply func(SCIMPatchOperation) error) error {
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &attrs); err != nil {
+		return &scimError{status: http.StatusBadRequest, scimType: "noTarget", detail: "an operation without a path needs an object value"}
+	}
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := apply(SCIMPatchOperation{Op: op.Op, Path: key, Value: attrs[key]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scimAttributePath lower-cases a path and drops its schema URN prefix.
+func scimAttributePath(path string) string {
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		if i := strings.LastIndex(path, ":"); i >= 0 {
+			path = path[i+1:]
+		}
+	}
+	return strings.ToLower(path)
+}
+
+func decodeSCIMBool(value json.RawMessage, dst *bool) error {
+	if err := json.Unmarshal(value, dst); err == nil {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			*dst = b
+			return nil
+		}
+	}
+	return invalidSCIMValue("active must be a boolean")
+}
+
+func decodeSCIMString(value json.RawMessage, dst *string) error {
+	if err := json.Unmarshal(value, dst); err != nil {
+		return invalidSCIMValue("expected a string value")
+	}
+	return nil
+}
+
+func invalidSCIMValue(detail string) *scimError {
+	return &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: detail}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type SigningKey struct {
+	ID     string
+	Secret []byte
+}
+
+type TokenConfig struct {
+	// ActiveKey signs new tokens; it is always accepted for verification too.
+	ActiveKey SigningKey
+	// VerificationKeys are older keys that are still accepted while tokens
+	// signed with them have not expired yet.
+	VerificationKeys []SigningKey
+	Issuer           string
+	Audience         string
+	AccessTokenTTL   time.Duration
+	RefreshTokenTTL  time.Duration
+}
+
+type Claims struct {
+	UserID string `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+type TokenManager struct {
+	activeKey       SigningKey
+	keys            map[string][]byte
+	issuer          string
+	audience        string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	now             func() time.Time
+}
+
+func NewTokenManager(cfg TokenConfig) (*TokenManager, error) {
+	if len(cfg.ActiveKey.Secret) == 0 {
+		return nil, errors.New("jwt signing secret is required")
+	}
+	if cfg.ActiveKey.ID == "" {
+		return nil, errors.New("jwt signing key id is required")
+	}
+	if cfg.AccessTokenTTL <= 0 {
+		return nil, errors.New("access token ttl must be positive")
+	}
+
+	keys := map[string][]byte{cfg.ActiveKey.ID: cfg.ActiveKey.Secret}
+	for _, key := range cfg.VerificationKeys {
+		if key.ID == "" || len(key.Secret) == 0 {
+			return nil, errors.New("verification keys need both an id and a secret")
+		}
+		if _, exists := keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id %q", key.ID)
+		}
+		keys[key.ID] = key.Secret
+	}
+
+	return &TokenManager{
+		activeKey:       cfg.ActiveKey,
+		keys:            keys,
+		issuer:          cfg.Issuer,
+		audience:        cfg.Audience,
+		accessTokenTTL:  cfg.AccessTokenTTL,
+		refreshTokenTTL: cfg.RefreshTokenTTL,
+		now:             time.Now,
+	}, nil
+}
+
+func (m *TokenManager) AccessTokenTTL() time.Duration {
+	return m.accessTokenTTL
+}
+
+func (m *TokenManager) RefreshTokenTTL() time.Duration {
+	return m.refreshTokenTTL
+}
+
+func (m *TokenManager) GenerateToken(userID string) (string, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", err
+	}
+
+	now := m.now()
+	claims := Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   userID,
+			Issuer:    m.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTokenTTL)),
+		},
+	}
+	if m.audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.audience}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = m.activeKey.ID
+
+	tokenString, err := token.SignedString(m.activeKey.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func (m *TokenManager) ParseToken(tokenString string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(m.now),
+	}
+	if m.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.issuer))
+	}
+	if m.audience != "" {
+		opts = append(opts, jwt.WithAudience(m.audience))
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.UserID == "" || claims.ID == "" {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+func (m *TokenManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no key id")
+	}
+	secret, ok := m.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return secret, nil
+}
+
+// NewRefreshToken returns an opaque refresh token for the client together
+// with the hash that is stored server-side.
+func NewRefreshToken() (string, string, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return raw, HashRefreshToken(raw), nil
+}
+
+func HashRefreshToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTokenManager(t *testing.T, cfg TokenConfig) *TokenManager {
+	if cfg.ActiveKey.ID == "" {
+		cfg.ActiveKey = SigningKey{ID: "k1", Secret: []byte("secret-1")}
+	}
+	if cfg.AccessTokenTTL == 0 {
+		cfg.AccessTokenTTL = 15 * time.Minute
+	}
+	if cfg.RefreshTokenTTL == 0 {
+		cfg.RefreshTokenTTL = time.Hour
+	}
+	m, err := NewTokenManager(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestTokenManagerRoundTrip(t *testing.T) {
+	m := newTestTokenManager(t, TokenConfig{Issuer: "svc", Audience: "api"})
+
+	token, err := m.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims, err := m.ParseToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.UserID != "user1" {
+		t.Errorf("expected user1, got %s", claims.UserID)
+	}
+	if claims.ExpiresAt == nil || claims.IssuedAt == nil {
+		t.Error("expected exp and iat claims to be set")
+	}
+}
+
+func TestTokenManagerRejectsInvalidTokens(t *testing.T) {
+	issuer := newTestTokenManager(t, TokenConfig{Issuer: "svc", Audience: "api"})
+
+	tests := []struct {
+		name     string
+		verifier *TokenManager
+		shift    time.Duration
+	}{
+		{
+			name:     "Expired token",
+			verifier: issuer,
+			shift:    time.Hour,
+		},
+		{
+			name:     "Wrong issuer",
+			verifier: newTestTokenManager(t, TokenConfig{Issuer: "other", Audience: "api"}),
+		},
+		{
+			name:     "Wrong audience",
+			verifier: newTestTokenManager(t, TokenConfig{Issuer: "svc", Audience: "other"}),
+		},
+		{
+			name: "Unknown key id",
+			verifier: newTestTokenManager(t, TokenConfig{
+				ActiveKey: SigningKey{ID: "k2", Secret: []byte("secret-2")},
+				Issuer:    "svc",
+				Audience:  "api",
+			}),
+		},
+	}
+
+	token, err := issuer.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift := tt.shift
+			tt.verifier.now = func() time.Time { return time.Now().Add(shift) }
+
+			if _, err := tt.verifier.ParseToken(token); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestTokenManagerKeyRotation(t *testing.T) {
+	oldKey := SigningKey{ID: "k1", Secret: []byte("secret-1")}
+	newKey := SigningKey{ID: "k2", Secret: []byte("secret-2")}
+
+	before := newTestTokenManager(t, TokenConfig{ActiveKey: oldKey})
+	token, err := before.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	after := newTestTokenManager(t, TokenConfig{ActiveKey: newKey, VerificationKeys: []SigningKey{oldKey}})
+	if _, err := after.ParseToken(token); err != nil {
+		t.Errorf("expected token signed with retired key to verify, got %v", err)
+	}
+
+	rotated, err := after.GenerateToken("user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := before.ParseToken(rotated); err == nil {
+		t.Error("expected token signed with new key to be rejected by old key set")
+	}
+}
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+)
+
+const namespace = "pr_reviewer"
+
+// collectTimeout bounds the database queries run on each scrape.
+const collectTimeout = 5 * time.Second
+
+// Metrics owns the Prometheus registry exposed on /metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	assignments  *prometheus.CounterVec
+	noCandidate  *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		assignments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_assignments_total",
+			Help:      "Reviewers assigned to pull requests by strategy and team.",
+		}, []string{"strategy", "team"}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_no_candidate_total",
+			Help:      "Assignments that failed because no active reviewer was available.",
+		}, []string{"operation", "team"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.assignments,
+		m.noCandidate,
+	)
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ReviewersAssigned(strategy, team string, count int) {
+	m.assignments.WithLabelValues(strategy, team).Add(float64(count))
+}
+
+func (m *Metrics) NoCandidateFound(operation, team string) {
+	m.noCandidate.WithLabelValues(operation, team).Inc()
+}
+
+// RegisterDatabase exports connection pool statistics, labelled with the
+// storage driver name, and the applied schema migration version.
+func (m *Metrics) RegisterDatabase(db *sql.DB, driver string, migrationRepo ports.MigrationRepository, logger *slog.Logger) error {
+	if err := m.registry.Register(collectors.NewDBStatsCollector(db, driver)); err != nil {
+		return err
+	}
+	return m.registry.Register(&migrationCollector{repo: migrationRepo, logger: logger})
+}
+
+// RegisterOpenPRs exports the number of open pull requests per team, counted
+// on each scrape.
+func (m *Metrics) RegisterOpenPRs(query *queries.CountOpenPRsByTeamQuery, logger *slog.Logger) error {
+	return m.registry.Register(&openPRCollector{query: query, logger: logger})
+}
+
+var openPRsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "open_pull_requests"),
+	"Open pull requests by author team.",
+	[]string{"team"}, nil,
+)
+
+type openPRCollector struct {
+	query  *queries.CountOpenPRsByTeamQuery
+	logger *slog.Logger
+}
+
+func (c *openPRCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- openPRsDesc
+}
+
+func (c *openPRCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.query.Execute(ctx)
+	if err != nil {
+		c.logger.Warn("failed to count open pull requests", "error", err)
+		ch <- prometheus.NewInvalidMetric(openPRsDesc, err)
+		return
+	}
+	for team, count := range counts {
+		ch <- prometheus.MustNewConstMetric(openPRsDesc, prometheus.GaugeValue, float64(count), team)
+	}
+}
+
+var (
+	migrationVersionDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "schema_migration_version"),
+		"Version of the last applied schema migration.",
+		nil, nil,
+	)
+	migrationDirtyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "schema_migration_dirty"),
+		"1 when the last migration failed part-way and needs manual repair.",
+		nil, nil,
+	)
+)
+
+type migrationCollector struct {
+	repo   ports.MigrationRepository
+	logger *slog.Logger
+}
+
+func (c *migrationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- migrationVersionDesc
+	ch <- migrationDirtyDesc
+}
+
+func (c *migrationCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	version, dirty, err := c.repo.GetCurrentVersion(ctx)
+	if err != nil {
+		c.logger.Warn("failed to read schema migration version", "error", err)
+		ch <- prometheus.NewInvalidMetric(migrationVersionDesc, err)
+		return
+	}
+
+	dirtyValue := 0.0
+	if dirty {
+		dirtyValue = 1
+	}
+	ch <- prometheus.MustNewConstMetric(migrationVersionDesc, prometheus.GaugeValue, float64(version))
+	ch <- prometheus.MustNewConstMetric(migrationDirtyDesc, prometheus.GaugeValue, dirtyValue)
+}
@@ -0,0 +1,110 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/queries"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/metrics"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/migrations"
+)
+
+func TestMetricsExposition(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	userRepo := repositories.NewInMemoryUserRepository()
+	teamRepo := repositories.NewInMemoryTeamRepository(userRepo)
+	prRepo := repositories.NewInMemoryPRRepository(userRepo, repositories.NewInMemoryAssignmentDecisionRepository())
+
+	teams := map[string][]*entities.User{
+		"backend": {
+			entities.NewUser("user1", "alice", "backend", true),
+			entities.NewUser("user2", "bob", "backend", true),
+			entities.NewUser("user3", "carol", "backend", true),
+		},
+		"solo": {entities.NewUser("user4", "dave", "solo", true)},
+	}
+	for name, members := range teams {
+		if err := teamRepo.Save(ctx, entities.NewTeam(name, members)); err != nil {
+			t.Fatalf("failed to save team: %v", err)
+		}
+		for _, m := range members {
+			if err := userRepo.Save(ctx, m); err != nil {
+				t.Fatalf("failed to save user: %v", err)
+			}
+		}
+	}
+
+	m := metrics.New()
+	if err := m.RegisterOpenPRs(queries.NewCountOpenPRsByTeamQuery(prRepo), logger); err != nil {
+		t.Fatalf("failed to register collector: %v", err)
+	}
+
+	createPR := commands.NewCreatePRCommand(
+		teamRepo, userRepo, prRepo,
+		services.NewReviewerAssignmentService(nil, 0),
+		nil, m, logger,
+	)
+	if _, err := createPR.Execute(ctx, "pr-1", "Add search", "user1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := createPR.Execute(ctx, "pr-2", "Fix typo", "user4"); !errors.Is(err, entities.ErrNoCandidateFound) {
+		t.Fatalf("expected ErrNoCandidateFound, got %v", err)
+	}
+	m.ObserveRequest(http.MethodPost, "/pullRequest/create", http.StatusCreated, 20*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`pr_reviewer_reviewer_assignments_total{strategy="random",team="backend"} 2`,
+		`pr_reviewer_reviewer_no_candidate_total{operation="create",team="solo"} 1`,
+		`pr_reviewer_open_pull_requests{team="backend"} 1`,
+		`pr_reviewer_http_requests_total{method="POST",route="/pullRequest/create",status="201"} 1`,
+		`pr_reviewer_http_request_duration_seconds_count{method="POST",route="/pullRequest/create"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}
+
+func TestDatabaseMetricsNameTheDriver(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := config.NewSQLiteConnection(&config.DBConfig{SQLitePath: filepath.Join(t.TempDir(), "metrics.db")})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrationRepo, err := repositories.NewSQLiteMigrationRepository(db, migrations.SQLiteFS)
+	if err != nil {
+		t.Fatalf("failed to create migration repository: %v", err)
+	}
+
+	m := metrics.New()
+	if err := m.RegisterDatabase(db, "sqlite", migrationRepo, logger); err != nil {
+		t.Fatalf("failed to register collectors: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if want := `go_sql_max_open_connections{db_name="sqlite"}`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("expected metrics to contain %q", want)
+	}
+}
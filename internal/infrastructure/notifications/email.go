@@ -0,0 +1,228 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const DefaultAssignmentEmailTemplate = `Hi {{.Reviewer.Username}},
+
+You have been assigned to review "{{.PR.Name}}" ({{.PR.ID}}) by {{.PR.AuthorID}}.
+`
+
+const DefaultDigestEmailTemplate = `Hi {{.Reviewer.Username}},
+
+You have {{len .Assignments}} open review assignment(s){{if .SLA}}, review SLA is {{age .SLA}}{{end}}:
+{{range .Assignments}}
+- {{.PR.Name}} ({{.PR.ID}}) by {{.PR.AuthorID}}, open for {{age .Age}}{{if .SLABreached}} [SLA BREACHED]{{end}}{{end}}
+`
+
+type EmailConfig struct {
+	Host               string
+	Port               string
+	Username           string
+	Password           string
+	From               string
+	AssignmentTemplate string
+	DigestTemplate     string
+	// Timeout bounds each message, from dialing to QUIT.
+	Timeout time.Duration
+}
+
+type assignmentEmail struct {
+	Reviewer *entities.User
+	PR       *entities.PullRequest
+	Team     *entities.Team
+}
+
+type EmailNotifier struct {
+	addr           string
+	host           string
+	auth           smtp.Auth
+	from           string
+	assignmentTmpl *template.Template
+	digestTmpl     *template.Template
+	timeout        time.Duration
+	logger         *slog.Logger
+}
+
+func NewEmailNotifier(cfg EmailConfig, logger *slog.Logger) (*EmailNotifier, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("smtp host is required")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("smtp sender address is required")
+	}
+
+	funcs := template.FuncMap{"age": formatAge}
+
+	assignmentText := cfg.AssignmentTemplate
+	if assignmentText == "" {
+		assignmentText = DefaultAssignmentEmailTemplate
+	}
+	assignmentTmpl, err := template.New("assignment").Funcs(funcs).Parse(assignmentText)
+	if err != nil {
+		return nil, fmt.Errorf("parse assignment email template: %w", err)
+	}
+
+	digestText := cfg.DigestTemplate
+	if digestText == "" {
+		digestText = DefaultDigestEmailTemplate
+	}
+	digestTmpl, err := template.New("digest").Funcs(funcs).Parse(digestText)
+	if err != nil {
+		return nil, fmt.Errorf("parse digest email template: %w", err)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &EmailNotifier{
+		addr:           net.JoinHostPort(cfg.Host, cfg.Port),
+		host:           cfg.Host,
+		auth:           auth,
+		from:           cfg.From,
+		assignmentTmpl: assignmentTmpl,
+		digestTmpl:     digestTmpl,
+		timeout:        timeout,
+		logger:         logger,
+	}, nil
+}
+
+// NotifyReviewersAssigned emails every opted-in reviewer; one failed delivery
+// does not stop the others.
+func (n *EmailNotifier) NotifyReviewersAssigned(ctx context.Context, event ports.ReviewersAssignedEvent) error {
+	var errs []error
+	for _, reviewer := range event.Reviewers {
+		if !reviewer.WantsAssignmentEmails() {
+			continue
+		}
+
+		var body bytes.Buffer
+		data := assignmentEmail{Reviewer: reviewer, PR: event.PR, Team: event.Team}
+		if err := n.assignmentTmpl.Execute(&body, data); err != nil {
+			errs = append(errs, fmt.Errorf("render assignment email for %s: %w", reviewer.ID, err))
+			continue
+		}
+
+		subject := fmt.Sprintf("Review requested: %s", event.PR.Name)
+		if err := n.send(ctx, reviewer.Email, subject, body.String()); err != nil {
+			errs = append(errs, fmt.Errorf("send assignment email to %s: %w", reviewer.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *EmailNotifier) SendDigest(ctx context.Context, digest ports.ReviewDigest) error {
+	if !digest.Reviewer.WantsDigestEmails() {
+		return nil
+	}
+
+	var body bytes.Buffer
+	if err := n.digestTmpl.Execute(&body, digest); err != nil {
+		return fmt.Errorf("render digest email: %w", err)
+	}
+
+	subject := fmt.Sprintf("Daily review digest: %d open assignment(s)", len(digest.Assignments))
+	return n.send(ctx, digest.Reviewer.Email, subject, body.String())
+}
+
+func (n *EmailNotifier) send(ctx context.Context, to, subject, body string) error {
+	// Assignment emails are sent while the request that assigned the
+	// reviewers waits, so a hung server must not hold it up.
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if n.auth != nil {
+		if err := client.Auth(n.auth); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.from); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(buildMessage(n.from, to, subject, body)); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("close message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func buildMessage(from, to, subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return msg.Bytes()
+}
+
+func formatAge(d time.Duration) string {
+	d = d.Round(time.Hour)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	default:
+		return fmt.Sprintf("%dh", hours)
+	}
+}
@@ -0,0 +1,284 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type receivedMail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpStandIn is a minimal in-process SMTP server that accepts every message
+// and records it for inspection.
+type smtpStandIn struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []receivedMail
+	// rejected recipients are refused with a permanent error.
+	rejected []string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &smtpStandIn{listener: listener}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *smtpStandIn) hostPort() (string, string) {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return host, port
+}
+
+func (s *smtpStandIn) received() []receivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedMail(nil), s.messages...)
+}
+
+func (s *smtpStandIn) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP stand-in")
+	var mail receivedMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			mail = receivedMail{From: strings.Trim(cmd[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			to := strings.Trim(cmd[len("RCPT TO:"):], "<> ")
+			if slices.Contains(s.rejected, to) {
+				reply("550 No such user")
+				continue
+			}
+			mail.To = append(mail.To, to)
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dl, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dl == ".\r\n" {
+					break
+				}
+				data.WriteString(dl)
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, mail)
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func newTestEmailNotifier(t *testing.T, server *smtpStandIn) *EmailNotifier {
+	host, port := server.hostPort()
+	notifier, err := NewEmailNotifier(EmailConfig{
+		Host: host,
+		Port: port,
+		From: "reviews@example.com",
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return notifier
+}
+
+func TestEmailNotifierSendsOnlyToOptedInReviewers(t *testing.T) {
+	server := newSMTPStandIn(t)
+	notifier := newTestEmailNotifier(t, server)
+
+	optedIn := entities.NewUser("user2", "bob", "backend", true)
+	optedIn.SetNotificationPreferences("bob@example.com", entities.NotificationPreferences{EmailOnAssignment: true})
+	optedOut := entities.NewUser("user3", "charlie", "backend", true)
+	optedOut.SetNotificationPreferences("charlie@example.com", entities.NotificationPreferences{EmailDigest: true})
+
+	event := ports.ReviewersAssignedEvent{
+		PR:        entities.NewPullRequest("pr-1", "Add search", "user1", []string{"user2", "user3"}),
+		Reviewers: []*entities.User{optedIn, optedOut},
+	}
+
+	if err := notifier.NotifyReviewersAssigned(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+	if messages[0].To[0] != "bob@example.com" {
+		t.Errorf("expected message to bob@example.com, got %v", messages[0].To)
+	}
+	if !strings.Contains(messages[0].Data, "Subject: Review requested: Add search") {
+		t.Errorf("expected subject header, got %q", messages[0].Data)
+	}
+	if !strings.Contains(messages[0].Data, `"Add search" (pr-1)`) {
+		t.Errorf("expected pull request in body, got %q", messages[0].Data)
+	}
+}
+
+func TestEmailNotifierKeepsSendingAfterFailedDelivery(t *testing.T) {
+	server := newSMTPStandIn(t)
+	server.rejected = []string{"bob@example.com"}
+	notifier := newTestEmailNotifier(t, server)
+
+	bob := entities.NewUser("user2", "bob", "backend", true)
+	bob.SetNotificationPreferences("bob@example.com", entities.NotificationPreferences{EmailOnAssignment: true})
+	charlie := entities.NewUser("user3", "charlie", "backend", true)
+	charlie.SetNotificationPreferences("charlie@example.com", entities.NotificationPreferences{EmailOnAssignment: true})
+
+	event := ports.ReviewersAssignedEvent{
+		PR:        entities.NewPullRequest("pr-1", "Add search", "user1", []string{"user2", "user3"}),
+		Reviewers: []*entities.User{bob, charlie},
+	}
+
+	err := notifier.NotifyReviewersAssigned(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "user2") {
+		t.Fatalf("expected an error for user2, got %v", err)
+	}
+
+	messages := server.received()
+	if len(messages) != 1 || messages[0].To[0] != "charlie@example.com" {
+		t.Fatalf("expected one message to charlie@example.com, got %v", messages)
+	}
+}
+
+func TestEmailNotifierSendsDigest(t *testing.T) {
+	server := newSMTPStandIn(t)
+	notifier := newTestEmailNotifier(t, server)
+
+	reviewer := entities.NewUser("user2", "bob", "backend", true)
+	reviewer.SetNotificationPreferences("bob@example.com", entities.NotificationPreferences{EmailDigest: true})
+
+	digest := ports.ReviewDigest{
+		Reviewer: reviewer,
+		SLA:      48 * time.Hour,
+		Assignments: []ports.ReviewAssignment{
+			{
+				PR:          entities.NewPullRequest("pr-1", "Old change", "user1", []string{"user2"}),
+				Age:         74 * time.Hour,
+				SLABreached: true,
+			},
+			{
+				PR:  entities.NewPullRequest("pr-2", "Fresh change", "user1", []string{"user2"}),
+				Age: 5 * time.Hour,
+			},
+		},
+		GeneratedAt: time.Now(),
+	}
+
+	if err := notifier.SendDigest(context.Background(), digest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+
+	body := messages[0].Data
+	for _, want := range []string{
+		"Subject: Daily review digest: 2 open assignment(s)",
+		"review SLA is 2d",
+		"Old change (pr-1) by user1, open for 3d 2h [SLA BREACHED]",
+		"Fresh change (pr-2) by user1, open for 5h\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected digest to contain %q, got %q", want, body)
+		}
+	}
+}
+
+func TestEmailNotifierTimesOutOnHungServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	// Accept connections but never send the greeting.
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	notifier, err := NewEmailNotifier(EmailConfig{
+		Host:    host,
+		Port:    port,
+		From:    "reviews@example.com",
+		Timeout: 100 * time.Millisecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reviewer := entities.NewUser("user2", "bob", "backend", true)
+	reviewer.SetNotificationPreferences("bob@example.com", entities.NotificationPreferences{EmailOnAssignment: true})
+	event := ports.ReviewersAssignedEvent{
+		PR:        entities.NewPullRequest("pr-1", "Add search", "user1", []string{"user2"}),
+		Reviewers: []*entities.User{reviewer},
+	}
+
+	start := time.Now()
+	if err := notifier.NotifyReviewersAssigned(context.Background(), event); err == nil {
+		t.Fatal("expected an error from a hung server")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the send to give up after the timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,37 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+type NoopNotifier struct{}
+
+func (NoopNotifier) NotifyReviewersAssigned(ctx context.Context, event ports.ReviewersAssignedEvent) error {
+	return nil
+}
+
+// MultiNotifier fans an event out to every notifier and joins their errors,
+// so one broken channel does not stop delivery to the others.
+type MultiNotifier struct {
+	notifiers []ports.Notifier
+}
+
+func NewMultiNotifier(notifiers ...ports.Notifier) ports.Notifier {
+	if len(notifiers) == 0 {
+		return NoopNotifier{}
+	}
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+func (m *MultiNotifier) NotifyReviewersAssigned(ctx context.Context, event ports.ReviewersAssignedEvent) error {
+	var errs []error
+	for _, n := range m.notifiers {
+		if err := n.NotifyReviewersAssigned(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
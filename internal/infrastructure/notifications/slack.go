@@ -0,0 +1,122 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const DefaultSlackTemplate = `:eyes: {{mentions .Reviewers}} you have been assigned to review *{{.PR.Name}}* (` + "`{{.PR.ID}}`" + `) by {{.PR.AuthorID}}`
+
+type SlackConfig struct {
+	// DefaultWebhookURL is used for teams that have no channel URL of their own.
+	DefaultWebhookURL string
+	Template          string
+	Timeout           time.Duration
+}
+
+type SlackPayload struct {
+	Text string `json:"text"`
+}
+
+type SlackNotifier struct {
+	client     *http.Client
+	defaultURL string
+	tmpl       *template.Template
+	logger     *slog.Logger
+}
+
+func NewSlackNotifier(cfg SlackConfig, logger *slog.Logger) (*SlackNotifier, error) {
+	text := cfg.Template
+	if text == "" {
+		text = DefaultSlackTemplate
+	}
+
+	tmpl, err := template.New("slack").Funcs(template.FuncMap{
+		"mention":  SlackMention,
+		"mentions": slackMentions,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse slack template: %w", err)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &SlackNotifier{
+		// The transport forwards the caller's traceparent to the webhook.
+		client:     &http.Client{Timeout: timeout, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		defaultURL: cfg.DefaultWebhookURL,
+		tmpl:       tmpl,
+		logger:     logger,
+	}, nil
+}
+
+func (n *SlackNotifier) NotifyReviewersAssigned(ctx context.Context, event ports.ReviewersAssignedEvent) error {
+	url := n.defaultURL
+	if event.Team != nil && event.Team.ChatWebhookURL != "" {
+		url = event.Team.ChatWebhookURL
+	}
+	if url == "" {
+		n.logger.Debug("no slack webhook configured, skipping notification", "pull_request_id", event.PR.ID)
+		return nil
+	}
+
+	var text bytes.Buffer
+	if err := n.tmpl.Execute(&text, event); err != nil {
+		return fmt.Errorf("render slack message: %w", err)
+	}
+
+	body, err := json.Marshal(SlackPayload{Text: text.String()})
+	if err != nil {
+		return fmt.Errorf("marshal slack payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post slack webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SlackMention renders a user mention, falling back to the plain username
+// when no chat handle is stored for the user.
+func SlackMention(user *entities.User) string {
+	if user.ChatHandle != "" {
+		return "<@" + user.ChatHandle + ">"
+	}
+	return user.Username
+}
+
+func slackMentions(users []*entities.User) string {
+	mentions := make([]string, 0, len(users))
+	for _, user := range users {
+		mentions = append(mentions, SlackMention(user))
+	}
+	return strings.Join(mentions, ", ")
+}
@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+func newTestEvent(webhookURL string) ports.ReviewersAssignedEvent {
+	alice := entities.NewUser("user1", "alice", "backend", true)
+	bob := entities.NewUser("user2", "bob", "backend", true)
+	bob.ChatHandle = "U0BOB"
+	charlie := entities.NewUser("user3", "charlie", "backend", true)
+	charlie.ChatHandle = "U0CHARLIE"
+
+	team := entities.NewTeam("backend", []*entities.User{alice, bob, charlie})
+	team.ChatWebhookURL = webhookURL
+
+	return ports.ReviewersAssignedEvent{
+		PR:        entities.NewPullRequest("pr-1", "Add search", "user1", []string{"user2", "user3"}),
+		Team:      team,
+		Reviewers: []*entities.User{bob, charlie},
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name         string
+		template     string
+		status       int
+		expectError  bool
+		expectedText string
+	}{
+		{
+			name:         "Default template mentions reviewers by chat handle",
+			status:       http.StatusOK,
+			expectedText: "<@U0BOB>, <@U0CHARLIE> you have been assigned to review *Add search*",
+		},
+		{
+			name:         "Custom template",
+			template:     `{{.PR.ID}}:{{range .Reviewers}} {{mention .}}{{end}}`,
+			status:       http.StatusOK,
+			expectedText: "pr-1: <@U0BOB> <@U0CHARLIE>",
+		},
+		{
+			name:        "Webhook failure is reported",
+			status:      http.StatusInternalServerError,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received SlackPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("expected application/json content type, got %q", ct)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+					t.Errorf("failed to decode payload: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier, err := NewSlackNotifier(SlackConfig{Template: tt.template}, logger)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = notifier.NotifyReviewersAssigned(context.Background(), newTestEvent(server.URL))
+
+			if tt.expectError && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !strings.Contains(received.Text, tt.expectedText) {
+				t.Errorf("expected text to contain %q, got %q", tt.expectedText, received.Text)
+			}
+		})
+	}
+}
+
+func TestSlackNotifierFallsBackToDefaultWebhook(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := NewSlackNotifier(SlackConfig{DefaultWebhookURL: server.URL}, logger)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := notifier.NotifyReviewersAssigned(context.Background(), newTestEvent("")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 webhook call, got %d", calls)
+	}
+}
+
+func TestSlackMentionWithoutHandle(t *testing.T) {
+	user := entities.NewUser("user1", "alice", "backend", true)
+	if got := SlackMention(user); got != "alice" {
+		t.Errorf("expected plain username, got %q", got)
+	}
+}
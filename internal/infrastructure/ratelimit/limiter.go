@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Window, with bursts of up to Requests.
+// The zero Limit disables limiting.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Window <= 0
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// ratePerSecond is how fast the bucket refills.
+func (l Limit) ratePerSecond() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the bucket is full again.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request is allowed; zero when
+	// the request was allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter takes one token from the bucket identified by key.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func newBucket(limit Limit, now time.Time) bucket {
+	return bucket{tokens: float64(limit.Requests), updatedAt: now}
+}
+
+// take refills the bucket for the time elapsed since its last update and
+// tries to take a token from it.
+func (b bucket) take(limit Limit, now time.Time) (bucket, Result) {
+	rate := limit.ratePerSecond()
+	capacity := float64(limit.Requests)
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	tokens := math.Min(capacity, b.tokens+elapsed*rate)
+
+	result := Result{Limit: limit.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.ResetAfter = seconds((capacity - tokens) / rate)
+
+	return bucket{tokens: tokens, updatedAt: now}, result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// Policy holds the limit applied to each route, keyed by the route pattern
+// as registered on the mux, e.g. "POST /pullRequest/create".
+type Policy struct {
+	Default Limit
+	Routes  map[string]Limit
+}
+
+func (p Policy) For(route string) Limit {
+	if limit, ok := p.Routes[route]; ok {
+		return limit
+	}
+	return p.Default
+}
+
+// ParseLimit parses limits such as "10/s", "100/m" or "1000/h". An empty
+// value or "0" disables limiting.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return Limit{}, nil
+	}
+
+	count, unit, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<s|m|h>", value)
+	}
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: bad request count", value)
+	}
+
+	var window time.Duration
+	switch unit {
+	case "s":
+		window = time.Second
+	case "m":
+		window = time.Minute
+	case "h":
+		window = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid rate limit %q: unknown unit %q", value, unit)
+	}
+	return Limit{Requests: requests, Window: window}, nil
+}
+
+// ParseRoutes parses per-route overrides given as a semicolon-separated list
+// of "<METHOD> <path>=<limit>" entries.
+func ParseRoutes(value string) (map[string]Limit, error) {
+	routes := make(map[string]Limit)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, raw, ok := strings.Cut(entry, "=")
+		route = strings.Join(strings.Fields(route), " ")
+		if !ok || route == "" {
+			return nil, fmt.Errorf("invalid route rate limit %q, expected <route>=<limit>", entry)
+		}
+		limit, err := ParseLimit(raw)
+		if err != nil {
+			return nil, err
+		}
+		routes[route] = limit
+	}
+	return routes, nil
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiterTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemoryLimiter()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	limit := Limit{Requests: 3, Window: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		result, err := limiter.Allow(ctx, "user:1", limit)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Allowed {
+			t.Fatalf("expected burst request to be allowed")
+		}
+		if result.Remaining != i {
+			t.Errorf("expected %d remaining, got %d", i, result.Remaining)
+		}
+	}
+
+	result, _ := limiter.Allow(ctx, "user:1", limit)
+	if result.Allowed {
+		t.Fatal("expected request over the burst to be rejected")
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("expected retry after 1s, got %v", result.RetryAfter)
+	}
+	if result.ResetAfter != 3*time.Second {
+		t.Errorf("expected reset after 3s, got %v", result.ResetAfter)
+	}
+
+	if other, _ := limiter.Allow(ctx, "user:2", limit); !other.Allowed {
+		t.Error("expected buckets to be independent per key")
+	}
+
+	now = now.Add(time.Second)
+	if result, _ := limiter.Allow(ctx, "user:1", limit); !result.Allowed {
+		t.Error("expected one token to be refilled after a second")
+	}
+	if result, _ := limiter.Allow(ctx, "user:1", limit); result.Allowed {
+		t.Error("expected refilled token to be used up")
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("POST /login=5/m; GET  /team/get=10/s;POST /health=0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]Limit{
+		"POST /login":   {Requests: 5, Window: time.Minute},
+		"GET /team/get": {Requests: 10, Window: time.Second},
+		"POST /health":  {},
+	}
+	if len(routes) != len(expected) {
+		t.Fatalf("expected %d routes, got %v", len(expected), routes)
+	}
+	for route, limit := range expected {
+		if routes[route] != limit {
+			t.Errorf("route %q: expected %v, got %v", route, limit, routes[route])
+		}
+	}
+
+	for _, invalid := range []string{"POST /login", "POST /login=5", "POST /login=5/d", "POST /login=x/m"} {
+		if _, err := ParseRoutes(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery controls how often idle buckets are dropped from memory.
+const sweepEvery = 1024
+
+// MemoryLimiter keeps buckets in process memory. Limits are enforced per
+// instance, so use PostgresLimiter when running several replicas.
+type MemoryLimiter struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	calls   int
+}
+
+type memoryBucket struct {
+	bucket
+	// fullAt is when the bucket will have refilled completely.
+	fullAt time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		now:     time.Now,
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: newBucket(limit, now)}
+		l.buckets[key] = b
+	}
+
+	var result Result
+	b.bucket, result = b.take(limit, now)
+	b.fullAt = now.Add(result.ResetAfter)
+	return result, nil
+}
+
+// sweep drops buckets that have refilled; they are recreated full on demand.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !now.Before(b.fullAt) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// PostgresLimiter stores buckets in Postgres so that all replicas share the
+// same limits.
+type PostgresLimiter struct {
+	db     *sql.DB
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewPostgresLimiter(db *sql.DB, logger *slog.Logger) *PostgresLimiter {
+	return &PostgresLimiter{db: db, logger: logger, now: time.Now}
+}
+
+func (l *PostgresLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := l.now().UTC()
+	initial := newBucket(limit, now)
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (key) DO NOTHING
+    `, key, initial.tokens, initial.updatedAt)
+	if err != nil {
+		return Result{}, fmt.Errorf("create rate limit bucket: %w", err)
+	}
+
+	var current bucket
+	err = tx.QueryRowContext(ctx, `
+        SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE
+    `, key).Scan(&current.tokens, &current.updatedAt)
+	if err != nil {
+		return Result{}, fmt.Errorf("lock rate limit bucket: %w", err)
+	}
+
+	next, result := current.take(limit, now)
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1
+    `, key, next.tokens, next.updatedAt)
+	if err != nil {
+		return Result{}, fmt.Errorf("update rate limit bucket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Result{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// Prune deletes buckets untouched since before; they would have refilled
+// completely and are recreated on demand.
+func (l *PostgresLimiter) Prune(ctx context.Context, before time.Time) error {
+	_, err := l.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before.UTC())
+	if err != nil {
+		return fmt.Errorf("prune rate limit buckets: %w", err)
+	}
+	return nil
+}
+
+// Run prunes buckets idle for longer than maxIdle every interval until ctx
+// is cancelled.
+func (l *PostgresLimiter) Run(ctx context.Context, interval, maxIdle time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Prune(ctx, l.now().Add(-maxIdle)); err != nil {
+				l.logger.Warn("failed to prune rate limit buckets", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const assignmentDecisionColumns = `pull_request_id, kind, replaced_reviewer_id, strategy, seed, candidates, excluded, reviewers, created_at`
+
+// execer runs a statement on either the database or an open transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// encodeAssignmentDecision returns the candidates, exclusions and reviewers
+// of a decision as JSON, in the same form as memory snapshots.
+func encodeAssignmentDecision(decision *entities.AssignmentDecision) (candidates, excluded, reviewers string, err error) {
+	stored := make([]SnapshotScoredCandidate, 0, len(decision.Candidates))
+	for _, c := range decision.Candidates {
+		stored = append(stored, SnapshotScoredCandidate{UserID: c.UserID, Score: c.Score})
+	}
+	exclusions := make([]SnapshotExclusion, 0, len(decision.Excluded))
+	for _, e := range decision.Excluded {
+		exclusions = append(exclusions, SnapshotExclusion{UserID: e.UserID, Reason: string(e.Reason)})
+	}
+	chosen := decision.Reviewers
+	if chosen == nil {
+		chosen = []string{}
+	}
+
+	if candidates, err = marshalJSON(stored); err != nil {
+		return "", "", "", fmt.Errorf("encode candidates: %w", err)
+	}
+	if excluded, err = marshalJSON(exclusions); err != nil {
+		return "", "", "", fmt.Errorf("encode exclusions: %w", err)
+	}
+	if reviewers, err = marshalJSON(chosen); err != nil {
+		return "", "", "", fmt.Errorf("encode reviewers: %w", err)
+	}
+	return candidates, excluded, reviewers, nil
+}
+
+func marshalJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// scanAssignmentDecision reads the columns of assignmentDecisionColumns.
+func scanAssignmentDecision(row rowScanner) (*entities.AssignmentDecision, error) {
+	decision := &entities.AssignmentDecision{}
+	var kind string
+	var candidatesJSON, excludedJSON, reviewersJSON []byte
+	err := row.Scan(
+		&decision.PullRequestID,
+		&kind,
+		&decision.ReplacedReviewerID,
+		&decision.Strategy,
+		&decision.Seed,
+		&candidatesJSON,
+		&excludedJSON,
+		&reviewersJSON,
+		&decision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	decision.Kind = entities.AssignmentKind(kind)
+
+	var candidates []SnapshotScoredCandidate
+	var excluded []SnapshotExclusion
+	if err := json.Unmarshal(candidatesJSON, &candidates); err != nil {
+		return nil, fmt.Errorf("decode candidates: %w", err)
+	}
+	if err := json.Unmarshal(excludedJSON, &excluded); err != nil {
+		return nil, fmt.Errorf("decode exclusions: %w", err)
+	}
+	if err := json.Unmarshal(reviewersJSON, &decision.Reviewers); err != nil {
+		return nil, fmt.Errorf("decode reviewers: %w", err)
+	}
+	for _, c := range candidates {
+		decision.Candidates = append(decision.Candidates, entities.ScoredCandidate{UserID: c.UserID, Score: c.Score})
+	}
+	for _, e := range excluded {
+		decision.Excluded = append(decision.Excluded, entities.ReviewerExclusion{UserID: e.UserID, Reason: entities.ExclusionReason(e.Reason)})
+	}
+	return decision, nil
+}
@@ -0,0 +1,46 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories/repositorytest"
+)
+
+func TestInMemoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		store := repositories.NewInMemoryStore()
+		return repositorytest.Repositories{
+			Teams:         store.Teams,
+			Users:         store.Users,
+			PRs:           store.PRs,
+			Credentials:   store.Credentials,
+			RefreshTokens: store.RefreshTokens,
+			APITokens:     store.APITokens,
+			Revocations:   store.Revocations,
+			Idempotency:   store.Idempotency,
+			Decisions:     store.Decisions,
+			DigestRuns:    store.DigestRuns,
+			TeamSync:      repositories.NewInMemoryTeamSyncRepository(store),
+		}
+	})
+}
+
+func TestSQLiteContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db, _ := newSQLiteDB(t)
+		return repositorytest.Repositories{
+			Teams:         repositories.NewSQLiteTeamRepository(db),
+			Users:         repositories.NewSQLiteUserRepository(db),
+			PRs:           repositories.NewSQLitePRRepository(db),
+			Credentials:   repositories.NewSQLiteCredentialRepository(db),
+			RefreshTokens: repositories.NewSQLiteRefreshTokenRepository(db),
+			APITokens:     repositories.NewSQLiteAPITokenRepository(db),
+			Revocations:   repositories.NewSQLiteTokenRevocationRepository(db),
+			Idempotency:   repositories.NewSQLiteIdempotencyRepository(db),
+			Decisions:     repositories.NewSQLiteAssignmentDecisionRepository(db),
+			DigestRuns:    repositories.NewSQLiteDigestRunRepository(db),
+			TeamSync:      repositories.NewSQLiteTeamSyncRepository(db),
+		}
+	})
+}
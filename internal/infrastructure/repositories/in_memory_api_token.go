@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type InMemoryAPITokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]*entities.APIToken
+}
+
+func NewInMemoryAPITokenRepository() ports.APITokenRepository {
+	return &InMemoryAPITokenRepository{
+		tokens: make(map[string]*entities.APIToken),
+	}
+}
+
+func (r *InMemoryAPITokenRepository) Save(ctx context.Context, token *entities.APIToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryAPITokenRepository) GetByID(ctx context.Context, id string) (*entities.APIToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *token
+	return &found, nil
+}
+
+func (r *InMemoryAPITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.APIToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryAPITokenRepository) List(ctx context.Context) ([]*entities.APIToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tokens := make([]*entities.APIToken, 0, len(r.tokens))
+	for _, token := range r.tokens {
+		found := *token
+		tokens = append(tokens, &found)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].ID < tokens[j].ID
+		}
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type InMemoryAssignmentDecisionRepository struct {
+	mu        sync.RWMutex
+	decisions []*entities.AssignmentDecision
+}
+
+func NewInMemoryAssignmentDecisionRepository() ports.AssignmentDecisionRepository {
+	return &InMemoryAssignmentDecisionRepository{}
+}
+
+func (r *InMemoryAssignmentDecisionRepository) Save(ctx context.Context, decision *entities.AssignmentDecision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.decisions = append(r.decisions, copyAssignmentDecision(decision))
+	return nil
+}
+
+func (r *InMemoryAssignmentDecisionRepository) ListByPullRequest(ctx context.Context, prID string) ([]*entities.AssignmentDecision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	decisions := make([]*entities.AssignmentDecision, 0)
+	for _, decision := range r.decisions {
+		if decision.PullRequestID == prID {
+			decisions = append(decisions, copyAssignmentDecision(decision))
+		}
+	}
+	// Decisions are kept in insertion order, which breaks ties.
+	slices.SortStableFunc(decisions, func(a, b *entities.AssignmentDecision) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return decisions, nil
+}
+
+func copyAssignmentDecision(decision *entities.AssignmentDecision) *entities.AssignmentDecision {
+	stored := *decision
+	stored.Candidates = slices.Clone(decision.Candidates)
+	stored.Excluded = slices.Clone(decision.Excluded)
+	stored.Reviewers = slices.Clone(decision.Reviewers)
+	return &stored
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type InMemoryCredentialRepository struct {
+	mu          sync.RWMutex
+	credentials map[string]*entities.Credentials
+}
+
+func NewInMemoryCredentialRepository() ports.CredentialRepository {
+	return &InMemoryCredentialRepository{
+		credentials: make(map[string]*entities.Credentials),
+	}
+}
+
+func (r *InMemoryCredentialRepository) Save(ctx context.Context, credentials *entities.Credentials) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *credentials
+	r.credentials[credentials.UserID] = &stored
+	return nil
+}
+
+func (r *InMemoryCredentialRepository) GetByUserID(ctx context.Context, userID string) (*entities.Credentials, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	credentials, ok := r.credentials[userID]
+	if !ok {
+		return nil, nil
+	}
+	found := *credentials
+	return &found, nil
+}
+
+func (r *InMemoryCredentialRepository) RecordFailedLogin(ctx context.Context, userID string, policy entities.LockoutPolicy, now time.Time) (*entities.Credentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	credentials, ok := r.credentials[userID]
+	if !ok {
+		return nil, nil
+	}
+	credentials.RecordFailedLogin(now, policy)
+	updated := *credentials
+	return &updated, nil
+}
+
+func (r *InMemoryCredentialRepository) ResetFailedLogins(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if credentials, ok := r.credentials[userID]; ok {
+		credentials.RecordSuccessfulLogin()
+	}
+	return nil
+}
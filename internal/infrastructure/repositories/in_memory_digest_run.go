@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+type InMemoryDigestRunRepository struct {
+	mu   sync.Mutex
+	days map[string]struct{}
+}
+
+func NewInMemoryDigestRunRepository() ports.DigestRunRepository {
+	return &InMemoryDigestRunRepository{days: make(map[string]struct{})}
+}
+
+func (r *InMemoryDigestRunRepository) Claim(ctx context.Context, day time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := day.UTC().Format(time.DateOnly)
+	if _, ok := r.days[key]; ok {
+		return false, nil
+	}
+	r.days[key] = struct{}{}
+	return true, nil
+}
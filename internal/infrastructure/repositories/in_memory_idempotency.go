@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type idempotencyKey struct {
+	userID string
+	key    string
+}
+
+type InMemoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]*entities.IdempotencyRecord
+}
+
+func NewInMemoryIdempotencyRepository() ports.IdempotencyRepository {
+	return &InMemoryIdempotencyRepository{
+		records: make(map[idempotencyKey]*entities.IdempotencyRecord),
+	}
+}
+
+func (r *InMemoryIdempotencyRepository) Reserve(ctx context.Context, record *entities.IdempotencyRecord, now time.Time) (*entities.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := idempotencyKey{userID: record.UserID, key: record.Key}
+	if existing, ok := r.records[id]; ok && existing.ExpiresAt.After(now) {
+		found := *existing
+		found.Body = append([]byte(nil), existing.Body...)
+		return &found, nil
+	}
+	stored := *record
+	stored.Body = append([]byte(nil), record.Body...)
+	r.records[id] = &stored
+	return nil, nil
+}
+
+func (r *InMemoryIdempotencyRepository) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.records[idempotencyKey{userID: record.UserID, key: record.Key}]
+	if !ok || stored.Owner != record.Owner {
+		return nil
+	}
+	stored.Complete(record.StatusCode, record.ContentType, append([]byte(nil), record.Body...), record.ExpiresAt)
+	return nil
+}
+
+func (r *InMemoryIdempotencyRepository) Release(ctx context.Context, record *entities.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := idempotencyKey{userID: record.UserID, key: record.Key}
+	if stored, ok := r.records[id]; ok && stored.Owner == record.Owner && !stored.IsComplete() {
+		delete(r.records, id)
+	}
+	return nil
+}
+
+func (r *InMemoryIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, stored := range r.records {
+		if !stored.ExpiresAt.After(now) {
+			delete(r.records, id)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"sync"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
@@ -11,25 +13,44 @@ import (
 type InMemoryPRRepository struct {
 	mu  sync.RWMutex
 	prs map[string]*entities.PullRequest
+	// users resolves author teams, as the join does in the database backends.
+	users     ports.UserRepository
+	decisions ports.AssignmentDecisionRepository
 }
 
-func NewInMemoryPRRepository() ports.PRRepository {
+func NewInMemoryPRRepository(users ports.UserRepository, decisions ports.AssignmentDecisionRepository) ports.PRRepository {
 	return &InMemoryPRRepository{
-		prs: make(map[string]*entities.PullRequest),
+		prs:       make(map[string]*entities.PullRequest),
+		users:     users,
+		decisions: decisions,
 	}
 }
 
 func (r *InMemoryPRRepository) Save(ctx context.Context, pr *entities.PullRequest) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.prs[pr.ID] = pr
+	r.prs[pr.ID] = copyPR(pr)
+	return nil
+}
+
+func (r *InMemoryPRRepository) SaveWithDecision(ctx context.Context, pr *entities.PullRequest, decision *entities.AssignmentDecision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.decisions.Save(ctx, decision); err != nil {
+		return err
+	}
+	r.prs[pr.ID] = copyPR(pr)
 	return nil
 }
 
 func (r *InMemoryPRRepository) GetByID(ctx context.Context, id string) (*entities.PullRequest, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.prs[id], nil
+	pr, ok := r.prs[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyPR(pr), nil
 }
 
 func (r *InMemoryPRRepository) ExistsByID(ctx context.Context, id string) (bool, error) {
@@ -39,17 +60,101 @@ func (r *InMemoryPRRepository) ExistsByID(ctx context.Context, id string) (bool,
 	return exists, nil
 }
 
-func (r *InMemoryPRRepository) GetByReviewer(ctx context.Context, userID string) ([]*entities.PullRequest, error) {
+func (r *InMemoryPRRepository) Find(ctx context.Context, query ports.PRQuery) ([]*entities.PullRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*entities.PullRequest
+	for _, pr := range r.prs {
+		if matchesPRFilter(pr, query) && afterPRCursor(pr, query) {
+			result = append(result, copyPR(pr))
+		}
+	}
+	sortPRs(result)
+	if query.Order == ports.SortDescending {
+		slices.Reverse(result)
+	}
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+func (r *InMemoryPRRepository) Count(ctx context.Context, query ports.PRQuery) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, pr := range r.prs {
+		if matchesPRFilter(pr, query) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InMemoryPRRepository) ListOpen(ctx context.Context) ([]*entities.PullRequest, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	var result []*entities.PullRequest
 	for _, pr := range r.prs {
+		if !pr.IsMerged() {
+			result = append(result, copyPR(pr))
+		}
+	}
+	sortPRs(result)
+	return result, nil
+}
+
+func (r *InMemoryPRRepository) CountOpenByTeam(ctx context.Context) (map[string]int, error) {
+	open, err := r.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, pr := range open {
+		author, err := r.users.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		if author != nil {
+			counts[author.TeamName]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *InMemoryPRRepository) CountOpenReviews(ctx context.Context, teamName string) (map[string]int, error) {
+	members, err := r.users.GetByTeamName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	open, err := r.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, pr := range open {
 		for _, reviewer := range pr.AssignedReviewers {
-			if reviewer == userID {
-				result = append(result, pr)
-				break
+			if slices.ContainsFunc(members, func(u *entities.User) bool { return u.ID == reviewer }) {
+				counts[reviewer]++
 			}
 		}
 	}
-	return result, nil
+	return counts, nil
+}
+
+func copyPR(pr *entities.PullRequest) *entities.PullRequest {
+	found := *pr
+	found.AssignedReviewers = append([]string(nil), pr.AssignedReviewers...)
+	found.Labels = append([]string(nil), pr.Labels...)
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		found.MergedAt = &mergedAt
+	}
+	return &found
+}
+
+// sortPRs orders pull requests the way the SQL backends do: oldest first,
+// ties broken by ID.
+func sortPRs(prs []*entities.PullRequest) {
+	sort.Slice(prs, func(i, j int) bool { return prBefore(prs[i], prs[j].CreatedAt, prs[j].ID) })
 }
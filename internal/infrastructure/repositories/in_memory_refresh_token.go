@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type InMemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]*entities.RefreshToken
+}
+
+func NewInMemoryRefreshTokenRepository() ports.RefreshTokenRepository {
+	return &InMemoryRefreshTokenRepository{
+		tokens: make(map[string]*entities.RefreshToken),
+	}
+}
+
+func (r *InMemoryRefreshTokenRepository) Save(ctx context.Context, token *entities.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryRefreshTokenRepository) Consume(ctx context.Context, tokenHash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			if token.IsRevoked() {
+				return false, nil
+			}
+			token.Revoke(at)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *InMemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID {
+			token.Revoke(now)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	for _, token := range r.tokens {
+		if token.UserID == userID {
+			token.Revoke(now)
+		}
+	}
+	return nil
+}
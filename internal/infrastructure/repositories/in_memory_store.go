@@ -0,0 +1,420 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// InMemoryStore groups the in-memory repositories so that their combined
+// state can be seeded from a fixture and saved to or restored from a
+// snapshot.
+type InMemoryStore struct {
+	Teams         *InMemoryTeamRepository
+	Users         *InMemoryUserRepository
+	PRs           *InMemoryPRRepository
+	Credentials   *InMemoryCredentialRepository
+	RefreshTokens *InMemoryRefreshTokenRepository
+	APITokens     *InMemoryAPITokenRepository
+	Revocations   *InMemoryTokenRevocationRepository
+	Idempotency   *InMemoryIdempotencyRepository
+	Decisions     *InMemoryAssignmentDecisionRepository
+	DigestRuns    *InMemoryDigestRunRepository
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	users := NewInMemoryUserRepository().(*InMemoryUserRepository)
+	decisions := NewInMemoryAssignmentDecisionRepository().(*InMemoryAssignmentDecisionRepository)
+	return &InMemoryStore{
+		Teams:         NewInMemoryTeamRepository(users).(*InMemoryTeamRepository),
+		Users:         users,
+		PRs:           NewInMemoryPRRepository(users, decisions).(*InMemoryPRRepository),
+		Credentials:   NewInMemoryCredentialRepository().(*InMemoryCredentialRepository),
+		RefreshTokens: NewInMemoryRefreshTokenRepository().(*InMemoryRefreshTokenRepository),
+		APITokens:     NewInMemoryAPITokenRepository().(*InMemoryAPITokenRepository),
+		Revocations:   NewInMemoryTokenRevocationRepository().(*InMemoryTokenRevocationRepository),
+		Idempotency:   NewInMemoryIdempotencyRepository().(*InMemoryIdempotencyRepository),
+		Decisions:     decisions,
+		DigestRuns:    NewInMemoryDigestRunRepository().(*InMemoryDigestRunRepository),
+	}
+}
+
+// MemorySnapshot is the JSON form of an InMemoryStore. Fixtures use the same
+// format and usually fill in only teams and pull requests.
+type MemorySnapshot struct {
+	Teams         []SnapshotTeam         `json:"teams"`
+	PullRequests  []SnapshotPullRequest  `json:"pull_requests,omitempty"`
+	Credentials   []SnapshotCredentials  `json:"credentials,omitempty"`
+	APITokens     []SnapshotAPIToken     `json:"api_tokens,omitempty"`
+	RefreshTokens []SnapshotRefreshToken `json:"refresh_tokens,omitempty"`
+	RevokedTokens map[string]time.Time   `json:"revoked_tokens,omitempty"`
+	RevokedUsers  map[string]time.Time   `json:"revoked_users,omitempty"`
+	// AssignmentDecisions are in the order they were made.
+	AssignmentDecisions []SnapshotAssignmentDecision `json:"assignment_decisions,omitempty"`
+}
+
+type SnapshotTeam struct {
+	Name           string         `json:"team_name"`
+	ChatWebhookURL string         `json:"chat_webhook_url,omitempty"`
+	Members        []SnapshotUser `json:"members"`
+}
+
+type SnapshotUser struct {
+	ID                      string `json:"user_id"`
+	Username                string `json:"username"`
+	IsActive                bool   `json:"is_active"`
+	OnLeave                 bool   `json:"on_leave,omitempty"`
+	Role                    string `json:"role,omitempty"`
+	ChatHandle              string `json:"chat_handle,omitempty"`
+	Email                   string `json:"email,omitempty"`
+	NotifyEmailOnAssignment bool   `json:"notify_email_on_assignment,omitempty"`
+	NotifyEmailDigest       bool   `json:"notify_email_digest,omitempty"`
+	// Password lets fixtures give demo users a login. It is hashed into
+	// Credentials before the snapshot is restored and never written back.
+	Password string `json:"password,omitempty"`
+}
+
+type SnapshotPullRequest struct {
+	ID                string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status,omitempty"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	Labels            []string   `json:"labels,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+}
+
+type SnapshotCredentials struct {
+	UserID         string     `json:"user_id"`
+	PasswordHash   string     `json:"password_hash"`
+	FailedAttempts int        `json:"failed_attempts,omitempty"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type SnapshotAPIToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	UserID    string     `json:"user_id"`
+	TokenHash string     `json:"token_hash"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type SnapshotRefreshToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type SnapshotAssignmentDecision struct {
+	PullRequestID      string                    `json:"pull_request_id"`
+	Kind               string                    `json:"kind"`
+	ReplacedReviewerID string                    `json:"replaced_reviewer_id,omitempty"`
+	Strategy           string                    `json:"strategy"`
+	Seed               int64                     `json:"seed"`
+	Candidates         []SnapshotScoredCandidate `json:"candidates"`
+	Excluded           []SnapshotExclusion       `json:"excluded"`
+	Reviewers          []string                  `json:"reviewers"`
+	CreatedAt          time.Time                 `json:"created_at"`
+}
+
+type SnapshotScoredCandidate struct {
+	UserID string  `json:"user_id"`
+	Score  float64 `json:"score"`
+}
+
+type SnapshotExclusion struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// Snapshot copies the current state. Team membership comes from the user
+// repository.
+func (s *InMemoryStore) Snapshot() *MemorySnapshot {
+	snapshot := &MemorySnapshot{
+		RevokedTokens: make(map[string]time.Time),
+		RevokedUsers:  make(map[string]time.Time),
+	}
+
+	s.Teams.mu.RLock()
+	teams := make(map[string]*SnapshotTeam, len(s.Teams.teams))
+	for name, team := range s.Teams.teams {
+		teams[name] = &SnapshotTeam{Name: name, ChatWebhookURL: team.ChatWebhookURL, Members: []SnapshotUser{}}
+	}
+	s.Teams.mu.RUnlock()
+
+	s.Users.mu.RLock()
+	for _, user := range s.Users.users {
+		team, ok := teams[user.TeamName]
+		if !ok {
+			team = &SnapshotTeam{Name: user.TeamName}
+			teams[user.TeamName] = team
+		}
+		team.Members = append(team.Members, SnapshotUser{
+			ID:                      user.ID,
+			Username:                user.Username,
+			IsActive:                user.IsActive,
+			OnLeave:                 user.OnLeave,
+			Role:                    userRole(user),
+			ChatHandle:              user.ChatHandle,
+			Email:                   user.Email,
+			NotifyEmailOnAssignment: user.Notifications.EmailOnAssignment,
+			NotifyEmailDigest:       user.Notifications.EmailDigest,
+		})
+	}
+	s.Users.mu.RUnlock()
+
+	for _, team := range teams {
+		sort.Slice(team.Members, func(i, j int) bool { return team.Members[i].ID < team.Members[j].ID })
+		snapshot.Teams = append(snapshot.Teams, *team)
+	}
+	sort.Slice(snapshot.Teams, func(i, j int) bool { return snapshot.Teams[i].Name < snapshot.Teams[j].Name })
+
+	s.PRs.mu.RLock()
+	for _, pr := range s.PRs.prs {
+		snapshot.PullRequests = append(snapshot.PullRequests, SnapshotPullRequest{
+			ID:                pr.ID,
+			Name:              pr.Name,
+			AuthorID:          pr.AuthorID,
+			Status:            pr.Status.String(),
+			AssignedReviewers: append([]string{}, pr.AssignedReviewers...),
+			Labels:            slices.Clone(pr.Labels),
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+		})
+	}
+	s.PRs.mu.RUnlock()
+	sort.Slice(snapshot.PullRequests, func(i, j int) bool { return snapshot.PullRequests[i].ID < snapshot.PullRequests[j].ID })
+
+	s.Credentials.mu.RLock()
+	for _, c := range s.Credentials.credentials {
+		snapshot.Credentials = append(snapshot.Credentials, SnapshotCredentials{
+			UserID:         c.UserID,
+			PasswordHash:   c.PasswordHash,
+			FailedAttempts: c.FailedAttempts,
+			LockedUntil:    c.LockedUntil,
+			UpdatedAt:      c.UpdatedAt,
+		})
+	}
+	s.Credentials.mu.RUnlock()
+	sort.Slice(snapshot.Credentials, func(i, j int) bool { return snapshot.Credentials[i].UserID < snapshot.Credentials[j].UserID })
+
+	s.APITokens.mu.RLock()
+	for _, t := range s.APITokens.tokens {
+		scopes := make([]string, 0, len(t.Scopes))
+		for _, scope := range t.Scopes {
+			scopes = append(scopes, scope.String())
+		}
+		snapshot.APITokens = append(snapshot.APITokens, SnapshotAPIToken{
+			ID:        t.ID,
+			Name:      t.Name,
+			UserID:    t.UserID,
+			TokenHash: t.TokenHash,
+			Scopes:    scopes,
+			CreatedAt: t.CreatedAt,
+			ExpiresAt: t.ExpiresAt,
+			RevokedAt: t.RevokedAt,
+		})
+	}
+	s.APITokens.mu.RUnlock()
+	sort.Slice(snapshot.APITokens, func(i, j int) bool { return snapshot.APITokens[i].ID < snapshot.APITokens[j].ID })
+
+	s.RefreshTokens.mu.RLock()
+	for _, t := range s.RefreshTokens.tokens {
+		snapshot.RefreshTokens = append(snapshot.RefreshTokens, SnapshotRefreshToken{
+			ID:        t.ID,
+			UserID:    t.UserID,
+			FamilyID:  t.FamilyID,
+			TokenHash: t.TokenHash,
+			ExpiresAt: t.ExpiresAt,
+			CreatedAt: t.CreatedAt,
+			RevokedAt: t.RevokedAt,
+		})
+	}
+	s.RefreshTokens.mu.RUnlock()
+	sort.Slice(snapshot.RefreshTokens, func(i, j int) bool { return snapshot.RefreshTokens[i].ID < snapshot.RefreshTokens[j].ID })
+
+	s.Revocations.mu.RLock()
+	for jti, expiresAt := range s.Revocations.denylist.Tokens {
+		snapshot.RevokedTokens[jti] = expiresAt
+	}
+	for userID, cutoff := range s.Revocations.denylist.Users {
+		snapshot.RevokedUsers[userID] = cutoff
+	}
+	s.Revocations.mu.RUnlock()
+
+	s.Decisions.mu.RLock()
+	for _, d := range s.Decisions.decisions {
+		decision := SnapshotAssignmentDecision{
+			PullRequestID:      d.PullRequestID,
+			Kind:               string(d.Kind),
+			ReplacedReviewerID: d.ReplacedReviewerID,
+			Strategy:           d.Strategy,
+			Seed:               d.Seed,
+			Candidates:         make([]SnapshotScoredCandidate, 0, len(d.Candidates)),
+			Excluded:           make([]SnapshotExclusion, 0, len(d.Excluded)),
+			Reviewers:          append([]string{}, d.Reviewers...),
+			CreatedAt:          d.CreatedAt,
+		}
+		for _, c := range d.Candidates {
+			decision.Candidates = append(decision.Candidates, SnapshotScoredCandidate{UserID: c.UserID, Score: c.Score})
+		}
+		for _, e := range d.Excluded {
+			decision.Excluded = append(decision.Excluded, SnapshotExclusion{UserID: e.UserID, Reason: string(e.Reason)})
+		}
+		snapshot.AssignmentDecisions = append(snapshot.AssignmentDecisions, decision)
+	}
+	s.Decisions.mu.RUnlock()
+
+	return snapshot
+}
+
+// Restore loads a snapshot or fixture into the store through the regular
+// repository methods. Unset pull request statuses default to OPEN and unset
+// creation times to now.
+func (s *InMemoryStore) Restore(ctx context.Context, snapshot *MemorySnapshot) error {
+	for _, t := range snapshot.Teams {
+		team := entities.NewTeam(t.Name, nil)
+		if team == nil {
+			return fmt.Errorf("team without a name")
+		}
+		team.ChatWebhookURL = t.ChatWebhookURL
+
+		for _, m := range t.Members {
+			user := entities.NewUser(m.ID, m.Username, t.Name, m.IsActive)
+			user.SetOnLeave(m.OnLeave)
+			if m.Role != "" {
+				role, err := entities.ParseRole(m.Role)
+				if err != nil {
+					return fmt.Errorf("user %s: %w", m.ID, err)
+				}
+				user.SetRole(role)
+			}
+			user.ChatHandle = m.ChatHandle
+			user.SetNotificationPreferences(m.Email, entities.NotificationPreferences{
+				EmailOnAssignment: m.NotifyEmailOnAssignment,
+				EmailDigest:       m.NotifyEmailDigest,
+			})
+			if err := team.AddMember(user); err != nil {
+				return fmt.Errorf("team %s: user %s: %w", t.Name, m.ID, err)
+			}
+		}
+
+		if err := s.Teams.Save(ctx, team); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range snapshot.PullRequests {
+		status := entities.PRStatusOpen
+		if p.Status != "" {
+			var err error
+			if status, err = entities.ParsePRStatus(p.Status); err != nil {
+				return fmt.Errorf("pull request %s: %w", p.ID, err)
+			}
+		}
+		createdAt := p.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now().UTC()
+		}
+		pr := &entities.PullRequest{
+			ID:                p.ID,
+			Name:              p.Name,
+			AuthorID:          p.AuthorID,
+			Status:            status,
+			AssignedReviewers: p.AssignedReviewers,
+			CreatedAt:         createdAt,
+			MergedAt:          p.MergedAt,
+		}
+		pr.SetLabels(p.Labels)
+		if err := s.PRs.Save(ctx, pr); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range snapshot.Credentials {
+		err := s.Credentials.Save(ctx, &entities.Credentials{
+			UserID:         c.UserID,
+			PasswordHash:   c.PasswordHash,
+			FailedAttempts: c.FailedAttempts,
+			LockedUntil:    c.LockedUntil,
+			UpdatedAt:      c.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range snapshot.APITokens {
+		scopes, err := entities.ParseScopes(t.Scopes)
+		if err != nil {
+			return fmt.Errorf("api token %s: %w", t.ID, err)
+		}
+		token := entities.NewAPIToken(t.ID, t.Name, t.UserID, t.TokenHash, scopes, t.CreatedAt, t.ExpiresAt)
+		token.RevokedAt = t.RevokedAt
+		if err := s.APITokens.Save(ctx, token); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range snapshot.RefreshTokens {
+		err := s.RefreshTokens.Save(ctx, &entities.RefreshToken{
+			ID:        t.ID,
+			UserID:    t.UserID,
+			FamilyID:  t.FamilyID,
+			TokenHash: t.TokenHash,
+			ExpiresAt: t.ExpiresAt,
+			CreatedAt: t.CreatedAt,
+			RevokedAt: t.RevokedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for jti, expiresAt := range snapshot.RevokedTokens {
+		if err := s.Revocations.RevokeToken(ctx, jti, "", expiresAt); err != nil {
+			return err
+		}
+	}
+	for userID, cutoff := range snapshot.RevokedUsers {
+		if err := s.Revocations.RevokeUser(ctx, userID, cutoff); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range snapshot.AssignmentDecisions {
+		decision := &entities.AssignmentDecision{
+			PullRequestID:      d.PullRequestID,
+			Kind:               entities.AssignmentKind(d.Kind),
+			ReplacedReviewerID: d.ReplacedReviewerID,
+			Strategy:           d.Strategy,
+			Seed:               d.Seed,
+			Reviewers:          d.Reviewers,
+			CreatedAt:          d.CreatedAt,
+		}
+		for _, c := range d.Candidates {
+			decision.Candidates = append(decision.Candidates, entities.ScoredCandidate{UserID: c.UserID, Score: c.Score})
+		}
+		for _, e := range d.Excluded {
+			decision.Excluded = append(decision.Excluded, entities.ReviewerExclusion{UserID: e.UserID, Reason: entities.ExclusionReason(e.Reason)})
+		}
+		if err := s.Decisions.Save(ctx, decision); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package repositories_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+)
+
+const memoryFixture = `{
+  "teams": [
+    {
+      "team_name": "backend",
+      "members": [
+        {"user_id": "u1", "username": "alice", "is_active": true, "role": "team_lead"},
+        {"user_id": "u2", "username": "bob", "is_active": true}
+      ]
+    }
+  ],
+  "pull_requests": [
+    {"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1", "assigned_reviewers": ["u2"]}
+  ]
+}`
+
+func TestInMemoryStoreRestoresFixture(t *testing.T) {
+	ctx := context.Background()
+
+	var fixture repositories.MemorySnapshot
+	if err := json.Unmarshal([]byte(memoryFixture), &fixture); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	store := repositories.NewInMemoryStore()
+	if err := store.Restore(ctx, &fixture); err != nil {
+		t.Fatalf("failed to restore fixture: %v", err)
+	}
+
+	team, err := store.Teams.GetByName(ctx, "backend")
+	if err != nil || team == nil || len(team.Members) != 2 {
+		t.Fatalf("unexpected team: %+v (%v)", team, err)
+	}
+	alice, err := store.Users.GetByID(ctx, "u1")
+	if err != nil || alice.Role != entities.RoleTeamLead {
+		t.Fatalf("unexpected user: %+v (%v)", alice, err)
+	}
+	pr, err := store.PRs.GetByID(ctx, "pr-1")
+	if err != nil || pr.Status != entities.PRStatusOpen || pr.CreatedAt.IsZero() {
+		t.Fatalf("unexpected pr: %+v (%v)", pr, err)
+	}
+}
+
+func TestInMemoryStoreSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	store := repositories.NewInMemoryStore()
+	alice := entities.NewUser("u1", "alice", "backend", true)
+	bob := entities.NewUser("u2", "bob", "backend", true)
+	for _, user := range []*entities.User{alice, bob} {
+		if err := store.Users.Save(ctx, user); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+	if err := store.Teams.Save(ctx, entities.NewTeam("backend", []*entities.User{alice, bob})); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	pr := entities.NewPullRequest("pr-1", "Add search", "u1", []string{"u2"})
+	pr.Merge()
+	if err := store.PRs.Save(ctx, pr); err != nil {
+		t.Fatalf("failed to save pr: %v", err)
+	}
+	if err := store.Credentials.Save(ctx, entities.NewCredentials("u1", "hash")); err != nil {
+		t.Fatalf("failed to save credentials: %v", err)
+	}
+	token := entities.NewAPIToken("t1", "ci", "u1", "token-hash", []entities.Scope{entities.ScopePRWrite}, now, nil)
+	if err := store.APITokens.Save(ctx, token); err != nil {
+		t.Fatalf("failed to save token: %v", err)
+	}
+	if err := store.Revocations.RevokeUser(ctx, "u2", now); err != nil {
+		t.Fatalf("failed to revoke user: %v", err)
+	}
+	decision := &entities.AssignmentDecision{
+		PullRequestID: "pr-1",
+		Kind:          entities.AssignmentCreate,
+		Strategy:      "random",
+		Seed:          42,
+		Candidates:    []entities.ScoredCandidate{{UserID: "u2", Score: 0.25}},
+		Excluded:      []entities.ReviewerExclusion{{UserID: "u1", Reason: entities.ExclusionAuthor}},
+		Reviewers:     []string{"u2"},
+		CreatedAt:     now,
+	}
+	if err := store.Decisions.Save(ctx, decision); err != nil {
+		t.Fatalf("failed to save decision: %v", err)
+	}
+
+	data, err := json.Marshal(store.Snapshot())
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+	var snapshot repositories.MemorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+
+	restored := repositories.NewInMemoryStore()
+	if err := restored.Restore(ctx, &snapshot); err != nil {
+		t.Fatalf("failed to restore snapshot: %v", err)
+	}
+
+	got, err := restored.PRs.GetByID(ctx, "pr-1")
+	if err != nil || got.Status != entities.PRStatusMerged || got.MergedAt == nil {
+		t.Fatalf("unexpected pr: %+v (%v)", got, err)
+	}
+	if c, err := restored.Credentials.GetByUserID(ctx, "u1"); err != nil || c == nil || c.PasswordHash != "hash" {
+		t.Errorf("unexpected credentials: %+v (%v)", c, err)
+	}
+	if tok, err := restored.APITokens.GetByHash(ctx, "token-hash"); err != nil || tok == nil || !tok.HasScope(entities.ScopePRWrite) {
+		t.Errorf("unexpected token: %+v (%v)", tok, err)
+	}
+	denylist, err := restored.Revocations.Load(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to load denylist: %v", err)
+	}
+	if !denylist.IsRevoked("any", "u2", now.Add(-time.Minute)) {
+		t.Error("expected user revocation to survive the round trip")
+	}
+	decisions, err := restored.Decisions.ListByPullRequest(ctx, "pr-1")
+	if err != nil || len(decisions) != 1 || !reflect.DeepEqual(decisions[0], decision) {
+		t.Errorf("unexpected decisions: %+v (%v)", decisions, err)
+	}
+}
@@ -2,34 +2,58 @@ package repositories
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
+// InMemoryTeamRepository keeps team records only; members are saved to and
+// read from the user repository, as they are in the database backends.
 type InMemoryTeamRepository struct {
 	mu    sync.RWMutex
 	teams map[string]*entities.Team
+	users ports.UserRepository
 }
 
-func NewInMemoryTeamRepository() ports.TeamRepository {
+func NewInMemoryTeamRepository(users ports.UserRepository) ports.TeamRepository {
 	return &InMemoryTeamRepository{
 		teams: make(map[string]*entities.Team),
+		users: users,
 	}
 }
 
 func (r *InMemoryTeamRepository) Save(ctx context.Context, team *entities.Team) error {
+	for _, member := range team.Members {
+		if err := r.users.Save(ctx, member); err != nil {
+			return err
+		}
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.teams[team.Name] = team
+	r.teams[team.Name] = &entities.Team{Name: team.Name, ChatWebhookURL: team.ChatWebhookURL}
 	return nil
 }
 
 func (r *InMemoryTeamRepository) GetByName(ctx context.Context, name string) (*entities.Team, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return r.teams[name], nil
+	stored, ok := r.teams[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	members, err := r.users.GetByTeamName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.Team{
+		Name:           stored.Name,
+		ChatWebhookURL: stored.ChatWebhookURL,
+		Members:        append(make([]*entities.User, 0, len(members)), members...),
+	}, nil
 }
 
 func (r *InMemoryTeamRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
@@ -38,3 +62,25 @@ func (r *InMemoryTeamRepository) ExistsByName(ctx context.Context, name string)
 	_, exists := r.teams[name]
 	return exists, nil
 }
+
+func (r *InMemoryTeamRepository) List(ctx context.Context) ([]*entities.Team, error) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.teams))
+	for name := range r.teams {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	slices.Sort(names)
+
+	teams := make([]*entities.Team, 0, len(names))
+	for _, name := range names {
+		team, err := r.GetByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if team != nil {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// InMemoryTeamSyncRepository applies plans to an InMemoryStore. Removing a
+// user also drops their credentials and tokens, as the database cascades do.
+// Unlike the database backends it does not roll back a partly applied plan.
+type InMemoryTeamSyncRepository struct {
+	store *InMemoryStore
+}
+
+func NewInMemoryTeamSyncRepository(store *InMemoryStore) ports.TeamSyncRepository {
+	return &InMemoryTeamSyncRepository{store: store}
+}
+
+func (r *InMemoryTeamSyncRepository) Apply(ctx context.Context, plan *entities.TeamSyncPlan) error {
+	teams := r.store.Teams
+	teams.mu.Lock()
+	for _, name := range plan.CreateTeams {
+		if _, ok := teams.teams[name]; !ok {
+			teams.teams[name] = &entities.Team{Name: name}
+		}
+	}
+	teams.mu.Unlock()
+
+	for _, user := range plan.Saves() {
+		if err := r.store.Users.Save(ctx, user); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range plan.Remove {
+		if !r.hasPRHistory(user.ID) {
+			r.deleteUser(user.ID)
+			continue
+		}
+		r.store.Users.mu.Lock()
+		if stored, ok := r.store.Users.users[user.ID]; ok {
+			stored.SetActive(false)
+		}
+		r.store.Users.mu.Unlock()
+	}
+
+	for _, name := range plan.DeleteTeams {
+		members, err := r.store.Users.GetByTeamName(ctx, name)
+		if err != nil {
+			return err
+		}
+		teams.mu.Lock()
+		_, ok := teams.teams[name]
+		if ok && len(members) == 0 {
+			delete(teams.teams, name)
+		}
+		teams.mu.Unlock()
+		if !ok || len(members) > 0 {
+			return fmt.Errorf("delete team %s: team is missing or still has members", name)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryTeamSyncRepository) hasPRHistory(userID string) bool {
+	prs := r.store.PRs
+	prs.mu.RLock()
+	defer prs.mu.RUnlock()
+	for _, pr := range prs.prs {
+		if pr.AuthorID == userID || slices.Contains(pr.AssignedReviewers, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *InMemoryTeamSyncRepository) deleteUser(userID string) {
+	users := r.store.Users
+	users.mu.Lock()
+	delete(users.users, userID)
+	users.mu.Unlock()
+
+	credentials := r.store.Credentials
+	credentials.mu.Lock()
+	delete(credentials.credentials, userID)
+	credentials.mu.Unlock()
+
+	apiTokens := r.store.APITokens
+	apiTokens.mu.Lock()
+	for key, token := range apiTokens.tokens {
+		if token.UserID == userID {
+			delete(apiTokens.tokens, key)
+		}
+	}
+	apiTokens.mu.Unlock()
+
+	refreshTokens := r.store.RefreshTokens
+	refreshTokens.mu.Lock()
+	for key, token := range refreshTokens.tokens {
+		if token.UserID == userID {
+			delete(refreshTokens.tokens, key)
+		}
+	}
+	refreshTokens.mu.Unlock()
+}
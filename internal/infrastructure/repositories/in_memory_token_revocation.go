@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type InMemoryTokenRevocationRepository struct {
+	mu       sync.RWMutex
+	denylist *entities.TokenDenylist
+}
+
+func NewInMemoryTokenRevocationRepository() ports.TokenRevocationRepository {
+	return &InMemoryTokenRevocationRepository{
+		denylist: entities.NewTokenDenylist(),
+	}
+}
+
+func (r *InMemoryTokenRevocationRepository) RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.denylist.RevokeToken(jti, expiresAt)
+	return nil
+}
+
+func (r *InMemoryTokenRevocationRepository) RevokeUser(ctx context.Context, userID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.denylist.RevokeUser(userID, revokedAt)
+	return nil
+}
+
+func (r *InMemoryTokenRevocationRepository) Load(ctx context.Context, now time.Time) (*entities.TokenDenylist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	denylist := entities.NewTokenDenylist()
+	for jti, expiresAt := range r.denylist.Tokens {
+		if now.Before(expiresAt) {
+			denylist.RevokeToken(jti, expiresAt)
+		}
+	}
+	for userID, cutoff := range r.denylist.Users {
+		denylist.RevokeUser(userID, cutoff)
+	}
+	return denylist, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
@@ -22,14 +23,20 @@ func NewInMemoryUserRepository() ports.UserRepository {
 func (r *InMemoryUserRepository) Save(ctx context.Context, user *entities.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.users[user.ID] = user
+	stored := *user
+	r.users[user.ID] = &stored
 	return nil
 }
 
 func (r *InMemoryUserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.users[id], nil
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *user
+	return &found, nil
 }
 
 func (r *InMemoryUserRepository) ExistsByID(ctx context.Context, id string) (bool, error) {
@@ -45,8 +52,22 @@ func (r *InMemoryUserRepository) GetByTeamName(ctx context.Context, teamName str
 	var result []*entities.User
 	for _, user := range r.users {
 		if user.TeamName == teamName {
-			result = append(result, user)
+			found := *user
+			result = append(result, &found)
 		}
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *InMemoryUserRepository) List(ctx context.Context) ([]*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*entities.User, 0, len(r.users))
+	for _, user := range r.users {
+		found := *user
+		result = append(result, &found)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
 	return result, nil
 }
@@ -3,51 +3,279 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lib/pq"
 )
 
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// schemaLockID is the advisory lock held while an instance checks and
+// upgrades the schema at startup. It differs from the lock golang-migrate
+// takes around each run, so MigrateTo can be called while holding it.
+var schemaLockID = int64(crc32.ChecksumIEEE([]byte("pr-reviewer:schema")))
+
+// PostgresMigrationRepository runs the migrations found in source, normally
+// the set embedded in the binary. It opens a dedicated connection for each
+// migration run instead of pinning one from the pool for its lifetime.
 type PostgresMigrationRepository struct {
-	migrate *migrate.Migrate
+	migrationSet
+	db *sql.DB
 }
 
-func NewPostgresMigrationRepository(db *sql.DB, migrationsPath string) (ports.MigrationRepository, error) {
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+func NewPostgresMigrationRepository(db *sql.DB, source fs.FS) (ports.MigrationRepository, error) {
+	set, err := newMigrationSet(source)
 	if err != nil {
 		return nil, err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrationsPath,
-		"postgres",
-		driver,
-	)
+	return &PostgresMigrationRepository{
+		migrationSet: set,
+		db:           db,
+	}, nil
+}
+
+func (r *PostgresMigrationRepository) MigrateTo(ctx context.Context, version uint) error {
+	return r.withMigrate(ctx, func(m *migrate.Migrate) error {
+		var err error
+		if version == 0 {
+			err = m.Down()
+		} else {
+			err = m.Migrate(version)
+		}
+		if err != nil && err != migrate.ErrNoChange {
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *PostgresMigrationRepository) Force(ctx context.Context, version int) error {
+	return r.withMigrate(ctx, func(m *migrate.Migrate) error {
+		return m.Force(version)
+	})
+}
+
+// GetCurrentVersion reads the version table directly; it reports version 0
+// when no migration has been applied yet.
+func (r *PostgresMigrationRepository) GetCurrentVersion(ctx context.Context) (uint, bool, error) {
+	var version int64
+	var dirty bool
+	err := r.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "undefined_table" {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("query migration version: %w", err)
+	}
+	if version < 0 {
+		return 0, dirty, nil
+	}
+	return uint(version), dirty, nil
+}
+
+func (r *PostgresMigrationRepository) Plan(ctx context.Context, version uint) ([]ports.MigrationStep, error) {
+	current, _, err := r.GetCurrentVersion(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return r.plan(current, version)
+}
 
-	return &PostgresMigrationRepository{
-		migrate: m,
+func (r *PostgresMigrationRepository) Lock(ctx context.Context) (func(), error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, schemaLockID); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("acquire schema lock: %w", err)
+	}
+
+	return func() {
+		// The lock belongs to the session, so closing the connection would
+		// release it too; unlock explicitly in case the pool keeps it open.
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, schemaLockID)
+		conn.Close()
 	}, nil
 }
 
-func (r *PostgresMigrationRepository) ApplyMigrations(ctx context.Context) error {
-	if err := r.migrate.Up(); err != nil && err != migrate.ErrNoChange {
-		return err
+func (r *PostgresMigrationRepository) withMigrate(ctx context.Context, fn func(*migrate.Migrate) error) error {
+	src, err := iofs.New(r.source, ".")
+	if err != nil {
+		return fmt.Errorf("open migrations: %w", err)
+	}
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		src.Close()
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		src.Close()
+		conn.Close()
+		return fmt.Errorf("create migration driver: %w", err)
 	}
-	return nil
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return fmt.Errorf("create migrator: %w", err)
+	}
+	defer m.Close()
+
+	return fn(m)
+}
+
+// migrationSet reads the migration files of one backend; both migration
+// repositories embed it.
+type migrationSet struct {
+	source fs.FS
 }
 
-func (r *PostgresMigrationRepository) RollbackMigrations(ctx context.Context) error {
-	if err := r.migrate.Down(); err != nil && err != migrate.ErrNoChange {
-		return err
+func newMigrationSet(source fs.FS) (migrationSet, error) {
+	if _, err := listMigrationFiles(source); err != nil {
+		return migrationSet{}, err
 	}
-	return nil
+	return migrationSet{source: source}, nil
 }
 
-func (r *PostgresMigrationRepository) GetCurrentVersion(ctx context.Context) (uint, bool, error) {
-	return r.migrate.Version()
+func (s migrationSet) GetLatestVersion(ctx context.Context) (uint, error) {
+	migrations, err := s.ListMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+func (s migrationSet) ListMigrations(ctx context.Context) ([]ports.Migration, error) {
+	files, err := listMigrationFiles(s.source)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]ports.Migration, 0, len(files))
+	for _, f := range files {
+		migrations = append(migrations, f.Migration)
+	}
+	return migrations, nil
+}
+
+// plan returns the steps that take the schema from current to version.
+func (s migrationSet) plan(current, version uint) ([]ports.MigrationStep, error) {
+	files, err := listMigrationFiles(s.source)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && !containsVersion(files, version) {
+		return nil, fmt.Errorf("migration %d does not exist", version)
+	}
+
+	var selected []migrationFile
+	direction := ports.MigrationUp
+	switch {
+	case version > current:
+		for _, f := range files {
+			if f.Version > current && f.Version <= version {
+				selected = append(selected, f)
+			}
+		}
+	case version < current:
+		direction = ports.MigrationDown
+		for i := len(files) - 1; i >= 0; i-- {
+			if files[i].Version > version && files[i].Version <= current {
+				selected = append(selected, files[i])
+			}
+		}
+	}
+
+	steps := make([]ports.MigrationStep, 0, len(selected))
+	for _, f := range selected {
+		name := f.up
+		if direction == ports.MigrationDown {
+			name = f.down
+		}
+		if name == "" {
+			return nil, fmt.Errorf("migration %d has no %s file", f.Version, direction)
+		}
+		content, err := fs.ReadFile(s.source, name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+		steps = append(steps, ports.MigrationStep{Migration: f.Migration, Direction: direction, SQL: string(content)})
+	}
+	return steps, nil
+}
+
+type migrationFile struct {
+	ports.Migration
+	up   string
+	down string
+}
+
+func listMigrationFiles(source fs.FS) ([]migrationFile, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*migrationFile)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		f, ok := byVersion[uint(version)]
+		if !ok {
+			f = &migrationFile{Migration: ports.Migration{Version: uint(version), Name: match[2]}}
+			byVersion[uint(version)] = f
+		}
+		if match[3] == "up" {
+			f.up = entry.Name()
+		} else {
+			f.down = entry.Name()
+		}
+	}
+
+	files := make([]migrationFile, 0, len(byVersion))
+	for _, f := range byVersion {
+		files = append(files, *f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+	return files, nil
+}
+
+func containsVersion(files []migrationFile, version uint) bool {
+	for _, f := range files {
+		if f.Version == version {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/lib/pq"
+)
+
+const apiTokenColumns = `id, name, user_id, token_hash, scopes, created_at, expires_at, revoked_at`
+
+type PostgresAPITokenRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAPITokenRepository(db *sql.DB) ports.APITokenRepository {
+	return &PostgresAPITokenRepository{db: db}
+}
+
+func scanAPIToken(row rowScanner) (*entities.APIToken, error) {
+	var scopes []string
+	token := &entities.APIToken{}
+	err := row.Scan(
+		&token.ID,
+		&token.Name,
+		&token.UserID,
+		&token.TokenHash,
+		pq.Array(&scopes),
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes, err = entities.ParseScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *PostgresAPITokenRepository) Save(ctx context.Context, token *entities.APIToken) error {
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, scope.String())
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO api_tokens (`+apiTokenColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (id) DO UPDATE SET revoked_at = EXCLUDED.revoked_at
+    `, token.ID, token.Name, token.UserID, token.TokenHash, pq.Array(scopes),
+		token.CreatedAt, token.ExpiresAt, token.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("save api token: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresAPITokenRepository) GetByID(ctx context.Context, id string) (*entities.APIToken, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT `+apiTokenColumns+`
+        FROM api_tokens
+        WHERE id = $1
+    `, id)
+	return r.scanOne(row)
+}
+
+func (r *PostgresAPITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.APIToken, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT `+apiTokenColumns+`
+        FROM api_tokens
+        WHERE token_hash = $1
+    `, tokenHash)
+	return r.scanOne(row)
+}
+
+func (r *PostgresAPITokenRepository) List(ctx context.Context) ([]*entities.APIToken, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+apiTokenColumns+`
+        FROM api_tokens
+        ORDER BY created_at, id
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("query api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*entities.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *PostgresAPITokenRepository) scanOne(row *sql.Row) (*entities.APIToken, error) {
+	token, err := scanAPIToken(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query api token: %w", err)
+	}
+	return token, nil
+}
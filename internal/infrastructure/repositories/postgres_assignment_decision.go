@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type PostgresAssignmentDecisionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAssignmentDecisionRepository(db *sql.DB) ports.AssignmentDecisionRepository {
+	return &PostgresAssignmentDecisionRepository{db: db}
+}
+
+func (r *PostgresAssignmentDecisionRepository) Save(ctx context.Context, decision *entities.AssignmentDecision) error {
+	return insertPostgresAssignmentDecision(ctx, r.db, decision)
+}
+
+func (r *PostgresAssignmentDecisionRepository) ListByPullRequest(ctx context.Context, prID string) ([]*entities.AssignmentDecision, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+assignmentDecisionColumns+`
+        FROM assignment_decisions
+        WHERE pull_request_id = $1
+        ORDER BY created_at, id
+    `, prID)
+	if err != nil {
+		return nil, fmt.Errorf("query assignment decisions: %w", err)
+	}
+	defer rows.Close()
+
+	decisions := make([]*entities.AssignmentDecision, 0)
+	for rows.Next() {
+		decision, err := scanAssignmentDecision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan assignment decision: %w", err)
+		}
+		decisions = append(decisions, decision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate assignment decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+func insertPostgresAssignmentDecision(ctx context.Context, db execer, decision *entities.AssignmentDecision) error {
+	candidates, excluded, reviewers, err := encodeAssignmentDecision(decision)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+        INSERT INTO assignment_decisions (`+assignmentDecisionColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `, decision.PullRequestID, string(decision.Kind), decision.ReplacedReviewerID, decision.Strategy, decision.Seed,
+		candidates, excluded, reviewers, decision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("save assignment decision: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type PostgresCredentialRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCredentialRepository(db *sql.DB) ports.CredentialRepository {
+	return &PostgresCredentialRepository{db: db}
+}
+
+func (r *PostgresCredentialRepository) Save(ctx context.Context, credentials *entities.Credentials) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO user_credentials (user_id, password_hash, failed_attempts, locked_until, updated_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id) DO UPDATE SET
+            password_hash = EXCLUDED.password_hash,
+            failed_attempts = EXCLUDED.failed_attempts,
+            locked_until = EXCLUDED.locked_until,
+            updated_at = EXCLUDED.updated_at
+    `, credentials.UserID, credentials.PasswordHash, credentials.FailedAttempts,
+		credentials.LockedUntil, credentials.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save credentials: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCredentialRepository) GetByUserID(ctx context.Context, userID string) (*entities.Credentials, error) {
+	credentials, err := scanCredentials(r.db.QueryRowContext(ctx, `
+        SELECT `+credentialColumns+`
+        FROM user_credentials
+        WHERE user_id = $1
+    `, userID))
+	if err != nil {
+		return nil, fmt.Errorf("query credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+func (r *PostgresCredentialRepository) RecordFailedLogin(ctx context.Context, userID string, policy entities.LockoutPolicy, now time.Time) (*entities.Credentials, error) {
+	credentials, err := scanCredentials(r.db.QueryRowContext(ctx, `
+        UPDATE user_credentials SET
+            failed_attempts = CASE WHEN $2 > 0 AND failed_attempts + 1 >= $2 THEN 0 ELSE failed_attempts + 1 END,
+            locked_until = CASE WHEN $2 > 0 AND failed_attempts + 1 >= $2 THEN $3 ELSE locked_until END
+        WHERE user_id = $1
+        RETURNING `+credentialColumns+`
+    `, userID, policy.MaxFailedAttempts, now.Add(policy.LockoutDuration)))
+	if err != nil {
+		return nil, fmt.Errorf("record failed login: %w", err)
+	}
+	return credentials, nil
+}
+
+func (r *PostgresCredentialRepository) ResetFailedLogins(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE user_credentials SET failed_attempts = 0, locked_until = NULL
+        WHERE user_id = $1
+    `, userID)
+	if err != nil {
+		return fmt.Errorf("reset failed logins: %w", err)
+	}
+	return nil
+}
+
+const credentialColumns = `user_id, password_hash, failed_attempts, locked_until, updated_at`
+
+// scanCredentials returns nil credentials when the row does not exist.
+func scanCredentials(row rowScanner) (*entities.Credentials, error) {
+	credentials := &entities.Credentials{}
+	err := row.Scan(
+		&credentials.UserID,
+		&credentials.PasswordHash,
+		&credentials.FailedAttempts,
+		&credentials.LockedUntil,
+		&credentials.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return credentials, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type PostgresIdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresIdempotencyRepository(db *sql.DB) ports.IdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+func (r *PostgresIdempotencyRepository) Reserve(ctx context.Context, record *entities.IdempotencyRecord, now time.Time) (*entities.IdempotencyRecord, error) {
+	// An expired record is taken over; a live one is rewritten with its own
+	// values. Either way the statement returns the row it leaves behind, so
+	// there is no follow-up read that a concurrent delete could miss.
+	existing := &entities.IdempotencyRecord{Key: record.Key, UserID: record.UserID}
+	err := r.db.QueryRowContext(ctx, `
+        INSERT INTO idempotency_keys (user_id, key, owner, request_hash, created_at, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (user_id, key) DO UPDATE SET
+            owner = CASE WHEN idempotency_keys.expires_at <= $7 THEN EXCLUDED.owner ELSE idempotency_keys.owner END,
+            request_hash = CASE WHEN idempotency_keys.expires_at <= $7 THEN EXCLUDED.request_hash ELSE idempotency_keys.request_hash END,
+            status_code = CASE WHEN idempotency_keys.expires_at <= $7 THEN 0 ELSE idempotency_keys.status_code END,
+            content_type = CASE WHEN idempotency_keys.expires_at <= $7 THEN '' ELSE idempotency_keys.content_type END,
+            body = CASE WHEN idempotency_keys.expires_at <= $7 THEN NULL ELSE idempotency_keys.body END,
+            created_at = CASE WHEN idempotency_keys.expires_at <= $7 THEN EXCLUDED.created_at ELSE idempotency_keys.created_at END,
+            expires_at = CASE WHEN idempotency_keys.expires_at <= $7 THEN EXCLUDED.expires_at ELSE idempotency_keys.expires_at END
+        RETURNING owner, request_hash, status_code, content_type, body, created_at, expires_at
+    `, record.UserID, record.Key, record.Owner, record.RequestHash, record.CreatedAt, record.ExpiresAt, now).Scan(
+		&existing.Owner,
+		&existing.RequestHash,
+		&existing.StatusCode,
+		&existing.ContentType,
+		&existing.Body,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if existing.Owner == record.Owner {
+		return nil, nil
+	}
+	return existing, nil
+}
+
+func (r *PostgresIdempotencyRepository) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE idempotency_keys
+        SET status_code = $4, content_type = $5, body = $6, expires_at = $7
+        WHERE user_id = $1 AND key = $2 AND owner = $3
+    `, record.UserID, record.Key, record.Owner, record.StatusCode, record.ContentType, record.Body, record.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresIdempotencyRepository) Release(ctx context.Context, record *entities.IdempotencyRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+        DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND owner = $3 AND status_code = 0
+    `, record.UserID, record.Key, record.Owner)
+	if err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return nil
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"time"
 
 	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/lib/pq"
 )
 
 type PostgresPRRepository struct {
@@ -25,7 +25,40 @@ func (r *PostgresPRRepository) Save(ctx context.Context, pr *entities.PullReques
 	}
 	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx, `
+	if err := savePostgresPR(ctx, tx, pr); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresPRRepository) SaveWithDecision(ctx context.Context, pr *entities.PullRequest, decision *entities.AssignmentDecision) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := savePostgresPR(ctx, tx, pr); err != nil {
+		return err
+	}
+	if err := insertPostgresAssignmentDecision(ctx, tx, decision); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func savePostgresPR(ctx context.Context, tx *sql.Tx, pr *entities.PullRequest) error {
+	_, err := tx.ExecContext(ctx, `
         INSERT INTO pull_requests (id, name, author_id, status, created_at, merged_at) 
         VALUES ($1, $2, $3, $4, $5, $6)
         ON CONFLICT (id) DO UPDATE SET 
@@ -53,60 +86,68 @@ func (r *PostgresPRRepository) Save(ctx context.Context, pr *entities.PullReques
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM pull_request_labels WHERE pull_request_id = $1
+    `, pr.ID)
+	if err != nil {
+		return fmt.Errorf("delete labels: %w", err)
 	}
 
+	for _, label := range pr.Labels {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO pull_request_labels (pull_request_id, label) 
+            VALUES ($1, $2)
+        `, pr.ID, label)
+		if err != nil {
+			return fmt.Errorf("insert label: %w", err)
+		}
+	}
 	return nil
 }
 
-func (r *PostgresPRRepository) GetByID(ctx context.Context, id string) (*entities.PullRequest, error) {
-	var prID, name, authorID, statusStr string
-	var createdAt time.Time
-	var mergedAt *time.Time
+// postgresPRSelect loads pull requests together with their reviewers and
+// labels, so that lists need a single query.
+const postgresPRSelect = `
+        SELECT pr.id, pr.name, pr.author_id, pr.status, pr.created_at, pr.merged_at,
+            ARRAY(
+                SELECT reviewer_id FROM pull_request_reviewers
+                WHERE pull_request_id = pr.id
+                ORDER BY assigned_at, reviewer_id
+            ),
+            ARRAY(
+                SELECT label FROM pull_request_labels
+                WHERE pull_request_id = pr.id
+                ORDER BY label
+            )
+        `
 
-	err := r.db.QueryRowContext(ctx, `
-        SELECT id, name, author_id, status, created_at, merged_at 
-        FROM pull_requests 
-        WHERE id = $1
-    `, id).Scan(&prID, &name, &authorID, &statusStr, &createdAt, &mergedAt)
+func scanPostgresPR(row rowScanner) (*entities.PullRequest, error) {
+	var statusStr string
+	pr := &entities.PullRequest{}
+	err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &statusStr, &pr.CreatedAt, &pr.MergedAt,
+		pq.Array(&pr.AssignedReviewers), pq.Array(&pr.Labels))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("query pr: %w", err)
+		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, `
-        SELECT reviewer_id FROM pull_request_reviewers WHERE pull_request_id = $1
-    `, id)
+	pr.Status, err = entities.ParsePRStatus(statusStr)
 	if err != nil {
-		return nil, fmt.Errorf("query reviewers: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	var reviewers []string
-	for rows.Next() {
-		var reviewerID string
-		if err := rows.Scan(&reviewerID); err != nil {
-			return nil, fmt.Errorf("scan reviewer: %w", err)
-		}
-		reviewers = append(reviewers, reviewerID)
-	}
+	return pr, nil
+}
 
-	status, err := entities.ParsePRStatus(statusStr)
+func (r *PostgresPRRepository) GetByID(ctx context.Context, id string) (*entities.PullRequest, error) {
+	pr, err := scanPostgresPR(r.db.QueryRowContext(ctx, postgresPRSelect+`
+        FROM pull_requests pr
+        WHERE pr.id = $1
+    `, id))
 	if err != nil {
-		return nil, err
-	}
-
-	pr := &entities.PullRequest{
-		ID:                prID,
-		Name:              name,
-		AuthorID:          authorID,
-		Status:            status,
-		AssignedReviewers: reviewers,
-		CreatedAt:         createdAt,
-		MergedAt:          mergedAt,
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query pr: %w", err)
 	}
 
 	return pr, nil
@@ -123,40 +164,20 @@ func (r *PostgresPRRepository) ExistsByID(ctx context.Context, id string) (bool,
 	return exists, nil
 }
 
-func (r *PostgresPRRepository) GetByReviewer(ctx context.Context, userID string) ([]*entities.PullRequest, error) {
-	rows, err := r.db.QueryContext(ctx, `
-        SELECT pr.id, pr.name, pr.author_id, pr.status, pr.created_at, pr.merged_at
-        FROM pull_requests pr
-        JOIN pull_request_reviewers prr ON pr.id = prr.pull_request_id
-        WHERE prr.reviewer_id = $1
-    `, userID)
+// Find loads the matching pull requests and their reviewers in one query.
+func (r *PostgresPRRepository) Find(ctx context.Context, query ports.PRQuery) ([]*entities.PullRequest, error) {
+	clauses, args := postgresDialect.prQueryClauses(query)
+	rows, err := r.db.QueryContext(ctx, postgresPRSelect+clauses, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query prs by reviewer: %w", err)
+		return nil, fmt.Errorf("query prs: %w", err)
 	}
 	defer rows.Close()
 
 	var prs []*entities.PullRequest
 	for rows.Next() {
-		var id, name, authorID, statusStr string
-		var createdAt time.Time
-		var mergedAt *time.Time
-
-		if err := rows.Scan(&id, &name, &authorID, &statusStr, &createdAt, &mergedAt); err != nil {
-			return nil, fmt.Errorf("scan pr: %w", err)
-		}
-
-		status, err := entities.ParsePRStatus(statusStr)
+		pr, err := scanPostgresPR(rows)
 		if err != nil {
-			return nil, err
-		}
-
-		pr := &entities.PullRequest{
-			ID:        id,
-			Name:      name,
-			AuthorID:  authorID,
-			Status:    status,
-			CreatedAt: createdAt,
-			MergedAt:  mergedAt,
+			return nil, fmt.Errorf("scan pr: %w", err)
 		}
 		prs = append(prs, pr)
 	}
@@ -167,3 +188,79 @@ func (r *PostgresPRRepository) GetByReviewer(ctx context.Context, userID string)
 
 	return prs, nil
 }
+
+func (r *PostgresPRRepository) Count(ctx context.Context, query ports.PRQuery) (int, error) {
+	clauses, args := postgresDialect.prCountClauses(query)
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) `+clauses, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count prs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresPRRepository) ListOpen(ctx context.Context) ([]*entities.PullRequest, error) {
+	return r.Find(ctx, ports.PRQuery{Status: entities.PRStatusOpen})
+}
+
+func (r *PostgresPRRepository) CountOpenByTeam(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT u.team_name, COUNT(*)
+        FROM pull_requests pr
+        JOIN users u ON u.id = pr.author_id
+        WHERE pr.status = $1
+        GROUP BY u.team_name
+    `, entities.PRStatusOpen.String())
+	if err != nil {
+		return nil, fmt.Errorf("count open prs by team: %w", err)
+	}
+	return scanCounts(rows)
+}
+
+func (r *PostgresPRRepository) CountOpenReviews(ctx context.Context, teamName string) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT prr.reviewer_id, COUNT(*)
+        FROM pull_request_reviewers prr
+        JOIN pull_requests pr ON pr.id = prr.pull_request_id
+        JOIN users u ON u.id = prr.reviewer_id
+        WHERE pr.status = $1 AND u.team_name = $2
+        GROUP BY prr.reviewer_id
+    `, entities.PRStatusOpen.String(), teamName)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews: %w", err)
+	}
+	return scanCounts(rows)
+}
+
+// scanCounts reads rows of a key and a count.
+func scanCounts(rows *sql.Rows) (map[string]int, error) {
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("scan count: %w", err)
+		}
+		counts[key] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate counts: %w", err)
+	}
+	return counts, nil
+}
+
+func scanPR(row rowScanner) (*entities.PullRequest, error) {
+	var statusStr string
+	pr := &entities.PullRequest{}
+	if err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &statusStr, &pr.CreatedAt, &pr.MergedAt); err != nil {
+		return nil, err
+	}
+
+	status, err := entities.ParsePRStatus(statusStr)
+	if err != nil {
+		return nil, err
+	}
+	pr.Status = status
+
+	return pr, nil
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type PostgresRefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRefreshTokenRepository(db *sql.DB) ports.RefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{db: db}
+}
+
+func (r *PostgresRefreshTokenRepository) Save(ctx context.Context, token *entities.RefreshToken) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at, revoked_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (id) DO UPDATE SET revoked_at = EXCLUDED.revoked_at
+    `, token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt, token.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("save refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	token := &entities.RefreshToken{}
+	err := r.db.QueryRowContext(ctx, `
+        SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at
+        FROM refresh_tokens
+        WHERE token_hash = $1
+    `, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query refresh token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *PostgresRefreshTokenRepository) Consume(ctx context.Context, tokenHash string, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+        UPDATE refresh_tokens SET revoked_at = $2
+        WHERE token_hash = $1 AND revoked_at IS NULL
+    `, tokenHash, at)
+	if err != nil {
+		return false, fmt.Errorf("consume refresh token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("consume refresh token: %w", err)
+	}
+	return rows == 1, nil
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE refresh_tokens SET revoked_at = $2
+        WHERE family_id = $1 AND revoked_at IS NULL
+    `, familyID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE refresh_tokens SET revoked_at = $2
+        WHERE user_id = $1 AND revoked_at IS NULL
+    `, userID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("revoke user refresh tokens: %w", err)
+	}
+	return nil
+}
@@ -25,19 +25,16 @@ func (r *PostgresTeamRepository) Save(ctx context.Context, team *entities.Team)
 	defer tx.Rollback()
 
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO teams (name) VALUES ($1)
-        ON CONFLICT DO NOTHING
-    `, team.Name)
+        INSERT INTO teams (name, chat_webhook_url) VALUES ($1, $2)
+        ON CONFLICT (name) DO UPDATE SET chat_webhook_url = EXCLUDED.chat_webhook_url
+    `, team.Name, team.ChatWebhookURL)
 	if err != nil {
 		return fmt.Errorf("insert team: %w", err)
 	}
 
 	for _, member := range team.Members {
-		_, err = tx.ExecContext(ctx, `
-            INSERT INTO users (id, username, team_name, is_active) 
-            VALUES ($1, $2, $3, $4)
-            ON CONFLICT (id) DO UPDATE SET is_active = EXCLUDED.is_active
-        `, member.ID, member.Username, member.TeamName, member.IsActive)
+		_, err = tx.ExecContext(ctx, upsertUser, member.ID, member.Username, member.TeamName, member.IsActive, userRole(member), member.ChatHandle, member.Email,
+			member.Notifications.EmailOnAssignment, member.Notifications.EmailDigest, member.OnLeave)
 		if err != nil {
 			return fmt.Errorf("insert user %s: %w", member.ID, err)
 		}
@@ -56,10 +53,21 @@ func (r *PostgresTeamRepository) GetByName(ctx context.Context, name string) (*e
 		Members: make([]*entities.User, 0),
 	}
 
+	err := r.db.QueryRowContext(ctx, `
+        SELECT chat_webhook_url FROM teams WHERE name = $1
+    `, name).Scan(&team.ChatWebhookURL)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query team: %w", err)
+	}
+
 	rows, err := r.db.QueryContext(ctx, `
-        SELECT id, username, team_name, is_active 
+        SELECT `+userColumns+` 
         FROM users 
         WHERE team_name = $1
+        ORDER BY id
     `, name)
 	if err != nil {
 		return nil, fmt.Errorf("query users: %w", err)
@@ -67,12 +75,10 @@ func (r *PostgresTeamRepository) GetByName(ctx context.Context, name string) (*e
 	defer rows.Close()
 
 	for rows.Next() {
-		var id, username, teamName string
-		var isActive bool
-		if err := rows.Scan(&id, &username, &teamName, &isActive); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan user: %w", err)
 		}
-		user := entities.NewUser(id, username, teamName, isActive)
 		team.Members = append(team.Members, user)
 	}
 
@@ -80,10 +86,6 @@ func (r *PostgresTeamRepository) GetByName(ctx context.Context, name string) (*e
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
-	if len(team.Members) == 0 {
-		return nil, nil
-	}
-
 	return team, nil
 }
 
@@ -97,3 +99,39 @@ func (r *PostgresTeamRepository) ExistsByName(ctx context.Context, name string)
 	}
 	return exists, nil
 }
+
+func (r *PostgresTeamRepository) List(ctx context.Context) ([]*entities.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT name, chat_webhook_url FROM teams ORDER BY name
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []*entities.Team
+	byName := make(map[string]*entities.Team)
+	for rows.Next() {
+		team := &entities.Team{Members: make([]*entities.User, 0)}
+		if err := rows.Scan(&team.Name, &team.ChatWebhookURL); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		teams = append(teams, team)
+		byName[team.Name] = team
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	users, err := NewPostgresUserRepository(r.db).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if team, ok := byName[user.TeamName]; ok {
+			team.Members = append(team.Members, user)
+		}
+	}
+
+	return teams, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type PostgresTeamSyncRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTeamSyncRepository(db *sql.DB) ports.TeamSyncRepository {
+	return &PostgresTeamSyncRepository{db: db}
+}
+
+func (r *PostgresTeamSyncRepository) Apply(ctx context.Context, plan *entities.TeamSyncPlan) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, name := range plan.CreateTeams {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO teams (name) VALUES ($1)
+            ON CONFLICT (name) DO NOTHING
+        `, name)
+		if err != nil {
+			return fmt.Errorf("insert team %s: %w", name, err)
+		}
+	}
+
+	for _, user := range plan.Saves() {
+		_, err = tx.ExecContext(ctx, upsertUser, user.ID, user.Username, user.TeamName, user.IsActive, userRole(user), user.ChatHandle, user.Email,
+			user.Notifications.EmailOnAssignment, user.Notifications.EmailDigest, user.OnLeave)
+		if err != nil {
+			return fmt.Errorf("save user %s: %w", user.ID, err)
+		}
+	}
+
+	for _, user := range plan.Remove {
+		// Deleting a user cascades to their pull requests, so a user who
+		// gained history after planning is only deactivated.
+		result, err := tx.ExecContext(ctx, `
+            DELETE FROM users
+            WHERE id = $1
+              AND NOT EXISTS (SELECT 1 FROM pull_requests WHERE author_id = $1)
+              AND NOT EXISTS (SELECT 1 FROM pull_request_reviewers WHERE reviewer_id = $1)
+        `, user.ID)
+		if err != nil {
+			return fmt.Errorf("delete user %s: %w", user.ID, err)
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("delete user %s: %w", user.ID, err)
+		}
+		if deleted > 0 {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE users SET is_active = false WHERE id = $1`, user.ID)
+		if err != nil {
+			return fmt.Errorf("deactivate user %s: %w", user.ID, err)
+		}
+	}
+
+	for _, name := range plan.DeleteTeams {
+		// Deleting a team cascades to its users, so members must have been
+		// moved out first.
+		result, err := tx.ExecContext(ctx, `
+            DELETE FROM teams
+            WHERE name = $1
+              AND NOT EXISTS (SELECT 1 FROM users WHERE team_name = $1)
+        `, name)
+		if err != nil {
+			return fmt.Errorf("delete team %s: %w", name, err)
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("delete team %s: %w", name, err)
+		}
+		if deleted == 0 {
+			return fmt.Errorf("delete team %s: team is missing or still has members", name)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -9,6 +9,64 @@ import (
 	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
 )
 
+const userColumns = `id, username, team_name, is_active, role, chat_handle, email,
+            notify_email_on_assignment, notify_email_digest, on_leave`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (*entities.User, error) {
+	var role string
+	user := &entities.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.TeamName,
+		&user.IsActive,
+		&role,
+		&user.ChatHandle,
+		&user.Email,
+		&user.Notifications.EmailOnAssignment,
+		&user.Notifications.EmailDigest,
+		&user.OnLeave,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role, err = entities.ParseRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// upsertUser writes every user column, so saving a user through either the
+// user or the team repository leaves the same row behind.
+const upsertUser = `
+        INSERT INTO users (` + userColumns + `) 
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (id) DO UPDATE SET 
+            username = EXCLUDED.username,
+            team_name = EXCLUDED.team_name,
+            is_active = EXCLUDED.is_active,
+            role = EXCLUDED.role,
+            chat_handle = EXCLUDED.chat_handle,
+            email = EXCLUDED.email,
+            notify_email_on_assignment = EXCLUDED.notify_email_on_assignment,
+            notify_email_digest = EXCLUDED.notify_email_digest,
+            on_leave = EXCLUDED.on_leave
+    `
+
+func userRole(user *entities.User) string {
+	if user.Role == "" {
+		return entities.RoleMember.String()
+	}
+	return user.Role.String()
+}
+
 type PostgresUserRepository struct {
 	db *sql.DB
 }
@@ -18,13 +76,8 @@ func NewPostgresUserRepository(db *sql.DB) ports.UserRepository {
 }
 
 func (r *PostgresUserRepository) Save(ctx context.Context, user *entities.User) error {
-	_, err := r.db.ExecContext(ctx, `
-        INSERT INTO users (id, username, team_name, is_active) 
-        VALUES ($1, $2, $3, $4)
-        ON CONFLICT (id) DO UPDATE SET 
-            username = EXCLUDED.username,
-            is_active = EXCLUDED.is_active
-    `, user.ID, user.Username, user.TeamName, user.IsActive)
+	_, err := r.db.ExecContext(ctx, upsertUser, user.ID, user.Username, user.TeamName, user.IsActive, userRole(user), user.ChatHandle, user.Email,
+		user.Notifications.EmailOnAssignment, user.Notifications.EmailDigest, user.OnLeave)
 	if err != nil {
 		return fmt.Errorf("save user: %w", err)
 	}
@@ -32,14 +85,11 @@ func (r *PostgresUserRepository) Save(ctx context.Context, user *entities.User)
 }
 
 func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
-	var userID, username, teamName string
-	var isActive bool
-
-	err := r.db.QueryRowContext(ctx, `
-        SELECT id, username, team_name, is_active 
+	user, err := scanUser(r.db.QueryRowContext(ctx, `
+        SELECT `+userColumns+` 
         FROM users 
         WHERE id = $1
-    `, id).Scan(&userID, &username, &teamName, &isActive)
+    `, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -47,7 +97,7 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*entit
 		return nil, fmt.Errorf("query user: %w", err)
 	}
 
-	return entities.NewUser(userID, username, teamName, isActive), nil
+	return user, nil
 }
 
 func (r *PostgresUserRepository) ExistsByID(ctx context.Context, id string) (bool, error) {
@@ -62,24 +112,36 @@ func (r *PostgresUserRepository) ExistsByID(ctx context.Context, id string) (boo
 }
 
 func (r *PostgresUserRepository) GetByTeamName(ctx context.Context, teamName string) ([]*entities.User, error) {
-	rows, err := r.db.QueryContext(ctx, `
-        SELECT id, username, team_name, is_active 
+	return r.list(ctx, `
+        SELECT `+userColumns+` 
         FROM users 
         WHERE team_name = $1
+        ORDER BY id
     `, teamName)
+}
+
+func (r *PostgresUserRepository) List(ctx context.Context) ([]*entities.User, error) {
+	return r.list(ctx, `
+        SELECT `+userColumns+` 
+        FROM users 
+        ORDER BY id
+    `)
+}
+
+func (r *PostgresUserRepository) list(ctx context.Context, query string, args ...any) ([]*entities.User, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query users by team: %w", err)
+		return nil, fmt.Errorf("query users: %w", err)
 	}
 	defer rows.Close()
 
 	var users []*entities.User
 	for rows.Next() {
-		var id, username, team string
-		var isActive bool
-		if err := rows.Scan(&id, &username, &team, &isActive); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan user: %w", err)
 		}
-		users = append(users, entities.NewUser(id, username, team, isActive))
+		users = append(users, user)
 	}
 
 	if err = rows.Err(); err != nil {
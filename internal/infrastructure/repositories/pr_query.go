@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// sqlDialect covers what the Postgres and SQLite pull request queries differ
+// in: placeholders, how times are passed and how names are searched.
+type sqlDialect struct {
+	placeholder func(n int) string
+	timeArg     func(t time.Time) any
+	nameSearch  func(words []string, arg func(any) string) string
+}
+
+var (
+	// Postgres searches the GIN-indexed tsvector of the name, matching each
+	// word as a prefix.
+	postgresDialect = sqlDialect{
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		timeArg:     func(t time.Time) any { return t.UTC() },
+		nameSearch: func(words []string, arg func(any) string) string {
+			terms := make([]string, 0, len(words))
+			for _, word := range words {
+				terms = append(terms, word+":*")
+			}
+			return "to_tsvector('simple', pr.name) @@ to_tsquery('simple', " + arg(strings.Join(terms, " & ")) + ")"
+		},
+	}
+	// SQLite has no word tokenizer to hand, so common separators are turned
+	// into spaces and each word has to follow one.
+	sqliteDialect = sqlDialect{
+		placeholder: func(int) string { return "?" },
+		timeArg:     func(t time.Time) any { return sqliteTime(t) },
+		nameSearch: func(words []string, arg func(any) string) string {
+			const name = `(' ' || replace(replace(replace(replace(lower(pr.name), '-', ' '), '_', ' '), '/', ' '), '.', ' '))`
+			conditions := make([]string, 0, len(words))
+			for _, word := range words {
+				conditions = append(conditions, name+" LIKE "+arg("% "+word+"%"))
+			}
+			return strings.Join(conditions, " AND ")
+		},
+	}
+)
+
+type prQueryBuilder struct {
+	dialect    sqlDialect
+	args       []any
+	conditions []string
+	joins      string
+}
+
+func (b *prQueryBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return b.dialect.placeholder(len(b.args))
+}
+
+func (b *prQueryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// from renders the FROM and WHERE clauses, with pull_requests aliased as pr.
+func (b *prQueryBuilder) from() string {
+	clauses := "FROM pull_requests pr" + b.joins
+	if len(b.conditions) > 0 {
+		clauses += " WHERE " + strings.Join(b.conditions, " AND ")
+	}
+	return clauses
+}
+
+// filter adds q's filters, but not its cursor.
+func (b *prQueryBuilder) filter(q ports.PRQuery) {
+	if q.ReviewerID != "" {
+		b.joins += " JOIN pull_request_reviewers prr ON prr.pull_request_id = pr.id"
+		b.where("prr.reviewer_id = " + b.arg(q.ReviewerID))
+	}
+	if q.AuthorID != "" {
+		b.where("pr.author_id = " + b.arg(q.AuthorID))
+	}
+	if len(q.AuthorIDs) > 0 {
+		placeholders := make([]string, 0, len(q.AuthorIDs))
+		for _, id := range q.AuthorIDs {
+			placeholders = append(placeholders, b.arg(id))
+		}
+		b.where("pr.author_id IN (" + strings.Join(placeholders, ", ") + ")")
+	}
+	if q.Status != "" {
+		b.where("pr.status = " + b.arg(q.Status.String()))
+	}
+	if q.Label != "" {
+		b.where("EXISTS (SELECT 1 FROM pull_request_labels prl WHERE prl.pull_request_id = pr.id AND prl.label = " +
+			b.arg(q.Label) + ")")
+	}
+	if words := searchWords(q.Search); len(words) > 0 {
+		b.where(b.dialect.nameSearch(words, b.arg))
+	}
+	if q.CreatedFrom != nil {
+		b.where("pr.created_at >= " + b.arg(b.dialect.timeArg(*q.CreatedFrom)))
+	}
+	if q.CreatedTo != nil {
+		b.where("pr.created_at < " + b.arg(b.dialect.timeArg(*q.CreatedTo)))
+	}
+}
+
+// prQueryClauses renders the FROM, WHERE, ORDER BY and LIMIT clauses for q.
+// Reviewer filtering joins on the (reviewer_id, pull_request_id) index and the
+// ordering matches the (created_at, id) index.
+func (d sqlDialect) prQueryClauses(q ports.PRQuery) (string, []any) {
+	b := &prQueryBuilder{dialect: d}
+	b.filter(q)
+
+	direction, comparison := "ASC", ">"
+	if q.Order == ports.SortDescending {
+		direction, comparison = "DESC", "<"
+	}
+	if q.After != nil {
+		b.where("(pr.created_at, pr.id) " + comparison +
+			" (" + b.arg(d.timeArg(q.After.CreatedAt)) + ", " + b.arg(q.After.ID) + ")")
+	}
+
+	clauses := b.from() + " ORDER BY pr.created_at " + direction + ", pr.id " + direction
+	if q.Limit > 0 {
+		clauses += " LIMIT " + b.arg(q.Limit)
+	}
+	return clauses, b.args
+}
+
+// prCountClauses renders the FROM and WHERE clauses for counting q's matches.
+func (d sqlDialect) prCountClauses(q ports.PRQuery) (string, []any) {
+	b := &prQueryBuilder{dialect: d}
+	b.filter(q)
+	return b.from(), b.args
+}
+
+// searchWords splits a search into lower-case words of letters and digits,
+// which also keeps tsquery and LIKE syntax out of the search.
+func searchWords(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchesPRFilter applies the filters of q to a single pull request, for the
+// in-memory repository.
+func matchesPRFilter(pr *entities.PullRequest, q ports.PRQuery) bool {
+	if q.ReviewerID != "" && !slices.Contains(pr.AssignedReviewers, q.ReviewerID) {
+		return false
+	}
+	if q.AuthorID != "" && pr.AuthorID != q.AuthorID {
+		return false
+	}
+	if len(q.AuthorIDs) > 0 && !slices.Contains(q.AuthorIDs, pr.AuthorID) {
+		return false
+	}
+	if q.Status != "" && pr.Status != q.Status {
+		return false
+	}
+	if q.Label != "" && !slices.Contains(pr.Labels, q.Label) {
+		return false
+	}
+	if words := searchWords(q.Search); len(words) > 0 {
+		nameWords := searchWords(pr.Name)
+		for _, word := range words {
+			if !slices.ContainsFunc(nameWords, func(w string) bool { return strings.HasPrefix(w, word) }) {
+				return false
+			}
+		}
+	}
+	if q.CreatedFrom != nil && pr.CreatedAt.Before(*q.CreatedFrom) {
+		return false
+	}
+	if q.CreatedTo != nil && !pr.CreatedAt.Before(*q.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// afterPRCursor reports whether pr comes after the cursor of q in q's order.
+func afterPRCursor(pr *entities.PullRequest, q ports.PRQuery) bool {
+	if q.After == nil {
+		return true
+	}
+	if q.Order == ports.SortDescending {
+		return prBefore(pr, q.After.CreatedAt, q.After.ID)
+	}
+	return prBefore(&entities.PullRequest{ID: q.After.ID, CreatedAt: q.After.CreatedAt}, pr.CreatedAt, pr.ID)
+}
+
+// prBefore reports whether pr sorts before the (createdAt, id) key.
+func prBefore(pr *entities.PullRequest, createdAt time.Time, id string) bool {
+	if pr.CreatedAt.Equal(createdAt) {
+		return pr.ID < id
+	}
+	return pr.CreatedAt.Before(createdAt)
+}
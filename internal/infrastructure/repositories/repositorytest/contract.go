@@ -0,0 +1,1088 @@
+// Package repositorytest holds the behaviour every storage backend has to
+// share. Backends run it from their own tests with a factory that returns
+// empty repositories.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// Repositories is one backend's set of repositories over a shared store, so
+// that for example members saved with a team are visible as users.
+type Repositories struct {
+	Teams         ports.TeamRepository
+	Users         ports.UserRepository
+	PRs           ports.PRRepository
+	Credentials   ports.CredentialRepository
+	RefreshTokens ports.RefreshTokenRepository
+	APITokens     ports.APITokenRepository
+	Revocations   ports.TokenRevocationRepository
+	Idempotency   ports.IdempotencyRepository
+	Decisions     ports.AssignmentDecisionRepository
+	DigestRuns    ports.DigestRunRepository
+	TeamSync      ports.TeamSyncRepository
+}
+
+// Factory returns repositories over an empty store. It is called once per
+// subtest.
+type Factory func(t *testing.T) Repositories
+
+// base is a whole-second UTC time, which every backend stores exactly.
+var base = time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+// Run checks the shared repository contract:
+//   - lookups of a missing entity return nil and no error;
+//   - saving is an upsert of every field, and entities are stored by value;
+//   - a team exists as soon as it is saved, members or not, and its members
+//     are the users whose team name matches;
+//   - saving a team moves members that already belong to another team;
+//   - lists are ordered: users by ID, pull requests by creation time then ID,
+//     API tokens by creation time then ID;
+//   - pull requests always come back with their assigned reviewers, and Find
+//     applies every PRQuery filter, the cursor and the limit;
+//   - an idempotency key can be reserved again only once its record expires,
+//     and only the current owner of a reservation can complete or release it;
+//   - assignment decisions are listed per pull request, oldest first;
+//   - a day's review digest run can be claimed only once;
+//   - a team sync never deletes a user with pull request history.
+func Run(t *testing.T, newRepositories Factory) {
+	t.Run("Teams", func(t *testing.T) { testTeams(t, newRepositories(t)) })
+	t.Run("SavingTeamMovesExistingMembers", func(t *testing.T) { testSavingTeamMovesExistingMembers(t, newRepositories(t)) })
+	t.Run("Users", func(t *testing.T) { testUsers(t, newRepositories(t)) })
+	t.Run("PullRequests", func(t *testing.T) { testPullRequests(t, newRepositories(t)) })
+	t.Run("PullRequestQueries", func(t *testing.T) { testPullRequestQueries(t, newRepositories(t)) })
+	t.Run("Credentials", func(t *testing.T) { testCredentials(t, newRepositories(t)) })
+	t.Run("RefreshTokens", func(t *testing.T) { testRefreshTokens(t, newRepositories(t)) })
+	t.Run("APITokens", func(t *testing.T) { testAPITokens(t, newRepositories(t)) })
+	t.Run("TokenRevocations", func(t *testing.T) { testTokenRevocations(t, newRepositories(t)) })
+	t.Run("Idempotency", func(t *testing.T) { testIdempotency(t, newRepositories(t)) })
+	t.Run("AssignmentDecisions", func(t *testing.T) { testAssignmentDecisions(t, newRepositories(t)) })
+	t.Run("DigestRuns", func(t *testing.T) { testDigestRuns(t, newRepositories(t)) })
+	t.Run("TeamSync", func(t *testing.T) { testTeamSync(t, newRepositories(t)) })
+}
+
+// seedTeam saves a team whose members are named after their IDs.
+func seedTeam(t *testing.T, repos Repositories, name string, ids ...string) *entities.Team {
+	t.Helper()
+	members := make([]*entities.User, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, entities.NewUser(id, "user-"+id, name, true))
+	}
+	team := entities.NewTeam(name, members)
+	if err := repos.Teams.Save(context.Background(), team); err != nil {
+		t.Fatalf("failed to save team %s: %v", name, err)
+	}
+	return team
+}
+
+func memberIDs(users []*entities.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func prIDs(prs []*entities.PullRequest) []string {
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.ID)
+	}
+	return ids
+}
+
+func sameReviewers(got, want []string) bool {
+	got, want = slices.Clone(got), slices.Clone(want)
+	slices.Sort(got)
+	slices.Sort(want)
+	return slices.Equal(got, want)
+}
+
+// testSavingTeamMovesExistingMembers pins down what /team/add does with a user
+// who is already in another team: a user belongs to exactly one team, so the
+// user moves and leaves the old team.
+func testSavingTeamMovesExistingMembers(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u1", "u2")
+	seedTeam(t, repos, "frontend", "u1", "u3")
+
+	user, err := repos.Users.GetByID(ctx, "u1")
+	if err != nil || user == nil || user.TeamName != "frontend" {
+		t.Fatalf("expected u1 to move to frontend, got %+v (%v)", user, err)
+	}
+	backend, _ := repos.Teams.GetByName(ctx, "backend")
+	if ids := memberIDs(backend.Members); !slices.Equal(ids, []string{"u2"}) {
+		t.Errorf("expected u1 to leave backend, got %v", ids)
+	}
+	frontend, _ := repos.Teams.GetByName(ctx, "frontend")
+	if ids := memberIDs(frontend.Members); !slices.Equal(ids, []string{"u1", "u3"}) {
+		t.Errorf("expected frontend to have u1 and u3, got %v", ids)
+	}
+}
+
+func testTeams(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	if team, err := repos.Teams.GetByName(ctx, "missing"); err != nil || team != nil {
+		t.Fatalf("expected no team, got %+v (%v)", team, err)
+	}
+	if exists, err := repos.Teams.ExistsByName(ctx, "missing"); err != nil || exists {
+		t.Fatalf("expected missing team not to exist, got %v (%v)", exists, err)
+	}
+
+	empty := entities.NewTeam("empty", nil)
+	if err := repos.Teams.Save(ctx, empty); err != nil {
+		t.Fatalf("failed to save empty team: %v", err)
+	}
+	got, err := repos.Teams.GetByName(ctx, "empty")
+	if err != nil || got == nil {
+		t.Fatalf("expected team without members to be found, got %+v (%v)", got, err)
+	}
+	if len(got.Members) != 0 {
+		t.Errorf("expected no members, got %v", memberIDs(got.Members))
+	}
+
+	team := seedTeam(t, repos, "backend", "u3", "u1", "u2")
+	team.ChatWebhookURL = "https://hooks.example.com/backend"
+	team.Members[0].SetActive(false)
+	if err := repos.Teams.Save(ctx, team); err != nil {
+		t.Fatalf("failed to update team: %v", err)
+	}
+
+	got, err = repos.Teams.GetByName(ctx, "backend")
+	if err != nil || got == nil {
+		t.Fatalf("failed to get team: %+v (%v)", got, err)
+	}
+	if got.ChatWebhookURL != team.ChatWebhookURL {
+		t.Errorf("expected webhook %q, got %q", team.ChatWebhookURL, got.ChatWebhookURL)
+	}
+	if ids := memberIDs(got.Members); !slices.Equal(ids, []string{"u1", "u2", "u3"}) {
+		t.Errorf("expected members ordered by ID, got %v", ids)
+	}
+	if got.Members[2].IsActive {
+		t.Error("expected the re-saved member to be inactive")
+	}
+
+	user, err := repos.Users.GetByID(ctx, "u1")
+	if err != nil || user == nil || user.TeamName != "backend" {
+		t.Errorf("expected team members to be saved as users, got %+v (%v)", user, err)
+	}
+
+	// A user moved to another team leaves the old one.
+	user.TeamName = "empty"
+	if err := repos.Users.Save(ctx, user); err != nil {
+		t.Fatalf("failed to move user: %v", err)
+	}
+	got, _ = repos.Teams.GetByName(ctx, "backend")
+	if ids := memberIDs(got.Members); !slices.Equal(ids, []string{"u2", "u3"}) {
+		t.Errorf("expected u1 to leave backend, got %v", ids)
+	}
+	got, _ = repos.Teams.GetByName(ctx, "empty")
+	if ids := memberIDs(got.Members); !slices.Equal(ids, []string{"u1"}) {
+		t.Errorf("expected u1 to join empty, got %v", ids)
+	}
+
+	teams, err := repos.Teams.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list teams: %v", err)
+	}
+	if len(teams) != 2 || teams[0].Name != "backend" || teams[1].Name != "empty" {
+		t.Fatalf("expected teams ordered by name, got %+v", teams)
+	}
+	if teams[0].ChatWebhookURL != team.ChatWebhookURL {
+		t.Errorf("expected listed webhook %q, got %q", team.ChatWebhookURL, teams[0].ChatWebhookURL)
+	}
+	if ids := memberIDs(teams[0].Members); !slices.Equal(ids, []string{"u2", "u3"}) {
+		t.Errorf("expected listed backend members [u2 u3], got %v", ids)
+	}
+	if ids := memberIDs(teams[1].Members); !slices.Equal(ids, []string{"u1"}) {
+		t.Errorf("expected listed empty members [u1], got %v", ids)
+	}
+}
+
+func testUsers(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u2", "u1")
+
+	if user, err := repos.Users.GetByID(ctx, "missing"); err != nil || user != nil {
+		t.Fatalf("expected no user, got %+v (%v)", user, err)
+	}
+	if exists, err := repos.Users.ExistsByID(ctx, "missing"); err != nil || exists {
+		t.Fatalf("expected missing user not to exist, got %v (%v)", exists, err)
+	}
+	if exists, err := repos.Users.ExistsByID(ctx, "u1"); err != nil || !exists {
+		t.Fatalf("expected u1 to exist, got %v (%v)", exists, err)
+	}
+
+	user, err := repos.Users.GetByID(ctx, "u1")
+	if err != nil || user == nil {
+		t.Fatalf("failed to get user: %+v (%v)", user, err)
+	}
+	user.Username = "alice"
+	user.SetActive(false)
+	user.SetRole(entities.RoleTeamLead)
+	user.SetOnLeave(true)
+	user.ChatHandle = "@alice"
+	user.SetNotificationPreferences("alice@example.com", entities.NotificationPreferences{
+		EmailOnAssignment: true,
+		EmailDigest:       true,
+	})
+	if err := repos.Users.Save(ctx, user); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+
+	// Changes made after Save stay with the caller.
+	user.Username = "not saved"
+
+	got, err := repos.Users.GetByID(ctx, "u1")
+	if err != nil || got == nil {
+		t.Fatalf("failed to get user: %+v (%v)", got, err)
+	}
+	want := entities.User{
+		ID:         "u1",
+		Username:   "alice",
+		TeamName:   "backend",
+		IsActive:   false,
+		OnLeave:    true,
+		Role:       entities.RoleTeamLead,
+		ChatHandle: "@alice",
+		Email:      "alice@example.com",
+		Notifications: entities.NotificationPreferences{
+			EmailOnAssignment: true,
+			EmailDigest:       true,
+		},
+	}
+	if *got != want {
+		t.Errorf("expected %+v, got %+v", want, *got)
+	}
+
+	users, err := repos.Users.GetByTeamName(ctx, "backend")
+	if err != nil {
+		t.Fatalf("failed to list team users: %v", err)
+	}
+	if ids := memberIDs(users); !slices.Equal(ids, []string{"u1", "u2"}) {
+		t.Errorf("expected users ordered by ID, got %v", ids)
+	}
+	if users, err := repos.Users.GetByTeamName(ctx, "missing"); err != nil || len(users) != 0 {
+		t.Errorf("expected no users for a missing team, got %v (%v)", memberIDs(users), err)
+	}
+
+	seedTeam(t, repos, "frontend", "u0")
+	all, err := repos.Users.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list users: %v", err)
+	}
+	if ids := memberIDs(all); !slices.Equal(ids, []string{"u0", "u1", "u2"}) {
+		t.Errorf("expected every user ordered by ID, got %v", ids)
+	}
+}
+
+func testPullRequests(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u1", "u2", "u3", "u4")
+
+	if pr, err := repos.PRs.GetByID(ctx, "missing"); err != nil || pr != nil {
+		t.Fatalf("expected no pr, got %+v (%v)", pr, err)
+	}
+	if exists, err := repos.PRs.ExistsByID(ctx, "missing"); err != nil || exists {
+		t.Fatalf("expected missing pr not to exist, got %v (%v)", exists, err)
+	}
+
+	newPR := func(id string, createdAt time.Time, reviewers ...string) *entities.PullRequest {
+		pr := entities.NewPullRequest(id, "PR "+id, "u1", reviewers)
+		pr.CreatedAt = createdAt
+		if err := repos.PRs.Save(ctx, pr); err != nil {
+			t.Fatalf("failed to save %s: %v", id, err)
+		}
+		return pr
+	}
+	// Saved out of order, with pr-b and pr-a sharing a creation time.
+	newPR("pr-c", base.Add(2*time.Hour), "u2")
+	newPR("pr-b", base.Add(time.Hour), "u2", "u3")
+	newPR("pr-a", base.Add(time.Hour), "u3", "u4")
+	first := newPR("pr-0", base, "u2", "u4")
+
+	// Changes made after Save stay with the caller.
+	first.AssignedReviewers[0] = "u3"
+
+	got, err := repos.PRs.GetByID(ctx, "pr-0")
+	if err != nil || got == nil {
+		t.Fatalf("failed to get pr: %+v (%v)", got, err)
+	}
+	if got.Name != "PR pr-0" || got.AuthorID != "u1" || got.Status != entities.PRStatusOpen || got.MergedAt != nil {
+		t.Errorf("unexpected pr: %+v", got)
+	}
+	if !got.CreatedAt.Equal(base) {
+		t.Errorf("expected created_at %v, got %v", base, got.CreatedAt)
+	}
+	if !sameReviewers(got.AssignedReviewers, []string{"u2", "u4"}) {
+		t.Errorf("expected reviewers [u2 u4], got %v", got.AssignedReviewers)
+	}
+
+	if err := got.ReassignReviewer("u2", "u3"); err != nil {
+		t.Fatalf("failed to reassign: %v", err)
+	}
+	got.Merge()
+	mergedAt := base.Add(3 * time.Hour)
+	got.MergedAt = &mergedAt
+	if err := repos.PRs.Save(ctx, got); err != nil {
+		t.Fatalf("failed to save merged pr: %v", err)
+	}
+	merged, err := repos.PRs.GetByID(ctx, "pr-0")
+	if err != nil || merged == nil {
+		t.Fatalf("failed to get merged pr: %+v (%v)", merged, err)
+	}
+	if merged.Status != entities.PRStatusMerged || merged.MergedAt == nil || !merged.MergedAt.Equal(mergedAt) {
+		t.Errorf("expected merged pr, got status %s merged_at %v", merged.Status, merged.MergedAt)
+	}
+	if !sameReviewers(merged.AssignedReviewers, []string{"u3", "u4"}) {
+		t.Errorf("expected reviewers [u3 u4] after reassignment, got %v", merged.AssignedReviewers)
+	}
+
+	reviews, err := repos.PRs.Find(ctx, ports.PRQuery{ReviewerID: "u3"})
+	if err != nil {
+		t.Fatalf("failed to list reviews: %v", err)
+	}
+	if ids := prIDs(reviews); !slices.Equal(ids, []string{"pr-0", "pr-a", "pr-b"}) {
+		t.Fatalf("expected reviews ordered by creation, merged included, got %v", ids)
+	}
+	if !sameReviewers(reviews[1].AssignedReviewers, []string{"u3", "u4"}) {
+		t.Errorf("expected full reviewer list on pr-a, got %v", reviews[1].AssignedReviewers)
+	}
+	if reviews, err := repos.PRs.Find(ctx, ports.PRQuery{ReviewerID: "u1"}); err != nil || len(reviews) != 0 {
+		t.Errorf("expected no reviews for u1, got %v (%v)", prIDs(reviews), err)
+	}
+
+	open, err := repos.PRs.ListOpen(ctx)
+	if err != nil {
+		t.Fatalf("failed to list open prs: %v", err)
+	}
+	if ids := prIDs(open); !slices.Equal(ids, []string{"pr-a", "pr-b", "pr-c"}) {
+		t.Fatalf("expected open prs ordered by creation, got %v", ids)
+	}
+	if !sameReviewers(open[1].AssignedReviewers, []string{"u2", "u3"}) {
+		t.Errorf("expected full reviewer list on pr-b, got %v", open[1].AssignedReviewers)
+	}
+
+	seedTeam(t, repos, "frontend", "u5")
+	if err := repos.PRs.Save(ctx, entities.NewPullRequest("pr-f", "PR pr-f", "u5", []string{"u2"})); err != nil {
+		t.Fatalf("failed to save pr-f: %v", err)
+	}
+	counts, err := repos.PRs.CountOpenByTeam(ctx)
+	if err != nil {
+		t.Fatalf("failed to count open prs: %v", err)
+	}
+	if want := map[string]int{"backend": 3, "frontend": 1}; !maps.Equal(counts, want) {
+		t.Errorf("expected open pr counts %v, got %v", want, counts)
+	}
+
+	// Reviews count whichever team authored the pull request; merged ones
+	// are left out.
+	reviewCounts, err := repos.PRs.CountOpenReviews(ctx, "backend")
+	if err != nil {
+		t.Fatalf("failed to count open reviews: %v", err)
+	}
+	if want := map[string]int{"u2": 3, "u3": 2, "u4": 1}; !maps.Equal(reviewCounts, want) {
+		t.Errorf("expected open review counts %v, got %v", want, reviewCounts)
+	}
+	if reviewCounts, err := repos.PRs.CountOpenReviews(ctx, "frontend"); err != nil || len(reviewCounts) != 0 {
+		t.Errorf("expected no open reviews for frontend, got %v (%v)", reviewCounts, err)
+	}
+}
+
+func testPullRequestQueries(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u1", "u2", "u3")
+
+	names := map[string]string{
+		"pr-2": "Fix login-page redirect",
+		"pr-4": "Add Search index",
+		"pr-6": "search: tidy results",
+	}
+	labels := map[string][]string{
+		"pr-2": {"frontend", "bug"},
+		"pr-4": {"backend"},
+		"pr-5": {"bug"},
+	}
+
+	// pr-1..pr-6 are created an hour apart; pr-4 and pr-5 share a time.
+	save := func(id, authorID string, hour int, merged bool, reviewers ...string) {
+		name, ok := names[id]
+		if !ok {
+			name = "PR " + id
+		}
+		pr := entities.NewPullRequest(id, name, authorID, reviewers)
+		pr.CreatedAt = base.Add(time.Duration(hour) * time.Hour)
+		pr.SetLabels(labels[id])
+		if merged {
+			pr.Merge()
+		}
+		if err := repos.PRs.Save(ctx, pr); err != nil {
+			t.Fatalf("failed to save %s: %v", id, err)
+		}
+	}
+	save("pr-1", "u1", 0, true, "u2")
+	save("pr-2", "u3", 1, false, "u2", "u1")
+	save("pr-3", "u1", 2, false, "u3")
+	save("pr-4", "u1", 3, false, "u2")
+	save("pr-5", "u3", 3, true, "u2", "u1")
+	save("pr-6", "u1", 4, false, "u2")
+
+	at := func(hour int) *time.Time {
+		t := base.Add(time.Duration(hour) * time.Hour)
+		return &t
+	}
+
+	tests := []struct {
+		name  string
+		query ports.PRQuery
+		want  []string
+	}{
+		{"all", ports.PRQuery{}, []string{"pr-1", "pr-2", "pr-3", "pr-4", "pr-5", "pr-6"}},
+		{"reviewer", ports.PRQuery{ReviewerID: "u2"}, []string{"pr-1", "pr-2", "pr-4", "pr-5", "pr-6"}},
+		{"descending", ports.PRQuery{ReviewerID: "u2", Order: ports.SortDescending}, []string{"pr-6", "pr-5", "pr-4", "pr-2", "pr-1"}},
+		{"status", ports.PRQuery{ReviewerID: "u2", Status: entities.PRStatusOpen}, []string{"pr-2", "pr-4", "pr-6"}},
+		{"author", ports.PRQuery{ReviewerID: "u2", AuthorID: "u3"}, []string{"pr-2", "pr-5"}},
+		{"date range", ports.PRQuery{ReviewerID: "u2", CreatedFrom: at(1), CreatedTo: at(4)}, []string{"pr-2", "pr-4", "pr-5"}},
+		{"limit", ports.PRQuery{ReviewerID: "u2", Limit: 2}, []string{"pr-1", "pr-2"}},
+		{
+			"after cursor",
+			ports.PRQuery{ReviewerID: "u2", After: &ports.PRCursor{CreatedAt: *at(3), ID: "pr-4"}},
+			[]string{"pr-5", "pr-6"},
+		},
+		{
+			"after cursor descending",
+			ports.PRQuery{ReviewerID: "u2", Order: ports.SortDescending, After: &ports.PRCursor{CreatedAt: *at(3), ID: "pr-5"}, Limit: 2},
+			[]string{"pr-4", "pr-2"},
+		},
+		{"no match", ports.PRQuery{ReviewerID: "u3", Status: entities.PRStatusMerged}, []string{}},
+		{"author ids", ports.PRQuery{AuthorIDs: []string{"u3", "u9"}}, []string{"pr-2", "pr-5"}},
+		{"label", ports.PRQuery{Label: "bug"}, []string{"pr-2", "pr-5"}},
+		{"label and status", ports.PRQuery{Label: "bug", Status: entities.PRStatusOpen}, []string{"pr-2"}},
+		{"search", ports.PRQuery{Search: "SEARCH"}, []string{"pr-4", "pr-6"}},
+		{"search prefixes", ports.PRQuery{Search: "sea ind"}, []string{"pr-4"}},
+		{"search any word order", ports.PRQuery{Search: "redirect login"}, []string{"pr-2"}},
+		{"search hyphenated", ports.PRQuery{Search: "page"}, []string{"pr-2"}},
+		{"search no match", ports.PRQuery{Search: "earch"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs, err := repos.PRs.Find(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ids := prIDs(prs); !slices.Equal(ids, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, ids)
+			}
+		})
+	}
+
+	prs, err := repos.PRs.Find(ctx, ports.PRQuery{ReviewerID: "u1", Status: entities.PRStatusMerged})
+	if err != nil || len(prs) != 1 {
+		t.Fatalf("expected pr-5, got %v (%v)", prIDs(prs), err)
+	}
+	if !sameReviewers(prs[0].AssignedReviewers, []string{"u1", "u2"}) {
+		t.Errorf("expected every reviewer of pr-5, got %v", prs[0].AssignedReviewers)
+	}
+	if prs[0].MergedAt == nil || prs[0].Status != entities.PRStatusMerged {
+		t.Errorf("expected merged pr, got %+v", prs[0])
+	}
+
+	got, err := repos.PRs.GetByID(ctx, "pr-2")
+	if err != nil || got == nil {
+		t.Fatalf("failed to get pr-2: %+v (%v)", got, err)
+	}
+	if !slices.Equal(got.Labels, []string{"bug", "frontend"}) {
+		t.Errorf("expected sorted labels [bug frontend], got %v", got.Labels)
+	}
+	got.SetLabels([]string{"backend"})
+	if err := repos.PRs.Save(ctx, got); err != nil {
+		t.Fatalf("failed to relabel pr-2: %v", err)
+	}
+	prs, err = repos.PRs.Find(ctx, ports.PRQuery{Label: "backend"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := prIDs(prs); !slices.Equal(ids, []string{"pr-2", "pr-4"}) {
+		t.Errorf("expected relabelled pr-2 and pr-4, got %v", ids)
+	}
+	if !slices.Equal(prs[0].Labels, []string{"backend"}) {
+		t.Errorf("expected labels to be replaced, got %v", prs[0].Labels)
+	}
+
+	counts := []struct {
+		name  string
+		query ports.PRQuery
+		want  int
+	}{
+		{"all", ports.PRQuery{}, 6},
+		{"ignores paging", ports.PRQuery{ReviewerID: "u2", Limit: 1, After: &ports.PRCursor{CreatedAt: *at(3), ID: "pr-4"}}, 5},
+		{"filtered", ports.PRQuery{Label: "backend", Search: "login"}, 1},
+		{"date range", ports.PRQuery{AuthorID: "u1", CreatedFrom: at(2), CreatedTo: at(4)}, 2},
+	}
+	for _, tt := range counts {
+		t.Run("count "+tt.name, func(t *testing.T) {
+			count, err := repos.PRs.Count(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, count)
+			}
+		})
+	}
+}
+
+func testCredentials(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u1")
+
+	if c, err := repos.Credentials.GetByUserID(ctx, "u1"); err != nil || c != nil {
+		t.Fatalf("expected no credentials, got %+v (%v)", c, err)
+	}
+
+	credentials := entities.NewCredentials("u1", "hash-1")
+	credentials.UpdatedAt = base
+	if err := repos.Credentials.Save(ctx, credentials); err != nil {
+		t.Fatalf("failed to save credentials: %v", err)
+	}
+
+	lockedUntil := base.Add(15 * time.Minute)
+	credentials.PasswordHash = "hash-2"
+	credentials.FailedAttempts = 3
+	credentials.LockedUntil = &lockedUntil
+	credentials.UpdatedAt = base.Add(time.Minute)
+	if err := repos.Credentials.Save(ctx, credentials); err != nil {
+		t.Fatalf("failed to update credentials: %v", err)
+	}
+
+	got, err := repos.Credentials.GetByUserID(ctx, "u1")
+	if err != nil || got == nil {
+		t.Fatalf("failed to get credentials: %+v (%v)", got, err)
+	}
+	if got.PasswordHash != "hash-2" || got.FailedAttempts != 3 || !got.UpdatedAt.Equal(credentials.UpdatedAt) {
+		t.Errorf("unexpected credentials: %+v", got)
+	}
+	if got.LockedUntil == nil || !got.LockedUntil.Equal(lockedUntil) {
+		t.Errorf("expected locked until %v, got %v", lockedUntil, got.LockedUntil)
+	}
+
+	if err := repos.Credentials.ResetFailedLogins(ctx, "u1"); err != nil {
+		t.Fatalf("failed to reset failed logins: %v", err)
+	}
+	policy := entities.LockoutPolicy{MaxFailedAttempts: 2, LockoutDuration: time.Hour}
+	now := base.Add(2 * time.Minute)
+	got, err = repos.Credentials.RecordFailedLogin(ctx, "u1", policy, now)
+	if err != nil || got == nil || got.FailedAttempts != 1 || got.LockedUntil != nil || got.PasswordHash != "hash-2" {
+		t.Fatalf("expected one failed attempt after a reset, got %+v (%v)", got, err)
+	}
+	got, err = repos.Credentials.RecordFailedLogin(ctx, "u1", policy, now)
+	if err != nil || got == nil || got.FailedAttempts != 0 || got.LockedUntil == nil || !got.LockedUntil.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the second failure to lock the account, got %+v (%v)", got, err)
+	}
+	if got, err := repos.Credentials.RecordFailedLogin(ctx, "missing", policy, now); err != nil || got != nil {
+		t.Errorf("expected no credentials for a missing user, got %+v (%v)", got, err)
+	}
+}
+
+func testRefreshTokens(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u1", "u2")
+
+	if token, err := repos.RefreshTokens.GetByHash(ctx, "missing"); err != nil || token != nil {
+		t.Fatalf("expected no token, got %+v (%v)", token, err)
+	}
+
+	save := func(id, userID, familyID string) {
+		token := &entities.RefreshToken{
+			ID:        id,
+			UserID:    userID,
+			FamilyID:  familyID,
+			TokenHash: "hash-" + id,
+			ExpiresAt: base.Add(24 * time.Hour),
+			CreatedAt: base,
+		}
+		if err := repos.RefreshTokens.Save(ctx, token); err != nil {
+			t.Fatalf("failed to save %s: %v", id, err)
+		}
+	}
+	save("r1", "u1", "f1")
+	save("r2", "u1", "f1")
+	save("r3", "u1", "f2")
+	save("r4", "u2", "f3")
+	save("r5", "u2", "f4")
+
+	revoked := func(id string) bool {
+		t.Helper()
+		token, err := repos.RefreshTokens.GetByHash(ctx, "hash-"+id)
+		if err != nil || token == nil {
+			t.Fatalf("failed to get %s: %+v (%v)", id, token, err)
+		}
+		return token.IsRevoked()
+	}
+
+	got, err := repos.RefreshTokens.GetByHash(ctx, "hash-r1")
+	if err != nil || got == nil {
+		t.Fatalf("failed to get token: %+v (%v)", got, err)
+	}
+	if got.ID != "r1" || got.UserID != "u1" || got.FamilyID != "f1" || !got.ExpiresAt.Equal(base.Add(24*time.Hour)) || got.IsRevoked() {
+		t.Errorf("unexpected token: %+v", got)
+	}
+
+	for i, want := range []bool{true, false} {
+		consumed, err := repos.RefreshTokens.Consume(ctx, "hash-r5", base.Add(time.Hour))
+		if err != nil || consumed != want {
+			t.Errorf("consume %d: expected %v, got %v (%v)", i+1, want, consumed, err)
+		}
+	}
+	if consumed, err := repos.RefreshTokens.Consume(ctx, "missing", base); err != nil || consumed {
+		t.Errorf("expected a missing token not to be consumed, got %v (%v)", consumed, err)
+	}
+	if !revoked("r5") {
+		t.Error("expected the consumed token to be revoked")
+	}
+
+	if err := repos.RefreshTokens.RevokeFamily(ctx, "f1"); err != nil {
+		t.Fatalf("failed to revoke family: %v", err)
+	}
+	if !revoked("r1") || !revoked("r2") || revoked("r3") || revoked("r4") {
+		t.Error("expected only family f1 to be revoked")
+	}
+
+	if err := repos.RefreshTokens.RevokeAllForUser(ctx, "u1"); err != nil {
+		t.Fatalf("failed to revoke user tokens: %v", err)
+	}
+	if !revoked("r3") || revoked("r4") {
+		t.Error("expected only u1's tokens to be revoked")
+	}
+}
+
+func testAPITokens(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "bots", "ci")
+
+	if token, err := repos.APITokens.GetByID(ctx, "missing"); err != nil || token != nil {
+		t.Fatalf("expected no token by id, got %+v (%v)", token, err)
+	}
+	if token, err := repos.APITokens.GetByHash(ctx, "missing"); err != nil || token != nil {
+		t.Fatalf("expected no token by hash, got %+v (%v)", token, err)
+	}
+	if tokens, err := repos.APITokens.List(ctx); err != nil || len(tokens) != 0 {
+		t.Fatalf("expected no tokens, got %d (%v)", len(tokens), err)
+	}
+
+	expiresAt := base.Add(24 * time.Hour)
+	scopes := []entities.Scope{entities.ScopePRWrite, entities.ScopeTeamRead}
+	for _, token := range []*entities.APIToken{
+		entities.NewAPIToken("t3", "later", "ci", "hash-3", scopes, base.Add(time.Hour), nil),
+		entities.NewAPIToken("t2", "second", "ci", "hash-2", scopes, base, &expiresAt),
+		entities.NewAPIToken("t1", "first", "ci", "hash-1", scopes, base, nil),
+	} {
+		if err := repos.APITokens.Save(ctx, token); err != nil {
+			t.Fatalf("failed to save %s: %v", token.ID, err)
+		}
+	}
+
+	token, err := repos.APITokens.GetByHash(ctx, "hash-2")
+	if err != nil || token == nil {
+		t.Fatalf("failed to get token: %+v (%v)", token, err)
+	}
+	if token.ID != "t2" || token.Name != "second" || token.UserID != "ci" || !token.CreatedAt.Equal(base) {
+		t.Errorf("unexpected token: %+v", token)
+	}
+	if !token.HasScope(entities.ScopePRWrite) || !token.HasScope(entities.ScopeTeamRead) || token.HasScope(entities.ScopeAdmin) {
+		t.Errorf("unexpected scopes: %v", token.Scopes)
+	}
+	if token.ExpiresAt == nil || !token.ExpiresAt.Equal(expiresAt) || token.IsRevoked() {
+		t.Errorf("unexpected token times: expires=%v revoked=%v", token.ExpiresAt, token.RevokedAt)
+	}
+
+	token.Revoke(base.Add(2 * time.Hour))
+	if err := repos.APITokens.Save(ctx, token); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+	got, err := repos.APITokens.GetByID(ctx, "t2")
+	if err != nil || got == nil || got.RevokedAt == nil || !got.RevokedAt.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("expected revoked token, got %+v (%v)", got, err)
+	}
+
+	tokens, err := repos.APITokens.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list tokens: %v", err)
+	}
+	ids := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		ids = append(ids, token.ID)
+	}
+	if !slices.Equal(ids, []string{"t1", "t2", "t3"}) {
+		t.Errorf("expected tokens ordered by creation then ID, got %v", ids)
+	}
+}
+
+func testTokenRevocations(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u1", "u2")
+	now := base
+
+	if err := repos.Revocations.RevokeToken(ctx, "expired", "u1", now.Add(-time.Minute)); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+	if err := repos.Revocations.RevokeToken(ctx, "live", "u1", now.Add(time.Hour)); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+	if err := repos.Revocations.RevokeUser(ctx, "u1", now); err != nil {
+		t.Fatalf("failed to revoke user: %v", err)
+	}
+	// An older revocation must not move the cut-off back.
+	if err := repos.Revocations.RevokeUser(ctx, "u1", now.Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to revoke user: %v", err)
+	}
+
+	denylist, err := repos.Revocations.Load(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to load denylist: %v", err)
+	}
+	if _, ok := denylist.Tokens["expired"]; ok {
+		t.Error("expected expired revocation to be skipped")
+	}
+	if !denylist.IsRevoked("live", "u2", now) {
+		t.Error("expected live token to be revoked")
+	}
+	if !denylist.IsRevoked("other", "u1", now.Add(-time.Minute)) {
+		t.Error("expected tokens issued before the cut-off to be revoked")
+	}
+	if denylist.IsRevoked("other", "u1", now.Add(time.Second)) {
+		t.Error("expected the cut-off not to move back")
+	}
+	if denylist.IsRevoked("other", "u2", now.Add(-time.Minute)) {
+		t.Error("expected other users to be unaffected")
+	}
+
+	// The cut-off outlives the user, so a deprovisioned user's tokens stay
+	// revoked after a reload.
+	u1, err := repos.Users.GetByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+	if err := repos.TeamSync.Apply(ctx, &entities.TeamSyncPlan{Remove: []*entities.User{u1}}); err != nil {
+		t.Fatalf("failed to remove user: %v", err)
+	}
+	denylist, err = repos.Revocations.Load(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to load denylist: %v", err)
+	}
+	if !denylist.IsRevoked("other", "u1", now.Add(-time.Minute)) {
+		t.Error("expected the cut-off to survive deleting the user")
+	}
+}
+
+func testIdempotency(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	now := base
+	reservations := 0
+	// reserve returns the new reservation and the live record that blocked
+	// it, if any.
+	reserve := func(key, userID, hash string, at time.Time) (*entities.IdempotencyRecord, *entities.IdempotencyRecord) {
+		t.Helper()
+		reservations++
+		record := &entities.IdempotencyRecord{
+			Key:         key,
+			UserID:      userID,
+			Owner:       fmt.Sprintf("owner-%d", reservations),
+			RequestHash: hash,
+			CreatedAt:   at,
+			ExpiresAt:   at.Add(time.Minute),
+		}
+		existing, err := repos.Idempotency.Reserve(ctx, record, at)
+		if err != nil {
+			t.Fatalf("failed to reserve %s: %v", key, err)
+		}
+		return record, existing
+	}
+
+	k1, existing := reserve("k1", "u1", "h1", now)
+	if existing != nil {
+		t.Fatalf("expected k1 to be reserved, got %+v", existing)
+	}
+	if _, pending := reserve("k1", "u1", "h2", now); pending == nil || pending.IsComplete() || pending.RequestHash != "h1" {
+		t.Fatalf("expected the pending k1 record, got %+v", pending)
+	}
+	if _, existing := reserve("k1", "u2", "h1", now); existing != nil {
+		t.Errorf("expected keys to be scoped per user, got %+v", existing)
+	}
+
+	k1.Complete(201, "application/json", []byte(`{"ok":true}`), now.Add(time.Hour))
+	if err := repos.Idempotency.Complete(ctx, k1); err != nil {
+		t.Fatalf("failed to complete record: %v", err)
+	}
+	// A completed record survives a release.
+	if err := repos.Idempotency.Release(ctx, k1); err != nil {
+		t.Fatalf("failed to release record: %v", err)
+	}
+	_, done := reserve("k1", "u1", "h1", now.Add(30*time.Minute))
+	if done == nil || done.StatusCode != 201 || done.ContentType != "application/json" || string(done.Body) != `{"ok":true}` ||
+		!done.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the completed k1 record, got %+v", done)
+	}
+	if _, existing := reserve("k1", "u1", "h3", now.Add(time.Hour)); existing != nil {
+		t.Errorf("expected an expired record to be replaced, got %+v", existing)
+	}
+
+	k2, existing := reserve("k2", "u1", "h1", now)
+	if existing != nil {
+		t.Fatalf("expected k2 to be reserved, got %+v", existing)
+	}
+	if err := repos.Idempotency.Release(ctx, k2); err != nil {
+		t.Fatalf("failed to release record: %v", err)
+	}
+	if _, existing := reserve("k2", "u1", "h2", now); existing != nil {
+		t.Errorf("expected a released key to be free, got %+v", existing)
+	}
+
+	// Once a retry takes over an expired reservation, the request that made
+	// it can neither complete nor release the record.
+	slow, existing := reserve("k3", "u1", "h1", now)
+	if existing != nil {
+		t.Fatalf("expected k3 to be reserved, got %+v", existing)
+	}
+	retry, existing := reserve("k3", "u1", "h1", now.Add(2*time.Minute))
+	if existing != nil {
+		t.Fatalf("expected the expired k3 reservation to be taken over, got %+v", existing)
+	}
+	slow.Complete(500, "text/plain", []byte("stale"), now.Add(time.Hour))
+	if err := repos.Idempotency.Complete(ctx, slow); err != nil {
+		t.Fatalf("failed to complete record: %v", err)
+	}
+	if err := repos.Idempotency.Release(ctx, slow); err != nil {
+		t.Fatalf("failed to release record: %v", err)
+	}
+	if _, pending := reserve("k3", "u1", "h1", now.Add(2*time.Minute)); pending == nil || pending.IsComplete() {
+		t.Fatalf("expected the retry to keep its pending k3 reservation, got %+v", pending)
+	}
+	retry.Complete(200, "application/json", []byte(`{}`), now.Add(time.Hour))
+	if err := repos.Idempotency.Complete(ctx, retry); err != nil {
+		t.Fatalf("failed to complete record: %v", err)
+	}
+	if _, done := reserve("k3", "u1", "h1", now.Add(2*time.Minute)); done == nil || done.StatusCode != 200 {
+		t.Errorf("expected the retry's response for k3, got %+v", done)
+	}
+
+	if err := repos.Idempotency.DeleteExpired(ctx, now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("failed to delete expired records: %v", err)
+	}
+	if _, existing := reserve("k2", "u1", "h3", now); existing != nil {
+		t.Errorf("expected the expired k2 record to be deleted, got %+v", existing)
+	}
+	if _, existing := reserve("k1", "u1", "h4", now.Add(time.Hour)); existing == nil || existing.RequestHash != "h3" {
+		t.Errorf("expected the live k1 record to be kept, got %+v", existing)
+	}
+}
+
+func testAssignmentDecisions(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	seedTeam(t, repos, "backend", "u1", "u2", "u3", "u4")
+	for _, id := range []string{"pr-1", "pr-2"} {
+		if err := repos.PRs.Save(ctx, entities.NewPullRequest(id, "PR", "u1", []string{"u2", "u3"})); err != nil {
+			t.Fatalf("failed to save pr: %v", err)
+		}
+	}
+
+	created := &entities.AssignmentDecision{
+		PullRequestID: "pr-1",
+		Kind:          entities.AssignmentCreate,
+		Strategy:      "random",
+		Seed:          -8123456789012345678,
+		Candidates: []entities.ScoredCandidate{
+			{UserID: "u2", Score: 0.9400458192631459},
+			{UserID: "u3", Score: 0.6645600532184904},
+			{UserID: "u4", Score: 0.4377141871869802},
+		},
+		Excluded:  []entities.ReviewerExclusion{{UserID: "u1", Reason: entities.ExclusionAuthor}},
+		Reviewers: []string{"u2", "u3"},
+		CreatedAt: base,
+	}
+	reassigned := &entities.AssignmentDecision{
+		PullRequestID:      "pr-1",
+		Kind:               entities.AssignmentReassign,
+		ReplacedReviewerID: "u2",
+		Strategy:           "random",
+		Seed:               7,
+		Candidates:         []entities.ScoredCandidate{{UserID: "u4", Score: 0.5}},
+		Excluded: []entities.ReviewerExclusion{
+			{UserID: "u1", Reason: entities.ExclusionAuthor},
+			{UserID: "u2", Reason: entities.ExclusionReviewer},
+			{UserID: "u3", Reason: entities.ExclusionReviewer},
+		},
+		Reviewers: []string{"u4"},
+		CreatedAt: base.Add(time.Hour),
+	}
+	other := &entities.AssignmentDecision{
+		PullRequestID: "pr-2",
+		Kind:          entities.AssignmentCreate,
+		Strategy:      "random",
+		Excluded:      []entities.ReviewerExclusion{{UserID: "u1", Reason: entities.ExclusionAuthor}},
+		CreatedAt:     base,
+	}
+	for _, decision := range []*entities.AssignmentDecision{reassigned, created, other} {
+		if err := repos.Decisions.Save(ctx, decision); err != nil {
+			t.Fatalf("failed to save decision: %v", err)
+		}
+	}
+	decisions, err := repos.Decisions.ListByPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("failed to list decisions: %v", err)
+	}
+	if len(decisions) != 2 {
+		t.Fatalf("expected 2 decisions for pr-1, got %d", len(decisions))
+	}
+	for i, want := range []*entities.AssignmentDecision{created, reassigned} {
+		got := *decisions[i]
+		if got.CreatedAt.Equal(want.CreatedAt) {
+			got.CreatedAt = want.CreatedAt
+		}
+		if !reflect.DeepEqual(&got, want) {
+			t.Errorf("decision %d: expected %+v, got %+v", i, want, decisions[i])
+		}
+	}
+
+	decisions, err = repos.Decisions.ListByPullRequest(ctx, "pr-2")
+	if err != nil || len(decisions) != 1 || len(decisions[0].Candidates) != 0 || len(decisions[0].Reviewers) != 0 {
+		t.Errorf("expected pr-2 decision without candidates, got %+v (%v)", decisions, err)
+	}
+	if decisions, err := repos.Decisions.ListByPullRequest(ctx, "missing"); err != nil || len(decisions) != 0 {
+		t.Errorf("expected no decisions for a missing pr, got %+v (%v)", decisions, err)
+	}
+
+	pr := entities.NewPullRequest("pr-3", "PR", "u1", []string{"u4"})
+	decision := &entities.AssignmentDecision{
+		PullRequestID: "pr-3",
+		Kind:          entities.AssignmentCreate,
+		Strategy:      "random",
+		Candidates:    []entities.ScoredCandidate{{UserID: "u4", Score: 0.5}},
+		Reviewers:     []string{"u4"},
+		CreatedAt:     base,
+	}
+	if err := repos.PRs.SaveWithDecision(ctx, pr, decision); err != nil {
+		t.Fatalf("failed to save pr with decision: %v", err)
+	}
+	if saved, err := repos.PRs.GetByID(ctx, "pr-3"); err != nil || saved == nil || !slices.Equal(saved.AssignedReviewers, []string{"u4"}) {
+		t.Errorf("expected pr-3 reviewed by u4, got %+v (%v)", saved, err)
+	}
+	decisions, err = repos.Decisions.ListByPullRequest(ctx, "pr-3")
+	if err != nil || len(decisions) != 1 || !slices.Equal(decisions[0].Reviewers, []string{"u4"}) {
+		t.Errorf("expected the pr-3 decision to be saved with it, got %+v (%v)", decisions, err)
+	}
+}
+
+func testDigestRuns(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	claim := func(day time.Time) bool {
+		t.Helper()
+		claimed, err := repos.DigestRuns.Claim(ctx, day)
+		if err != nil {
+			t.Fatalf("failed to claim digest run: %v", err)
+		}
+		return claimed
+	}
+
+	if !claim(base) {
+		t.Fatal("expected the first claim of a day to succeed")
+	}
+	if claim(base.Add(3 * time.Hour)) {
+		t.Error("expected a second claim on the same day to fail")
+	}
+	if !claim(base.AddDate(0, 0, 1)) {
+		t.Error("expected the next day to be claimable")
+	}
+}
+
+func testTeamSync(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	backend := seedTeam(t, repos, "backend", "u1", "u2", "u3")
+	if err := repos.Credentials.Save(ctx, entities.NewCredentials("u3", "hash")); err != nil {
+		t.Fatalf("failed to save credentials: %v", err)
+	}
+	if err := repos.PRs.Save(ctx, entities.NewPullRequest("pr-1", "PR", "u2", []string{"u1"})); err != nil {
+		t.Fatalf("failed to save pr: %v", err)
+	}
+
+	moved := *backend.Members[0]
+	moved.TeamName = "frontend"
+	moved.SetRole(entities.RoleTeamLead)
+	renamed := *backend.Members[1]
+	renamed.Username = "bobby"
+	plan := &entities.TeamSyncPlan{
+		CreateTeams: []string{"frontend"},
+		Add:         []*entities.User{entities.NewUser("u4", "user-u4", "frontend", false)},
+		Move:        []entities.UserMove{{User: &moved, FromTeam: "backend"}},
+		Update:      []*entities.User{&renamed},
+		// u2 authored pr-1, so it is deactivated rather than deleted.
+		Remove: []*entities.User{backend.Members[1], backend.Members[2]},
+	}
+	if err := repos.TeamSync.Apply(ctx, plan); err != nil {
+		t.Fatalf("failed to apply plan: %v", err)
+	}
+
+	frontend, err := repos.Teams.GetByName(ctx, "frontend")
+	if err != nil || frontend == nil {
+		t.Fatalf("expected frontend to be created, got %+v (%v)", frontend, err)
+	}
+	if ids := memberIDs(frontend.Members); !slices.Equal(ids, []string{"u1", "u4"}) {
+		t.Errorf("expected frontend members [u1 u4], got %v", ids)
+	}
+	if u1, err := repos.Users.GetByID(ctx, "u1"); err != nil || u1.Role != entities.RoleTeamLead {
+		t.Errorf("expected moved user to keep every field, got %+v (%v)", u1, err)
+	}
+	if u4, err := repos.Users.GetByID(ctx, "u4"); err != nil || u4 == nil || u4.IsActive {
+		t.Errorf("expected inactive u4, got %+v (%v)", u4, err)
+	}
+
+	u2, err := repos.Users.GetByID(ctx, "u2")
+	if err != nil || u2 == nil {
+		t.Fatalf("expected u2 to be kept, got %+v (%v)", u2, err)
+	}
+	if u2.IsActive || u2.Username != "bobby" {
+		t.Errorf("expected u2 renamed and deactivated, got %+v", u2)
+	}
+	if pr, err := repos.PRs.GetByID(ctx, "pr-1"); err != nil || pr == nil {
+		t.Errorf("expected pr-1 to survive, got %+v (%v)", pr, err)
+	}
+
+	if u3, err := repos.Users.GetByID(ctx, "u3"); err != nil || u3 != nil {
+		t.Errorf("expected u3 to be removed, got %+v (%v)", u3, err)
+	}
+	if c, err := repos.Credentials.GetByUserID(ctx, "u3"); err != nil || c != nil {
+		t.Errorf("expected credentials of u3 to be removed, got %+v (%v)", c, err)
+	}
+
+	// backend still holds u2, and deleting it would cascade to the user.
+	if err := repos.TeamSync.Apply(ctx, &entities.TeamSyncPlan{DeleteTeams: []string{"backend"}}); err == nil {
+		t.Error("expected deleting a team with members to fail")
+	}
+	if u2, err := repos.Users.GetByID(ctx, "u2"); err != nil || u2 == nil {
+		t.Errorf("expected u2 to survive the failed delete, got %+v (%v)", u2, err)
+	}
+
+	emptied := &entities.TeamSyncPlan{
+		Move:        []entities.UserMove{{User: u2, FromTeam: "backend"}},
+		DeleteTeams: []string{"backend"},
+	}
+	u2.TeamName = "frontend"
+	if err := repos.TeamSync.Apply(ctx, emptied); err != nil {
+		t.Fatalf("failed to delete emptied team: %v", err)
+	}
+	if exists, err := repos.Teams.ExistsByName(ctx, "backend"); err != nil || exists {
+		t.Errorf("expected backend to be deleted, got exists=%v (%v)", exists, err)
+	}
+}
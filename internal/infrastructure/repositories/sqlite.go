@@ -0,0 +1,19 @@
+package repositories
+
+import "time"
+
+// sqliteTimeLayout is how the SQLite repositories store timestamps. SQLite
+// compares them as text, so every value is written in UTC with a fixed width
+// that sorts chronologically; the driver parses it back on TIMESTAMP columns.
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000000"
+
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func sqliteTimePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return sqliteTime(*t)
+}
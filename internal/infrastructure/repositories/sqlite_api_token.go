@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SQLiteAPITokenRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteAPITokenRepository(db *sql.DB) ports.APITokenRepository {
+	return &SQLiteAPITokenRepository{db: db}
+}
+
+// scanSQLiteAPIToken reads a token whose scopes are stored as a JSON array.
+func scanSQLiteAPIToken(row rowScanner) (*entities.APIToken, error) {
+	var scopesJSON string
+	token := &entities.APIToken{}
+	err := row.Scan(
+		&token.ID,
+		&token.Name,
+		&token.UserID,
+		&token.TokenHash,
+		&scopesJSON,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var scopes []string
+	if err := json.Unmarshal([]byte(scopesJSON), &scopes); err != nil {
+		return nil, fmt.Errorf("decode scopes: %w", err)
+	}
+	token.Scopes, err = entities.ParseScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *SQLiteAPITokenRepository) Save(ctx context.Context, token *entities.APIToken) error {
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, scope.String())
+	}
+	scopesJSON, err := json.Marshal(scopes)
+	if err != nil {
+		return fmt.Errorf("encode scopes: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+        INSERT INTO api_tokens (`+apiTokenColumns+`)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (id) DO UPDATE SET revoked_at = excluded.revoked_at
+    `, token.ID, token.Name, token.UserID, token.TokenHash, string(scopesJSON),
+		sqliteTime(token.CreatedAt), sqliteTimePtr(token.ExpiresAt), sqliteTimePtr(token.RevokedAt))
+	if err != nil {
+		return fmt.Errorf("save api token: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteAPITokenRepository) GetByID(ctx context.Context, id string) (*entities.APIToken, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT `+apiTokenColumns+`
+        FROM api_tokens
+        WHERE id = ?
+    `, id)
+	return r.scanOne(row)
+}
+
+func (r *SQLiteAPITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.APIToken, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT `+apiTokenColumns+`
+        FROM api_tokens
+        WHERE token_hash = ?
+    `, tokenHash)
+	return r.scanOne(row)
+}
+
+func (r *SQLiteAPITokenRepository) List(ctx context.Context) ([]*entities.APIToken, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+apiTokenColumns+`
+        FROM api_tokens
+        ORDER BY created_at, id
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("query api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*entities.APIToken
+	for rows.Next() {
+		token, err := scanSQLiteAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *SQLiteAPITokenRepository) scanOne(row *sql.Row) (*entities.APIToken, error) {
+	token, err := scanSQLiteAPIToken(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query api token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SQLiteCredentialRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteCredentialRepository(db *sql.DB) ports.CredentialRepository {
+	return &SQLiteCredentialRepository{db: db}
+}
+
+func (r *SQLiteCredentialRepository) Save(ctx context.Context, credentials *entities.Credentials) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO user_credentials (user_id, password_hash, failed_attempts, locked_until, updated_at)
+        VALUES (?, ?, ?, ?, ?)
+        ON CONFLICT (user_id) DO UPDATE SET
+            password_hash = excluded.password_hash,
+            failed_attempts = excluded.failed_attempts,
+            locked_until = excluded.locked_until,
+            updated_at = excluded.updated_at
+    `, credentials.UserID, credentials.PasswordHash, credentials.FailedAttempts,
+		sqliteTimePtr(credentials.LockedUntil), sqliteTime(credentials.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("save credentials: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteCredentialRepository) GetByUserID(ctx context.Context, userID string) (*entities.Credentials, error) {
+	credentials, err := scanCredentials(r.db.QueryRowContext(ctx, `
+        SELECT `+credentialColumns+`
+        FROM user_credentials
+        WHERE user_id = ?
+    `, userID))
+	if err != nil {
+		return nil, fmt.Errorf("query credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+func (r *SQLiteCredentialRepository) RecordFailedLogin(ctx context.Context, userID string, policy entities.LockoutPolicy, now time.Time) (*entities.Credentials, error) {
+	credentials, err := scanCredentials(r.db.QueryRowContext(ctx, `
+        UPDATE user_credentials SET
+            failed_attempts = CASE WHEN ? > 0 AND failed_attempts + 1 >= ? THEN 0 ELSE failed_attempts + 1 END,
+            locked_until = CASE WHEN ? > 0 AND failed_attempts + 1 >= ? THEN ? ELSE locked_until END
+        WHERE user_id = ?
+        RETURNING `+credentialColumns+`
+    `, policy.MaxFailedAttempts, policy.MaxFailedAttempts, policy.MaxFailedAttempts, policy.MaxFailedAttempts,
+		sqliteTime(now.Add(policy.LockoutDuration)), userID))
+	if err != nil {
+		return nil, fmt.Errorf("record failed login: %w", err)
+	}
+	return credentials, nil
+}
+
+func (r *SQLiteCredentialRepository) ResetFailedLogins(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE user_credentials SET failed_attempts = 0, locked_until = NULL
+        WHERE user_id = ?
+    `, userID)
+	if err != nil {
+		return fmt.Errorf("reset failed logins: %w", err)
+	}
+	return nil
+}
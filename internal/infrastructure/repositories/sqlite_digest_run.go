@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+)
+
+type SQLiteDigestRunRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteDigestRunRepository(db *sql.DB) ports.DigestRunRepository {
+	return &SQLiteDigestRunRepository{db: db}
+}
+
+func (r *SQLiteDigestRunRepository) Claim(ctx context.Context, day time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+        INSERT INTO digest_runs (day, started_at)
+        VALUES (?, ?)
+        ON CONFLICT (day) DO NOTHING
+    `, day.UTC().Format(time.DateOnly), sqliteTime(time.Now()))
+	if err != nil {
+		return false, fmt.Errorf("claim digest run: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim digest run: %w", err)
+	}
+	return claimed > 0, nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SQLiteIdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteIdempotencyRepository(db *sql.DB) ports.IdempotencyRepository {
+	return &SQLiteIdempotencyRepository{db: db}
+}
+
+func (r *SQLiteIdempotencyRepository) Reserve(ctx context.Context, record *entities.IdempotencyRecord, now time.Time) (*entities.IdempotencyRecord, error) {
+	// The write takes the database lock, so reading the live record in the
+	// same transaction cannot race with a concurrent delete.
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// An expired record is taken over; a live one is left alone.
+	result, err := tx.ExecContext(ctx, `
+        INSERT INTO idempotency_keys (user_id, key, owner, request_hash, created_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON CONFLICT (user_id, key) DO UPDATE SET
+            owner = excluded.owner,
+            request_hash = excluded.request_hash,
+            status_code = 0,
+            content_type = '',
+            body = NULL,
+            created_at = excluded.created_at,
+            expires_at = excluded.expires_at
+        WHERE idempotency_keys.expires_at <= ?
+    `, record.UserID, record.Key, record.Owner, record.RequestHash, sqliteTime(record.CreatedAt), sqliteTime(record.ExpiresAt), sqliteTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	reserved, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if reserved > 0 {
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit transaction: %w", err)
+		}
+		return nil, nil
+	}
+
+	existing := &entities.IdempotencyRecord{}
+	err = tx.QueryRowContext(ctx, `
+        SELECT key, user_id, owner, request_hash, status_code, content_type, body, created_at, expires_at
+        FROM idempotency_keys
+        WHERE user_id = ? AND key = ?
+    `, record.UserID, record.Key).Scan(
+		&existing.Key,
+		&existing.UserID,
+		&existing.Owner,
+		&existing.RequestHash,
+		&existing.StatusCode,
+		&existing.ContentType,
+		&existing.Body,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return existing, nil
+}
+
+func (r *SQLiteIdempotencyRepository) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE idempotency_keys
+        SET status_code = ?, content_type = ?, body = ?, expires_at = ?
+        WHERE user_id = ? AND key = ? AND owner = ?
+    `, record.StatusCode, record.ContentType, record.Body, sqliteTime(record.ExpiresAt), record.UserID, record.Key, record.Owner)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteIdempotencyRepository) Release(ctx context.Context, record *entities.IdempotencyRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+        DELETE FROM idempotency_keys WHERE user_id = ? AND key = ? AND owner = ? AND status_code = 0
+    `, record.UserID, record.Key, record.Owner)
+	if err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, sqliteTime(now))
+	if err != nil {
+		return fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// SQLiteMigrationRepository runs the SQLite migration set against a single
+// database file. SQLite deployments run one instance, so the schema lock is
+// held in process.
+type SQLiteMigrationRepository struct {
+	migrationSet
+	db   *sql.DB
+	lock sync.Mutex
+}
+
+func NewSQLiteMigrationRepository(db *sql.DB, source fs.FS) (ports.MigrationRepository, error) {
+	set, err := newMigrationSet(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteMigrationRepository{
+		migrationSet: set,
+		db:           db,
+	}, nil
+}
+
+func (r *SQLiteMigrationRepository) MigrateTo(ctx context.Context, version uint) error {
+	return r.withMigrate(func(m *migrate.Migrate) error {
+		var err error
+		if version == 0 {
+			err = m.Down()
+		} else {
+			err = m.Migrate(version)
+		}
+		if err != nil && err != migrate.ErrNoChange {
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteMigrationRepository) Force(ctx context.Context, version int) error {
+	return r.withMigrate(func(m *migrate.Migrate) error {
+		return m.Force(version)
+	})
+}
+
+func (r *SQLiteMigrationRepository) GetCurrentVersion(ctx context.Context) (uint, bool, error) {
+	var tables int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'
+    `).Scan(&tables)
+	if err != nil {
+		return 0, false, fmt.Errorf("query migration table: %w", err)
+	}
+	if tables == 0 {
+		return 0, false, nil
+	}
+
+	var version int64
+	var dirty bool
+	err = r.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("query migration version: %w", err)
+	}
+	if version < 0 {
+		return 0, dirty, nil
+	}
+	return uint(version), dirty, nil
+}
+
+func (r *SQLiteMigrationRepository) Plan(ctx context.Context, version uint) ([]ports.MigrationStep, error) {
+	current, _, err := r.GetCurrentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.plan(current, version)
+}
+
+func (r *SQLiteMigrationRepository) Lock(ctx context.Context) (func(), error) {
+	r.lock.Lock()
+	return r.lock.Unlock, nil
+}
+
+func (r *SQLiteMigrationRepository) withMigrate(fn func(*migrate.Migrate) error) error {
+	src, err := iofs.New(r.source, ".")
+	if err != nil {
+		return fmt.Errorf("open migrations: %w", err)
+	}
+	// Closing the migrator would close the shared pool as well, so only the
+	// source is released.
+	defer src.Close()
+
+	driver, err := sqlite.WithInstance(r.db, &sqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("create migrator: %w", err)
+	}
+
+	return fn(m)
+}
@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const prColumns = `id, name, author_id, status, created_at, merged_at`
+
+type SQLitePRRepository struct {
+	db *sql.DB
+}
+
+func NewSQLitePRRepository(db *sql.DB) ports.PRRepository {
+	return &SQLitePRRepository{db: db}
+}
+
+func (r *SQLitePRRepository) Save(ctx context.Context, pr *entities.PullRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveSQLitePR(ctx, tx, pr); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLitePRRepository) SaveWithDecision(ctx context.Context, pr *entities.PullRequest, decision *entities.AssignmentDecision) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveSQLitePR(ctx, tx, pr); err != nil {
+		return err
+	}
+	if err := insertSQLiteAssignmentDecision(ctx, tx, decision); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func saveSQLitePR(ctx context.Context, tx *sql.Tx, pr *entities.PullRequest) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO pull_requests (`+prColumns+`)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON CONFLICT (id) DO UPDATE SET
+            status = excluded.status,
+            merged_at = excluded.merged_at
+    `, pr.ID, pr.Name, pr.AuthorID, pr.Status.String(), sqliteTime(pr.CreatedAt), sqliteTimePtr(pr.MergedAt))
+	if err != nil {
+		return fmt.Errorf("insert pr: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM pull_request_reviewers WHERE pull_request_id = ?
+    `, pr.ID)
+	if err != nil {
+		return fmt.Errorf("delete reviewers: %w", err)
+	}
+
+	for _, reviewer := range pr.AssignedReviewers {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
+            VALUES (?, ?)
+        `, pr.ID, reviewer)
+		if err != nil {
+			return fmt.Errorf("insert reviewer: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM pull_request_labels WHERE pull_request_id = ?
+    `, pr.ID)
+	if err != nil {
+		return fmt.Errorf("delete labels: %w", err)
+	}
+
+	for _, label := range pr.Labels {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO pull_request_labels (pull_request_id, label)
+            VALUES (?, ?)
+        `, pr.ID, label)
+		if err != nil {
+			return fmt.Errorf("insert label: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *SQLitePRRepository) GetByID(ctx context.Context, id string) (*entities.PullRequest, error) {
+	pr, err := scanPR(r.db.QueryRowContext(ctx, `
+        SELECT `+prColumns+`
+        FROM pull_requests
+        WHERE id = ?
+    `, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query pr: %w", err)
+	}
+
+	if err := r.attachRelations(ctx, []*entities.PullRequest{pr}); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (r *SQLitePRRepository) ExistsByID(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+        SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = ?)
+    `, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("query pr exists: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *SQLitePRRepository) Find(ctx context.Context, query ports.PRQuery) ([]*entities.PullRequest, error) {
+	clauses, args := sqliteDialect.prQueryClauses(query)
+	return r.list(ctx, `
+        SELECT pr.id, pr.name, pr.author_id, pr.status, pr.created_at, pr.merged_at
+        `+clauses, args...)
+}
+
+func (r *SQLitePRRepository) Count(ctx context.Context, query ports.PRQuery) (int, error) {
+	clauses, args := sqliteDialect.prCountClauses(query)
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) `+clauses, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count prs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLitePRRepository) ListOpen(ctx context.Context) ([]*entities.PullRequest, error) {
+	return r.list(ctx, `
+        SELECT `+prColumns+`
+        FROM pull_requests
+        WHERE status = ?
+        ORDER BY created_at, id
+    `, entities.PRStatusOpen.String())
+}
+
+func (r *SQLitePRRepository) CountOpenByTeam(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT u.team_name, COUNT(*)
+        FROM pull_requests pr
+        JOIN users u ON u.id = pr.author_id
+        WHERE pr.status = ?
+        GROUP BY u.team_name
+    `, entities.PRStatusOpen.String())
+	if err != nil {
+		return nil, fmt.Errorf("count open prs by team: %w", err)
+	}
+	return scanCounts(rows)
+}
+
+func (r *SQLitePRRepository) CountOpenReviews(ctx context.Context, teamName string) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT prr.reviewer_id, COUNT(*)
+        FROM pull_request_reviewers prr
+        JOIN pull_requests pr ON pr.id = prr.pull_request_id
+        JOIN users u ON u.id = prr.reviewer_id
+        WHERE pr.status = ? AND u.team_name = ?
+        GROUP BY prr.reviewer_id
+    `, entities.PRStatusOpen.String(), teamName)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews: %w", err)
+	}
+	return scanCounts(rows)
+}
+
+func (r *SQLitePRRepository) list(ctx context.Context, query string, args ...any) ([]*entities.PullRequest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query prs: %w", err)
+	}
+	defer rows.Close()
+
+	var prs []*entities.PullRequest
+	for rows.Next() {
+		pr, err := scanPR(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan pr: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	if err := r.attachRelations(ctx, prs); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// attachRelations loads assigned reviewers and labels for a batch of pull
+// requests with one query per table.
+func (r *SQLitePRRepository) attachRelations(ctx context.Context, prs []*entities.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(prs))
+	byID := make(map[string]*entities.PullRequest, len(prs))
+	for _, pr := range prs {
+		args = append(args, pr.ID)
+		byID[pr.ID] = pr
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+
+	err := r.attach(ctx, `
+        SELECT pull_request_id, reviewer_id
+        FROM pull_request_reviewers
+        WHERE pull_request_id IN (`+placeholders+`)
+        ORDER BY assigned_at, reviewer_id
+    `, args, func(prID, reviewerID string) {
+		if pr, ok := byID[prID]; ok {
+			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("query reviewers: %w", err)
+	}
+
+	err = r.attach(ctx, `
+        SELECT pull_request_id, label
+        FROM pull_request_labels
+        WHERE pull_request_id IN (`+placeholders+`)
+        ORDER BY label
+    `, args, func(prID, label string) {
+		if pr, ok := byID[prID]; ok {
+			pr.Labels = append(pr.Labels, label)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("query labels: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLitePRRepository) attach(ctx context.Context, query string, args []any, add func(prID, value string)) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID, value string
+		if err := rows.Scan(&prID, &value); err != nil {
+			return err
+		}
+		add(prID, value)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SQLiteRefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRefreshTokenRepository(db *sql.DB) ports.RefreshTokenRepository {
+	return &SQLiteRefreshTokenRepository{db: db}
+}
+
+func (r *SQLiteRefreshTokenRepository) Save(ctx context.Context, token *entities.RefreshToken) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at, revoked_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (id) DO UPDATE SET revoked_at = excluded.revoked_at
+    `, token.ID, token.UserID, token.FamilyID, token.TokenHash,
+		sqliteTime(token.ExpiresAt), sqliteTime(token.CreatedAt), sqliteTimePtr(token.RevokedAt))
+	if err != nil {
+		return fmt.Errorf("save refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	token := &entities.RefreshToken{}
+	err := r.db.QueryRowContext(ctx, `
+        SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at
+        FROM refresh_tokens
+        WHERE token_hash = ?
+    `, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query refresh token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *SQLiteRefreshTokenRepository) Consume(ctx context.Context, tokenHash string, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+        UPDATE refresh_tokens SET revoked_at = ?
+        WHERE token_hash = ? AND revoked_at IS NULL
+    `, sqliteTime(at), tokenHash)
+	if err != nil {
+		return false, fmt.Errorf("consume refresh token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("consume refresh token: %w", err)
+	}
+	return rows == 1, nil
+}
+
+func (r *SQLiteRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE refresh_tokens SET revoked_at = ?
+        WHERE family_id = ? AND revoked_at IS NULL
+    `, sqliteTime(time.Now()), familyID)
+	if err != nil {
+		return fmt.Errorf("revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE refresh_tokens SET revoked_at = ?
+        WHERE user_id = ? AND revoked_at IS NULL
+    `, sqliteTime(time.Now()), userID)
+	if err != nil {
+		return fmt.Errorf("revoke user refresh tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SQLiteTeamRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteTeamRepository(db *sql.DB) ports.TeamRepository {
+	return &SQLiteTeamRepository{db: db}
+}
+
+func (r *SQLiteTeamRepository) Save(ctx context.Context, team *entities.Team) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO teams (name, chat_webhook_url) VALUES (?, ?)
+        ON CONFLICT (name) DO UPDATE SET chat_webhook_url = excluded.chat_webhook_url
+    `, team.Name, team.ChatWebhookURL)
+	if err != nil {
+		return fmt.Errorf("insert team: %w", err)
+	}
+
+	for _, member := range team.Members {
+		_, err = tx.ExecContext(ctx, upsertSQLiteUser, member.ID, member.Username, member.TeamName, member.IsActive, userRole(member), member.ChatHandle, member.Email,
+			member.Notifications.EmailOnAssignment, member.Notifications.EmailDigest, member.OnLeave)
+		if err != nil {
+			return fmt.Errorf("insert user %s: %w", member.ID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteTeamRepository) GetByName(ctx context.Context, name string) (*entities.Team, error) {
+	team := &entities.Team{
+		Name:    name,
+		Members: make([]*entities.User, 0),
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+        SELECT chat_webhook_url FROM teams WHERE name = ?
+    `, name).Scan(&team.ChatWebhookURL)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query team: %w", err)
+	}
+
+	users, err := querySQLiteUsers(ctx, r.db, name)
+	if err != nil {
+		return nil, err
+	}
+	team.Members = append(team.Members, users...)
+
+	return team, nil
+}
+
+func (r *SQLiteTeamRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+        SELECT EXISTS(SELECT 1 FROM teams WHERE name = ?)
+    `, name).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("query team exists: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *SQLiteTeamRepository) List(ctx context.Context) ([]*entities.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT name, chat_webhook_url FROM teams ORDER BY name
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []*entities.Team
+	byName := make(map[string]*entities.Team)
+	for rows.Next() {
+		team := &entities.Team{Members: make([]*entities.User, 0)}
+		if err := rows.Scan(&team.Name, &team.ChatWebhookURL); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		teams = append(teams, team)
+		byName[team.Name] = team
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	users, err := NewSQLiteUserRepository(r.db).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if team, ok := byName[user.TeamName]; ok {
+			team.Members = append(team.Members, user)
+		}
+	}
+
+	return teams, nil
+}
@@ -0,0 +1,253 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/config"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/migrations"
+)
+
+func newSQLiteDB(t *testing.T) (*sql.DB, ports.MigrationRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := config.NewSQLiteConnection(&config.DBConfig{SQLitePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrationRepo, err := repositories.NewSQLiteMigrationRepository(db, migrations.SQLiteFS)
+	if err != nil {
+		t.Fatalf("failed to create migration repository: %v", err)
+	}
+	latest, err := migrationRepo.GetLatestVersion(ctx)
+	if err != nil {
+		t.Fatalf("failed to read latest migration: %v", err)
+	}
+	if err := migrationRepo.MigrateTo(ctx, latest); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db, migrationRepo
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	ctx := context.Background()
+	_, migrationRepo := newSQLiteDB(t)
+
+	latest, _ := migrationRepo.GetLatestVersion(ctx)
+	version, dirty, err := migrationRepo.GetCurrentVersion(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != latest || dirty {
+		t.Fatalf("expected clean version %d, got %d (dirty=%v)", latest, version, dirty)
+	}
+
+	if err := migrationRepo.MigrateTo(ctx, 0); err != nil {
+		t.Fatalf("failed to migrate down: %v", err)
+	}
+	if version, _, _ := migrationRepo.GetCurrentVersion(ctx); version != 0 {
+		t.Errorf("expected version 0 after migrating down, got %d", version)
+	}
+}
+
+func TestSQLiteTeamsUsersAndPullRequests(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newSQLiteDB(t)
+
+	teamRepo := repositories.NewSQLiteTeamRepository(db)
+	userRepo := repositories.NewSQLiteUserRepository(db)
+	prRepo := repositories.NewSQLitePRRepository(db)
+
+	alice := entities.NewUser("u1", "alice", "backend", true)
+	bob := entities.NewUser("u2", "bob", "backend", false)
+	team := entities.NewTeam("backend", []*entities.User{alice, bob})
+	team.ChatWebhookURL = "https://hooks.example.com/backend"
+	if err := teamRepo.Save(ctx, team); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	saved, err := teamRepo.GetByName(ctx, "backend")
+	if err != nil {
+		t.Fatalf("failed to get team: %v", err)
+	}
+	if saved == nil || len(saved.Members) != 2 || saved.ChatWebhookURL != team.ChatWebhookURL {
+		t.Fatalf("unexpected team: %+v", saved)
+	}
+	if exists, _ := teamRepo.ExistsByName(ctx, "backend"); !exists {
+		t.Error("expected team to exist")
+	}
+
+	bob.SetRole(entities.RoleTeamLead)
+	if err := userRepo.Save(ctx, bob); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	user, err := userRepo.GetByID(ctx, "u2")
+	if err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+	if user.IsActive || user.Role != entities.RoleTeamLead {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	pr := entities.NewPullRequest("pr-1", "Add search", "u1", []string{"u2"})
+	if err := prRepo.Save(ctx, pr); err != nil {
+		t.Fatalf("failed to save pr: %v", err)
+	}
+
+	got, err := prRepo.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("failed to get pr: %v", err)
+	}
+	if got.Status != entities.PRStatusOpen || len(got.AssignedReviewers) != 1 || got.MergedAt != nil {
+		t.Fatalf("unexpected pr: %+v", got)
+	}
+	if !got.CreatedAt.Equal(pr.CreatedAt.UTC()) {
+		t.Errorf("expected created_at %v, got %v", pr.CreatedAt, got.CreatedAt)
+	}
+
+	reviews, err := prRepo.Find(ctx, ports.PRQuery{ReviewerID: "u2"})
+	if err != nil {
+		t.Fatalf("failed to get reviews: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].ID != "pr-1" {
+		t.Fatalf("unexpected reviews: %+v", reviews)
+	}
+
+	got.Merge()
+	if err := prRepo.Save(ctx, got); err != nil {
+		t.Fatalf("failed to save merged pr: %v", err)
+	}
+	open, err := prRepo.ListOpen(ctx)
+	if err != nil {
+		t.Fatalf("failed to list open prs: %v", err)
+	}
+	if len(open) != 0 {
+		t.Errorf("expected no open prs, got %d", len(open))
+	}
+}
+
+func TestSQLiteSaveWithDecisionRollsBackPR(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newSQLiteDB(t)
+
+	members := []*entities.User{
+		entities.NewUser("u1", "alice", "backend", true),
+		entities.NewUser("u2", "bob", "backend", true),
+	}
+	if err := repositories.NewSQLiteTeamRepository(db).Save(ctx, entities.NewTeam("backend", members)); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	// A score JSON cannot encode makes the decision fail after the pull
+	// request has been written in the same transaction.
+	prRepo := repositories.NewSQLitePRRepository(db)
+	pr := entities.NewPullRequest("pr-1", "Add search", "u1", []string{"u2"})
+	decision := &entities.AssignmentDecision{
+		PullRequestID: "pr-1",
+		Kind:          entities.AssignmentCreate,
+		Strategy:      "random",
+		Candidates:    []entities.ScoredCandidate{{UserID: "u2", Score: math.NaN()}},
+		Reviewers:     []string{"u2"},
+		CreatedAt:     pr.CreatedAt,
+	}
+	if err := prRepo.SaveWithDecision(ctx, pr, decision); err == nil {
+		t.Fatal("expected saving an unencodable decision to fail")
+	}
+	if exists, err := prRepo.ExistsByID(ctx, "pr-1"); err != nil || exists {
+		t.Errorf("expected pr-1 to be rolled back, exists=%v (%v)", exists, err)
+	}
+}
+
+func TestSQLiteTokenRevocations(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newSQLiteDB(t)
+
+	if err := repositories.NewSQLiteTeamRepository(db).Save(ctx, entities.NewTeam("backend", []*entities.User{
+		entities.NewUser("u1", "alice", "backend", true),
+	})); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	repo := repositories.NewSQLiteTokenRevocationRepository(db)
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	if err := repo.RevokeToken(ctx, "expired", "u1", now.Add(-time.Minute)); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+	if err := repo.RevokeToken(ctx, "live", "u1", now.Add(time.Hour)); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+	if err := repo.RevokeUser(ctx, "u1", now); err != nil {
+		t.Fatalf("failed to revoke user: %v", err)
+	}
+	// An older revocation must not move the cut-off back.
+	if err := repo.RevokeUser(ctx, "u1", now.Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to revoke user: %v", err)
+	}
+
+	denylist, err := repo.Load(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to load denylist: %v", err)
+	}
+	if !denylist.IsRevoked("live", "u1", now.Add(time.Second)) {
+		t.Error("expected live token to be revoked")
+	}
+	if denylist.IsRevoked("expired", "u2", now) {
+		t.Error("expected expired revocation to be skipped")
+	}
+	if denylist.IsRevoked("other", "u1", now.Add(time.Second)) {
+		t.Error("expected tokens issued after the user cut-off to stay valid")
+	}
+	if !denylist.IsRevoked("other", "u1", now.Add(-time.Minute)) {
+		t.Error("expected tokens issued before the user cut-off to be revoked")
+	}
+}
+
+func TestSQLiteAPITokens(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newSQLiteDB(t)
+
+	if err := repositories.NewSQLiteTeamRepository(db).Save(ctx, entities.NewTeam("bots", []*entities.User{
+		entities.NewUser("ci", "ci-bot", "bots", true),
+	})); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	repo := repositories.NewSQLiteAPITokenRepository(db)
+	createdAt := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(24 * time.Hour)
+	token := entities.NewAPIToken("t1", "ci", "ci", "hash", []entities.Scope{entities.ScopePRWrite, entities.ScopeTeamRead}, createdAt, &expiresAt)
+	if err := repo.Save(ctx, token); err != nil {
+		t.Fatalf("failed to save token: %v", err)
+	}
+
+	token.Revoke(createdAt.Add(time.Hour))
+	if err := repo.Save(ctx, token); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+
+	got, err := repo.GetByHash(ctx, "hash")
+	if err != nil {
+		t.Fatalf("failed to get token: %v", err)
+	}
+	if got == nil || !got.HasScope(entities.ScopePRWrite) || !got.HasScope(entities.ScopeTeamRead) {
+		t.Fatalf("unexpected token: %+v", got)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) || !got.IsRevoked() {
+		t.Errorf("unexpected token times: expires=%v revoked=%v", got.ExpiresAt, got.RevokedAt)
+	}
+
+	if missing, err := repo.GetByID(ctx, "nope"); err != nil || missing != nil {
+		t.Errorf("expected no token, got %+v (%v)", missing, err)
+	}
+}
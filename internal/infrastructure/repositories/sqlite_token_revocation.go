@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+type SQLiteTokenRevocationRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteTokenRevocationRepository(db *sql.DB) ports.TokenRevocationRepository {
+	return &SQLiteTokenRevocationRepository{db: db}
+}
+
+func (r *SQLiteTokenRevocationRepository) RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO revoked_tokens (jti, user_id, expires_at)
+        VALUES (?, ?, ?)
+        ON CONFLICT (jti) DO NOTHING
+    `, jti, userID, sqliteTime(expiresAt))
+	if err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteTokenRevocationRepository) RevokeUser(ctx context.Context, userID string, revokedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO user_token_revocations (user_id, revoked_before)
+        VALUES (?, ?)
+        ON CONFLICT (user_id) DO UPDATE SET
+            revoked_before = MAX(user_token_revocations.revoked_before, excluded.revoked_before)
+    `, userID, sqliteTime(revokedAt.Truncate(time.Second)))
+	if err != nil {
+		return fmt.Errorf("revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteTokenRevocationRepository) Load(ctx context.Context, now time.Time) (*entities.TokenDenylist, error) {
+	denylist := entities.NewTokenDenylist()
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT jti, expires_at FROM revoked_tokens WHERE expires_at > ?
+    `, sqliteTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("query revoked tokens: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jti string
+		var expiresAt time.Time
+		if err := rows.Scan(&jti, &expiresAt); err != nil {
+			return nil, fmt.Errorf("scan revoked token: %w", err)
+		}
+		denylist.RevokeToken(jti, expiresAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate revoked tokens: %w", err)
+	}
+
+	userRows, err := r.db.QueryContext(ctx, `
+        SELECT user_id, revoked_before FROM user_token_revocations
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("query user revocations: %w", err)
+	}
+	defer userRows.Close()
+
+	for userRows.Next() {
+		var userID string
+		var revokedBefore time.Time
+		if err := userRows.Scan(&userID, &revokedBefore); err != nil {
+			return nil, fmt.Errorf("scan user revocation: %w", err)
+		}
+		denylist.RevokeUser(userID, revokedBefore)
+	}
+	if err := userRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate user revocations: %w", err)
+	}
+
+	return denylist, nil
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+const upsertSQLiteUser = `
+        INSERT INTO users (` + userColumns + `)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (id) DO UPDATE SET
+            username = excluded.username,
+            team_name = excluded.team_name,
+            is_active = excluded.is_active,
+            role = excluded.role,
+            chat_handle = excluded.chat_handle,
+            email = excluded.email,
+            notify_email_on_assignment = excluded.notify_email_on_assignment,
+            notify_email_digest = excluded.notify_email_digest,
+            on_leave = excluded.on_leave
+    `
+
+type SQLiteUserRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteUserRepository(db *sql.DB) ports.UserRepository {
+	return &SQLiteUserRepository{db: db}
+}
+
+func (r *SQLiteUserRepository) Save(ctx context.Context, user *entities.User) error {
+	_, err := r.db.ExecContext(ctx, upsertSQLiteUser, user.ID, user.Username, user.TeamName, user.IsActive, userRole(user), user.ChatHandle, user.Email,
+		user.Notifications.EmailOnAssignment, user.Notifications.EmailDigest, user.OnLeave)
+	if err != nil {
+		return fmt.Errorf("save user: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteUserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, `
+        SELECT `+userColumns+`
+        FROM users
+        WHERE id = ?
+    `, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *SQLiteUserRepository) ExistsByID(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+        SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)
+    `, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("query user exists: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *SQLiteUserRepository) GetByTeamName(ctx context.Context, teamName string) ([]*entities.User, error) {
+	return querySQLiteUsers(ctx, r.db, teamName)
+}
+
+func (r *SQLiteUserRepository) List(ctx context.Context) ([]*entities.User, error) {
+	return listSQLiteUsers(ctx, r.db, `
+        SELECT `+userColumns+`
+        FROM users
+        ORDER BY id
+    `)
+}
+
+func querySQLiteUsers(ctx context.Context, db *sql.DB, teamName string) ([]*entities.User, error) {
+	return listSQLiteUsers(ctx, db, `
+        SELECT `+userColumns+`
+        FROM users
+        WHERE team_name = ?
+        ORDER BY id
+    `, teamName)
+}
+
+func listSQLiteUsers(ctx context.Context, db *sql.DB, query string, args ...any) ([]*entities.User, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,86 @@
+// Package roster reads team roster documents and renders team sync plans for
+// the sync-teams command and the /admin/teams/sync endpoint.
+package roster
+
+import (
+	"fmt"
+	"io"
+
+	"go.yaml.in/yaml/v2"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+// Document is the roster format. JSON is valid YAML, so the same structure
+// covers both encodings.
+type Document struct {
+	Teams []Team `yaml:"teams"`
+}
+
+type Team struct {
+	Name    string   `yaml:"team_name"`
+	Members []Member `yaml:"members"`
+}
+
+type Member struct {
+	ID       string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	// IsActive defaults to true when omitted.
+	IsActive *bool `yaml:"is_active"`
+}
+
+// Parse decodes a YAML or JSON roster. Unknown fields are rejected so that a
+// typo cannot silently drop a member's settings.
+func Parse(data []byte) ([]*entities.Team, error) {
+	var doc Document
+	if err := yaml.UnmarshalStrict(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidRoster, err)
+	}
+
+	teams := make([]*entities.Team, 0, len(doc.Teams))
+	for _, t := range doc.Teams {
+		team := &entities.Team{Name: t.Name, Members: make([]*entities.User, 0, len(t.Members))}
+		for _, m := range t.Members {
+			isActive := m.IsActive == nil || *m.IsActive
+			team.Members = append(team.Members, entities.NewUser(m.ID, m.Username, t.Name, isActive))
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+// WritePlan prints one line per change.
+func WritePlan(w io.Writer, plan *entities.TeamSyncPlan) {
+	if plan.IsEmpty() {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+	for _, name := range plan.CreateTeams {
+		fmt.Fprintf(w, "create team %s\n", name)
+	}
+	for _, u := range plan.Add {
+		fmt.Fprintf(w, "add        %s (%s) to %s%s\n", u.ID, u.Username, u.TeamName, inactive(u))
+	}
+	for _, m := range plan.Move {
+		fmt.Fprintf(w, "move       %s (%s) from %s to %s%s\n", m.User.ID, m.User.Username, m.FromTeam, m.User.TeamName, inactive(m.User))
+	}
+	for _, u := range plan.Update {
+		fmt.Fprintf(w, "update     %s (%s) in %s\n", u.ID, u.Username, u.TeamName)
+	}
+	for _, u := range plan.Deactivate {
+		fmt.Fprintf(w, "deactivate %s (%s) in %s\n", u.ID, u.Username, u.TeamName)
+	}
+	for _, u := range plan.Remove {
+		fmt.Fprintf(w, "remove     %s (%s) from %s\n", u.ID, u.Username, u.TeamName)
+	}
+	for _, name := range plan.DeleteTeams {
+		fmt.Fprintf(w, "delete team %s\n", name)
+	}
+}
+
+func inactive(u *entities.User) string {
+	if u.IsActive {
+		return ""
+	}
+	return ", inactive"
+}
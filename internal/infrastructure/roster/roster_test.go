@@ -0,0 +1,73 @@
+package roster
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+)
+
+func TestParseAcceptsYAMLAndJSON(t *testing.T) {
+	documents := map[string]string{
+		"yaml": `
+teams:
+  - team_name: backend
+    members:
+      - user_id: u1
+        username: alice
+      - user_id: u2
+        username: bob
+        is_active: false
+`,
+		"json": `{"teams": [{"team_name": "backend", "members": [
+			{"user_id": "u1", "username": "alice"},
+			{"user_id": "u2", "username": "bob", "is_active": false}
+		]}]}`,
+	}
+
+	for name, doc := range documents {
+		t.Run(name, func(t *testing.T) {
+			teams, err := Parse([]byte(doc))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(teams) != 1 || teams[0].Name != "backend" || len(teams[0].Members) != 2 {
+				t.Fatalf("unexpected teams: %+v", teams)
+			}
+			alice, bob := teams[0].Members[0], teams[0].Members[1]
+			if alice.ID != "u1" || alice.Username != "alice" || alice.TeamName != "backend" || !alice.IsActive {
+				t.Errorf("unexpected member: %+v", alice)
+			}
+			if bob.IsActive {
+				t.Errorf("expected bob to be inactive")
+			}
+		})
+	}
+}
+
+func TestParseRejectsUnknownFields(t *testing.T) {
+	_, err := Parse([]byte("teams:\n  - team_name: backend\n    members:\n      - id: u1\n"))
+	if !errors.Is(err, entities.ErrInvalidRoster) {
+		t.Errorf("expected ErrInvalidRoster, got %v", err)
+	}
+}
+
+func TestWritePlan(t *testing.T) {
+	plan := &entities.TeamSyncPlan{
+		CreateTeams: []string{"frontend"},
+		Add:         []*entities.User{entities.NewUser("u4", "dan", "frontend", false)},
+		Move:        []entities.UserMove{{User: entities.NewUser("u1", "alice", "frontend", true), FromTeam: "backend"}},
+		Remove:      []*entities.User{entities.NewUser("u3", "carol", "backend", true)},
+	}
+
+	var out bytes.Buffer
+	WritePlan(&out, plan)
+	want := "create team frontend\n" +
+		"add        u4 (dan) to frontend, inactive\n" +
+		"move       u1 (alice) from backend to frontend\n" +
+		"remove     u3 (carol) from backend\n"
+	if out.String() != want {
+		t.Errorf("unexpected plan:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
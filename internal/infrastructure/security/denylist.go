@@ -0,0 +1,105 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/ports"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+)
+
+// Denylist keeps revoked access tokens in memory so they can be checked on
+// every request. Revocations are written through to the repository and
+// reloaded periodically to pick up those made by other instances.
+type Denylist struct {
+	repo             ports.TokenRevocationRepository
+	refreshTokenRepo ports.RefreshTokenRepository
+	clock            services.Clock
+	logger           *slog.Logger
+
+	mu     sync.RWMutex
+	cached *entities.TokenDenylist
+}
+
+func NewDenylist(
+	repo ports.TokenRevocationRepository,
+	refreshTokenRepo ports.RefreshTokenRepository,
+	clock services.Clock,
+	logger *slog.Logger,
+) *Denylist {
+	return &Denylist{
+		repo:             repo,
+		refreshTokenRepo: refreshTokenRepo,
+		clock:            clock,
+		logger:           logger,
+		cached:           entities.NewTokenDenylist(),
+	}
+}
+
+func (d *Denylist) IsRevoked(jti, userID string, issuedAt time.Time) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.cached.IsRevoked(jti, userID, issuedAt)
+}
+
+func (d *Denylist) RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	if err := d.repo.RevokeToken(ctx, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.cached.RevokeToken(jti, expiresAt)
+	d.mu.Unlock()
+	return nil
+}
+
+// RevokeUserSessions invalidates every access token issued to the user so far
+// and all of their refresh tokens.
+func (d *Denylist) RevokeUserSessions(ctx context.Context, userID string) error {
+	now := d.clock.Now().UTC()
+	if err := d.repo.RevokeUser(ctx, userID, now); err != nil {
+		return err
+	}
+	if err := d.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.cached.RevokeUser(userID, now)
+	d.mu.Unlock()
+	return nil
+}
+
+// Reload replaces the cache with the revocations stored in the repository.
+func (d *Denylist) Reload(ctx context.Context) error {
+	loaded, err := d.repo.Load(ctx, d.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("load token denylist: %w", err)
+	}
+
+	d.mu.Lock()
+	d.cached = loaded
+	d.mu.Unlock()
+	return nil
+}
+
+// Run reloads the cache every interval until ctx is cancelled.
+func (d *Denylist) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Reload(ctx); err != nil {
+				d.logger.Warn("failed to reload token denylist", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package tracing_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/application/commands"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/entities"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/domain/services"
+	apphttp "github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/http"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/notifications"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/tracing"
+)
+
+const inboundTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestTracePropagatesFromRequestToWebhook(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider("pr-reviewer-test", sdktrace.WithSyncer(exporter))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var webhookTraceparent string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookTraceparent = r.Header.Get("traceparent")
+	}))
+	t.Cleanup(webhook.Close)
+
+	userRepo := repositories.NewInMemoryUserRepository()
+	teamRepo := repositories.NewInMemoryTeamRepository(userRepo)
+	members := []*entities.User{
+		entities.NewUser("user1", "alice", "backend", true),
+		entities.NewUser("user2", "bob", "backend", true),
+	}
+	team := entities.NewTeam("backend", members)
+	team.ChatWebhookURL = webhook.URL
+	if err := teamRepo.Save(ctx, team); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	for _, m := range members {
+		if err := userRepo.Save(ctx, m); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+
+	notifier, err := notifications.NewSlackNotifier(notifications.SlackConfig{}, logger)
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+	createPR := commands.NewCreatePRCommand(
+		teamRepo, userRepo, repositories.NewInMemoryPRRepository(userRepo, repositories.NewInMemoryAssignmentDecisionRepository()),
+		services.NewReviewerAssignmentService(nil, 0),
+		notifier, nil, logger,
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /pullRequest/create", apphttp.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := createPR.Execute(r.Context(), "pr-1", "Add search", "user1"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}), apphttp.RouteSpanMiddleware()))
+	mux.Handle("GET /scim/v2/Users/{id}", apphttp.Chain(http.NotFoundHandler(), apphttp.RouteSpanMiddleware()))
+	handler := apphttp.Chain(mux, apphttp.TracingMiddleware(), apphttp.RequestIDMiddleware(logger))
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+	req.Header.Set("traceparent", inboundTraceparent)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/scim/v2/Users/user1", nil)
+	req.Header.Set("traceparent", inboundTraceparent)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	traceID := inboundTraceparent[3:35]
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+		if got := span.SpanContext.TraceID().String(); got != traceID {
+			t.Errorf("span %q: expected trace %s, got %s", span.Name, traceID, got)
+		}
+	}
+
+	server, ok := spans["POST /pullRequest/create"]
+	if !ok {
+		t.Fatalf("expected a server span, got %v", spanNames(spans))
+	}
+	if _, ok := spans["GET /scim/v2/Users/{id}"]; !ok {
+		t.Errorf("expected spans to be named after the route pattern, got %v", spanNames(spans))
+	}
+	command, ok := spans["CreatePRCommand"]
+	if !ok {
+		t.Fatalf("expected a command span, got %v", spanNames(spans))
+	}
+	if command.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Error("expected command span to be a child of the server span")
+	}
+
+	if len(webhookTraceparent) != len(inboundTraceparent) || webhookTraceparent[3:35] != traceID {
+		t.Errorf("expected webhook to receive trace %s, got traceparent %q", traceID, webhookTraceparent)
+	}
+}
+
+func spanNames(spans map[string]tracetest.SpanStub) []string {
+	names := make([]string, 0, len(spans))
+	for name := range spans {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS chat_webhook_url;
+
+ALTER TABLE users DROP COLUMN IF EXISTS chat_handle;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN chat_handle VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE teams ADD COLUMN chat_webhook_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pull_requests_status;
+
+ALTER TABLE users DROP COLUMN IF EXISTS notify_email_digest;
+ALTER TABLE users DROP COLUMN IF EXISTS notify_email_on_assignment;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN notify_email_on_assignment BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN notify_email_digest BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_pull_requests_status ON pull_requests(status);
@@ -0,0 +1,3 @@
+UPDATE users SET is_active = true WHERE id = 'admin-id';
+
+DROP TABLE IF EXISTS user_credentials;
@@ -0,0 +1,13 @@
+CREATE TABLE user_credentials (
+    user_id VARCHAR(255) PRIMARY KEY,
+    password_hash VARCHAR(255) NOT NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- The default admin from 000002 has no password and can no longer log in.
+-- Deactivate it rather than delete it, which would cascade to its pull
+-- requests and reviews. bootstrap-admin re-activates it with a password.
+UPDATE users SET is_active = false WHERE id = 'admin-id';
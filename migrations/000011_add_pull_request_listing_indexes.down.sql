@@ -0,0 +1,4 @@
+CREATE INDEX idx_pull_request_reviewers_reviewer_id ON pull_request_reviewers(reviewer_id);
+DROP INDEX IF EXISTS idx_pull_request_reviewers_reviewer_pr;
+
+DROP INDEX IF EXISTS idx_pull_requests_created_at_id;
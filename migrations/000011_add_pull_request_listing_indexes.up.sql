@@ -0,0 +1,5 @@
+CREATE INDEX idx_pull_requests_created_at_id ON pull_requests(created_at, id);
+
+-- Serves reviewer lookups on its own, so the single-column index goes.
+CREATE INDEX idx_pull_request_reviewers_reviewer_pr ON pull_request_reviewers(reviewer_id, pull_request_id);
+DROP INDEX IF EXISTS idx_pull_request_reviewers_reviewer_id;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pull_requests_name_search;
+DROP INDEX IF EXISTS idx_pull_request_labels_label;
+
+DROP TABLE IF EXISTS pull_request_labels;
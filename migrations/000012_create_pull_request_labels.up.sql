@@ -0,0 +1,11 @@
+CREATE TABLE pull_request_labels (
+    pull_request_id VARCHAR(255) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    PRIMARY KEY (pull_request_id, label),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pull_request_labels_label ON pull_request_labels(label, pull_request_id);
+
+-- Full-text search on pull request names, see PostgresPRRepository.Find.
+CREATE INDEX idx_pull_requests_name_search ON pull_requests USING GIN (to_tsvector('simple', name));
@@ -0,0 +1,15 @@
+CREATE TABLE assignment_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    replaced_reviewer_id VARCHAR(255) NOT NULL DEFAULT '',
+    strategy VARCHAR(50) NOT NULL,
+    seed BIGINT NOT NULL,
+    candidates JSONB NOT NULL,
+    excluded JSONB NOT NULL,
+    reviewers JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_assignment_decisions_pull_request_id ON assignment_decisions(pull_request_id, created_at, id);
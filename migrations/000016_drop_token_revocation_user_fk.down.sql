@@ -0,0 +1,5 @@
+DELETE FROM user_token_revocations WHERE user_id NOT IN (SELECT id FROM users);
+
+ALTER TABLE user_token_revocations
+    ADD CONSTRAINT user_token_revocations_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+-- A session cut-off must outlive the user: deleting a deprovisioned user
+-- would otherwise drop it and revive their access tokens.
+ALTER TABLE user_token_revocations DROP CONSTRAINT IF EXISTS user_token_revocations_user_id_fkey;
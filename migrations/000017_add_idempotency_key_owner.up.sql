@@ -0,0 +1 @@
+ALTER TABLE idempotency_keys ADD COLUMN owner VARCHAR(64) NOT NULL DEFAULT '';
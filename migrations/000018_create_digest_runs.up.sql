@@ -0,0 +1,4 @@
+CREATE TABLE digest_runs (
+    day DATE PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,19 @@
+// Package migrations bundles the SQL schema migrations into the binary.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS holds the Postgres migrations.
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLiteFS holds the migrations for the SQLite backend, which keeps its own
+// version sequence.
+var SQLiteFS, _ = fs.Sub(sqliteFiles, "sqlite")
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS user_token_revocations;
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS user_credentials;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS pull_request_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
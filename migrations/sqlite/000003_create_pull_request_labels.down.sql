@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pull_request_labels_label;
+
+DROP TABLE IF EXISTS pull_request_labels;
@@ -0,0 +1,8 @@
+CREATE TABLE pull_request_labels (
+    pull_request_id TEXT NOT NULL,
+    label TEXT NOT NULL,
+    PRIMARY KEY (pull_request_id, label),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pull_request_labels_label ON pull_request_labels(label, pull_request_id);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN on_leave BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+CREATE TABLE assignment_decisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    replaced_reviewer_id TEXT NOT NULL DEFAULT '',
+    strategy TEXT NOT NULL,
+    seed INTEGER NOT NULL,
+    candidates TEXT NOT NULL,
+    excluded TEXT NOT NULL,
+    reviewers TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_assignment_decisions_pull_request_id ON assignment_decisions(pull_request_id, created_at, id);
@@ -0,0 +1,13 @@
+CREATE TABLE user_token_revocations_old (
+    user_id TEXT PRIMARY KEY,
+    revoked_before TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO user_token_revocations_old (user_id, revoked_before)
+SELECT user_id, revoked_before FROM user_token_revocations
+WHERE user_id IN (SELECT id FROM users);
+
+DROP TABLE user_token_revocations;
+
+ALTER TABLE user_token_revocations_old RENAME TO user_token_revocations;
@@ -0,0 +1,14 @@
+-- A session cut-off must outlive the user: deleting a deprovisioned user
+-- would otherwise drop it and revive their access tokens. SQLite cannot drop
+-- a constraint, so the table is rebuilt.
+CREATE TABLE user_token_revocations_new (
+    user_id TEXT PRIMARY KEY,
+    revoked_before TIMESTAMP NOT NULL
+);
+
+INSERT INTO user_token_revocations_new (user_id, revoked_before)
+SELECT user_id, revoked_before FROM user_token_revocations;
+
+DROP TABLE user_token_revocations;
+
+ALTER TABLE user_token_revocations_new RENAME TO user_token_revocations;
@@ -21,7 +21,8 @@ func TestLogin(t *testing.T) {
 
 	t.Run("Successful login", func(t *testing.T) {
 		loginData := map[string]string{
-			"user_id": "test-admin",
+			"user_id":  "test-admin",
+			"password": testutils.TestAdminPassword,
 		}
 
 		body, _ := json.Marshal(loginData)
@@ -47,7 +48,8 @@ func TestLogin(t *testing.T) {
 
 	t.Run("Login with non-existent user", func(t *testing.T) {
 		loginData := map[string]string{
-			"user_id": "non-existent-user",
+			"user_id":  "non-existent-user",
+			"password": testutils.TestAdminPassword,
 		}
 
 		body, _ := json.Marshal(loginData)
@@ -57,8 +59,26 @@ func TestLogin(t *testing.T) {
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusNotFound {
-			t.Errorf("Expected status 404 for non-existent user, got %d", resp.StatusCode)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for non-existent user, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Login with wrong password", func(t *testing.T) {
+		loginData := map[string]string{
+			"user_id":  "test-admin",
+			"password": "wrong-password",
+		}
+
+		body, _ := json.Marshal(loginData)
+		resp, err := http.Post(ts.URL+"/login", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for wrong password, got %d", resp.StatusCode)
 		}
 	})
 }
@@ -90,7 +90,8 @@ func createTestUsers(t *testing.T, db *sql.DB) {
 
 func getAuthToken(t *testing.T, baseURL string) string {
 	loginData := map[string]string{
-		"user_id": "test-admin",
+		"user_id":  "test-admin",
+		"password": testutils.TestAdminPassword,
 	}
 
 	body, _ := json.Marshal(loginData)
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories/repositorytest"
+	"github.com/KKittyCatik/redesigned-umbrella/tests/testutils"
+)
+
+func TestPostgresContract(t *testing.T) {
+	testDB := testutils.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		_, err := testDB.DB.Exec(`
+            TRUNCATE teams, users, pull_requests, pull_request_reviewers, pull_request_labels, refresh_tokens,
+                user_credentials, api_tokens, revoked_tokens, user_token_revocations, idempotency_keys, assignment_decisions, digest_runs CASCADE
+        `)
+		if err != nil {
+			t.Fatalf("failed to reset tables: %v", err)
+		}
+
+		db := testDB.DB
+		return repositorytest.Repositories{
+			Teams:         repositories.NewPostgresTeamRepository(db),
+			Users:         repositories.NewPostgresUserRepository(db),
+			PRs:           repositories.NewPostgresPRRepository(db),
+			Credentials:   repositories.NewPostgresCredentialRepository(db),
+			RefreshTokens: repositories.NewPostgresRefreshTokenRepository(db),
+			APITokens:     repositories.NewPostgresAPITokenRepository(db),
+			Revocations:   repositories.NewPostgresTokenRevocationRepository(db),
+			Idempotency:   repositories.NewPostgresIdempotencyRepository(db),
+			Decisions:     repositories.NewPostgresAssignmentDecisionRepository(db),
+			DigestRuns:    repositories.NewPostgresDigestRunRepository(db),
+			TeamSync:      repositories.NewPostgresTeamSyncRepository(db),
+		}
+	})
+}
@@ -7,12 +7,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/KKittyCatik/redesigned-umbrella/internal/infrastructure/repositories"
+	"github.com/KKittyCatik/redesigned-umbrella/migrations"
 	_ "github.com/lib/pq"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// TestAdminPassword is the password seeded for the test-admin user.
+const TestAdminPassword = "test-admin-password"
+
 type TestDB struct {
 	DB        *sql.DB
 	Container testcontainers.Container
@@ -80,45 +86,19 @@ func (tdb *TestDB) Cleanup(t *testing.T) {
 }
 
 func runMigrations(db *sql.DB) error {
-	migrations := []string{
-		`CREATE TABLE IF NOT EXISTS teams (
-			name VARCHAR(255) PRIMARY KEY,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		`CREATE TABLE IF NOT EXISTS users (
-			id VARCHAR(255) PRIMARY KEY,
-			username VARCHAR(255) NOT NULL,
-			team_name VARCHAR(255) NOT NULL,
-			is_active BOOLEAN DEFAULT true,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE
-		)`,
-
-		`CREATE TABLE IF NOT EXISTS pull_requests (
-			id VARCHAR(255) PRIMARY KEY,
-			pull_request_name VARCHAR(255) NOT NULL,
-			author_id VARCHAR(255) NOT NULL,
-			status VARCHAR(50) NOT NULL DEFAULT 'open',
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			merged_at TIMESTAMP,
-			FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
-		)`,
-
-		`CREATE TABLE IF NOT EXISTS pull_request_reviewers (
-			pull_request_id VARCHAR(255) NOT NULL,
-			reviewer_id VARCHAR(255) NOT NULL,
-			assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			PRIMARY KEY (pull_request_id, reviewer_id),
-			FOREIGN KEY (pull_request_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
-			FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE CASCADE
-		)`,
-
-		`CREATE INDEX IF NOT EXISTS idx_users_team_name ON users(team_name)`,
-		`CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests(author_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests(status)`,
-		`CREATE INDEX IF NOT EXISTS idx_pull_request_reviewers_reviewer_id ON pull_request_reviewers(reviewer_id)`,
+	migrationRepo, err := repositories.NewPostgresMigrationRepository(db, migrations.FS)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %v", err)
+	}
+	latest, err := migrationRepo.GetLatestVersion(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get latest migration: %v", err)
+	}
+	if err := migrationRepo.MigrateTo(context.Background(), latest); err != nil {
+		return fmt.Errorf("failed to run migrations: %v", err)
+	}
 
+	seeds := []string{
 		`INSERT INTO teams (name) VALUES 
 			('backend-team'),
 			('frontend-team'),
@@ -126,21 +106,33 @@ func runMigrations(db *sql.DB) error {
 			('admin-team')
 		ON CONFLICT (name) DO NOTHING`,
 
-		`INSERT INTO users (id, username, team_name, is_active) VALUES 
-		 	('test-admin', 'admin', 'admin-team', true),
-			('test-user-1', 'john_backend', 'backend-team', true),
-			('test-user-2', 'jane_backend', 'backend-team', true),
-			('test-user-3', 'bob_frontend', 'frontend-team', true),
-			('test-user-4', 'alice_mobile', 'mobile-team', true),
-			('test-user-5', 'charlie_backend', 'backend-team', false)
+		`INSERT INTO users (id, username, team_name, is_active, role) VALUES 
+		 	('test-admin', 'admin', 'admin-team', true, 'admin'),
+			('test-user-1', 'john_backend', 'backend-team', true, 'member'),
+			('test-user-2', 'jane_backend', 'backend-team', true, 'member'),
+			('test-user-3', 'bob_frontend', 'frontend-team', true, 'member'),
+			('test-user-4', 'alice_mobile', 'mobile-team', true, 'member'),
+			('test-user-5', 'charlie_backend', 'backend-team', false, 'member')
 		ON CONFLICT (id) DO NOTHING`,
 	}
 
-	for _, migration := range migrations {
-		if _, err := db.Exec(migration); err != nil {
-			return fmt.Errorf("failed to run migration: %v\nSQL: %s", err, migration)
+	for _, seed := range seeds {
+		if _, err := db.Exec(seed); err != nil {
+			return fmt.Errorf("failed to seed test data: %v\nSQL: %s", err, seed)
 		}
 	}
 
+	hash, err := bcrypt.GenerateFromPassword([]byte(TestAdminPassword), bcrypt.MinCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash test admin password: %v", err)
+	}
+	_, err = db.Exec(
+		"INSERT INTO user_credentials (user_id, password_hash) VALUES ($1, $2) ON CONFLICT (user_id) DO NOTHING",
+		"test-admin", string(hash),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to seed test admin credentials: %v", err)
+	}
+
 	return nil
 }